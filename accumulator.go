@@ -0,0 +1,76 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+// Accumulator is a running sum of points, for long chains of additions and
+// doublings. It keeps the sum in the intermediate representation the group
+// operations produce, and converts it back to a Point only when Point is
+// called.
+//
+// This saves work only for doublings, and for additions and subtractions of an
+// AffinePoint. Adding a Point would need the same conversions as Point.Add,
+// so to add a Point only a few times, use Point.Add on the result of Point.
+//
+// Each method runs in constant time. The zero value is not valid; use
+// NewAccumulator.
+type Accumulator struct {
+	sum projP1xP1
+}
+
+// NewAccumulator returns a new Accumulator with the value start.
+func NewAccumulator(start *Point) *Accumulator {
+	checkInitialized(start)
+	// (X:Y:Z:T) with T = Z maps back to (xz:yz:z²:xy), which is start.
+	a := &Accumulator{}
+	a.sum.X.Set(&start.x)
+	a.sum.Y.Set(&start.y)
+	a.sum.Z.Set(&start.z)
+	a.sum.T.Set(&start.z)
+	return a
+}
+
+// AddAffine sets the value of a to a + p, and returns a.
+func (a *Accumulator) AddAffine(p *AffinePoint) *Accumulator {
+	a.sum.AddAffine((&Point{}).fromP1xP1(&a.sum), &p.cached)
+	return a
+}
+
+// SubAffine sets the value of a to a - p, and returns a.
+func (a *Accumulator) SubAffine(p *AffinePoint) *Accumulator {
+	a.sum.SubAffine((&Point{}).fromP1xP1(&a.sum), &p.cached)
+	return a
+}
+
+// Double sets the value of a to 2 * a, and returns a.
+func (a *Accumulator) Double() *Accumulator {
+	a.sum.Double((&projP2{}).FromP1xP1(&a.sum))
+	return a
+}
+
+// Point returns the value of a as a new Point. It does not change a, which can
+// be used for further operations afterwards.
+func (a *Accumulator) Point() *Point {
+	return (&Point{}).fromP1xP1(&a.sum)
+}
+
+// AffinePoint is a point with its coordinates divided through by Z, which
+// makes adding it to an Accumulator cheaper, for points that are added many
+// times.
+//
+// An AffinePoint is immutable once built, and is safe for concurrent use.
+type AffinePoint struct {
+	cached affineCached
+}
+
+// NewAffinePoint returns a new AffinePoint for q.
+//
+// It involves a field inversion, which costs about as much as fifteen
+// additions, so it pays off only if the result is added many times.
+func NewAffinePoint(q *Point) *AffinePoint {
+	checkInitialized(q)
+	v := &AffinePoint{}
+	v.cached.FromP3(q)
+	return v
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	mathrand "math/rand"
+	"testing"
+)
+
+func TestAccumulator(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	randomPoint := func() *Point {
+		var buf [64]byte
+		rand.Read(buf[:])
+		s := NewScalar().SetUniformBytes(buf[:])
+		return NewIdentityPoint().ScalarBaseMult(s)
+	}
+
+	for trace := 0; trace < 100; trace++ {
+		start := randomPoint()
+		if trace == 0 {
+			start = NewIdentityPoint()
+		}
+		acc := NewAccumulator(start)
+		want := NewIdentityPoint().Set(start)
+
+		for op := 0; op < 20; op++ {
+			p := randomPoint()
+			switch rand.Intn(4) {
+			case 0:
+				acc.AddAffine(NewAffinePoint(p))
+				want.Add(want, p)
+			case 1:
+				acc.SubAffine(NewAffinePoint(p))
+				want.Subtract(want, p)
+			case 2:
+				acc.Double()
+				want.Add(want, want)
+			case 3:
+				// Extracting the value must not change the accumulator.
+				got := acc.Point()
+				checkOnCurve(t, got)
+				if got.Equal(want) != 1 {
+					t.Fatalf("trace %d, op %d: Point() = %x, want %x", trace, op, got.Bytes(), want.Bytes())
+				}
+			}
+		}
+
+		got := acc.Point()
+		checkOnCurve(t, got)
+		if got.Equal(want) != 1 {
+			t.Errorf("trace %d: got %x, want %x", trace, got.Bytes(), want.Bytes())
+		}
+	}
+}
+
+func TestAccumulatorIdentity(t *testing.T) {
+	affineB, affineI := NewAffinePoint(B), NewAffinePoint(I)
+	acc := NewAccumulator(B)
+	acc.SubAffine(affineB).Double().AddAffine(affineI)
+	if acc.Point().Equal(I) != 1 {
+		t.Error("B - B + I != I")
+	}
+	acc.SubAffine(affineI).AddAffine(affineB).SubAffine(affineB)
+	if acc.Point().Equal(I) != 1 {
+		t.Error("I - I + B - B != I")
+	}
+}
+
+const accumulatorBenchPoints = 10000
+
+func accumulatorBenchInputs() []*Point {
+	points := make([]*Point, accumulatorBenchPoints)
+	p := NewGeneratorPoint()
+	for i := range points {
+		points[i] = NewIdentityPoint().Set(p)
+		p.Add(p, B)
+	}
+	return points
+}
+
+func BenchmarkAccumulatorSum(b *testing.B) {
+	points := accumulatorBenchInputs()
+	affinePoints := make([]*AffinePoint, len(points))
+	for i, p := range points {
+		affinePoints[i] = NewAffinePoint(p)
+	}
+
+	b.Run("Point.Add", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sum := NewIdentityPoint()
+			for _, p := range points {
+				sum.Add(sum, p)
+			}
+		}
+	})
+	b.Run("Accumulator.AddAffine", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			acc := NewAccumulator(I)
+			for _, p := range affinePoints {
+				acc.AddAffine(p)
+			}
+			acc.Point()
+		}
+	})
+}
+
+func BenchmarkAccumulatorDouble(b *testing.B) {
+	b.Run("Point.Add", func(b *testing.B) {
+		p := NewGeneratorPoint()
+		for i := 0; i < b.N; i++ {
+			p.Add(p, p)
+		}
+	})
+	b.Run("Accumulator", func(b *testing.B) {
+		acc := NewAccumulator(B)
+		for i := 0; i < b.N; i++ {
+			acc.Double()
+		}
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import "errors"
+
+// AggregateKeys returns the aggregate public key
+//
+//     X = a_0 * X_0 + ... + a_(n-1) * X_(n-1)
+//
+// with a_i = hash(L, X_i), where L is the concatenation of the encodings of
+// all keys in order and X_i is the encoding of keys[i], as in MuSig and MuSig2
+// key aggregation. It also returns the coefficients a_i, with which each
+// signer must multiply its secret key, so that sum(a_i * x_i) is the discrete
+// logarithm of X.
+//
+// The keys are encoded canonically, so different encodings of the same point
+// result in the same coefficients. L depends on the order of the keys, so all
+// parties must agree on it, for example by sorting the encoded keys. Repeated
+// keys are not deduplicated: each occurrence gets the same coefficient, and
+// is added to the aggregate separately.
+//
+// AggregateKeys returns an error if keys is empty, if any key has small order,
+// or if hash returns nil.
+//
+// Execution time depends on the inputs.
+func AggregateKeys(hash func(L, key []byte) *Scalar, keys []*Point) (*Point, []*Scalar, error) {
+	if len(keys) == 0 {
+		return nil, nil, errors.New("edwards25519: no keys to aggregate")
+	}
+	checkInitialized(keys...)
+
+	L := make([]byte, 0, 32*len(keys))
+	for _, k := range keys {
+		if (&Point{}).MultByCofactor(k).varTimeIsIdentity() {
+			return nil, nil, errors.New("edwards25519: small order key")
+		}
+		L = append(L, k.Bytes()...)
+	}
+
+	coeffs := make([]*Scalar, len(keys))
+	for i := range keys {
+		a := hash(L, L[32*i:32*(i+1):32*(i+1)])
+		if a == nil {
+			return nil, nil, errors.New("edwards25519: aggregation coefficient hash failed")
+		}
+		coeffs[i] = NewScalar().Set(a)
+	}
+	return (&Point{}).VarTimeMultiScalarMult(coeffs, keys), coeffs, nil
+}
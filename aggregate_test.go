@@ -0,0 +1,120 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"crypto/sha512"
+	mathrand "math/rand"
+	"testing"
+)
+
+func aggregationHash(L, key []byte) *Scalar {
+	h := sha512.New()
+	h.Write([]byte("KeyAgg coefficient"))
+	h.Write(L)
+	h.Write(key)
+	return NewScalar().SetUniformBytes(h.Sum(nil))
+}
+
+func TestAggregateKeysSignature(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	randomScalar := func() *Scalar {
+		var buf [64]byte
+		rand.Read(buf[:])
+		return NewScalar().SetUniformBytes(buf[:])
+	}
+	secrets := make([]*Scalar, 5)
+	keys := make([]*Point, len(secrets))
+	for i := range secrets {
+		secrets[i] = randomScalar()
+		keys[i] = (&Point{}).ScalarBaseMult(secrets[i])
+	}
+	X, coeffs, err := AggregateKeys(aggregationHash, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coeffs) != len(keys) {
+		t.Fatalf("got %d coefficients, expected %d", len(coeffs), len(keys))
+	}
+
+	// Each signer computes a partial Ed25519 signature with its nonce and
+	// its secret key times its coefficient, and the partial signatures are
+	// summed into a signature under the aggregate key.
+	message := []byte("message")
+	nonces := make([]*Scalar, len(keys))
+	R := NewIdentityPoint()
+	for i := range nonces {
+		nonces[i] = randomScalar()
+		R.Add(R, (&Point{}).ScalarBaseMult(nonces[i]))
+	}
+	h := sha512.New()
+	h.Write(R.Bytes())
+	h.Write(X.Bytes())
+	h.Write(message)
+	k := NewScalar().SetUniformBytes(h.Sum(nil))
+	S := NewScalar()
+	for i := range keys {
+		ax := NewScalar().Multiply(coeffs[i], secrets[i])
+		S.Add(S, NewScalar().MultiplyAdd(k, ax, nonces[i]))
+	}
+	sig := append(R.Bytes(), S.Bytes()...)
+	if !Verify(PublicKeyFromPoint(X), message, sig) {
+		t.Errorf("aggregate signature does not verify")
+	}
+
+	// The coefficients depend on the order of the keys.
+	keys[0], keys[1] = keys[1], keys[0]
+	X2, _, err := AggregateKeys(aggregationHash, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if X2.Equal(X) == 1 {
+		t.Errorf("aggregate key does not depend on the order of the keys")
+	}
+}
+
+func TestAggregateKeysCoefficients(t *testing.T) {
+	keys := []*Point{B, (&Point{}).ScalarBaseMult(&dalekScalar), B}
+	X, coeffs, err := AggregateKeys(aggregationHash, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	L := append(append(B.Bytes(), keys[1].Bytes()...), B.Bytes()...)
+	expected := NewIdentityPoint()
+	for i, k := range keys {
+		a := aggregationHash(L, k.Bytes())
+		if coeffs[i].Equal(a) != 1 {
+			t.Errorf("coefficient %d does not match hash(L, X_%d)", i, i)
+		}
+		expected.Add(expected, (&Point{}).ScalarMult(a, k))
+	}
+	if X.Equal(expected) != 1 {
+		t.Errorf("aggregate key does not match")
+	}
+	// Repeated keys get the same coefficient.
+	if coeffs[0].Equal(coeffs[2]) != 1 {
+		t.Errorf("repeated keys got different coefficients")
+	}
+}
+
+func TestAggregateKeysErrors(t *testing.T) {
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := AggregateKeys(aggregationHash, nil); err == nil {
+		t.Errorf("expected error for no keys")
+	}
+	if _, _, err := AggregateKeys(aggregationHash, []*Point{B, lowOrder}); err == nil {
+		t.Errorf("expected error for small order key")
+	}
+	if _, _, err := AggregateKeys(aggregationHash, []*Point{NewIdentityPoint()}); err == nil {
+		t.Errorf("expected error for identity key")
+	}
+	nilHash := func(L, key []byte) *Scalar { return nil }
+	if _, _, err := AggregateKeys(nilHash, []*Point{B}); err == nil {
+		t.Errorf("expected error for nil coefficient")
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build ed25519_smalltables
+
+package edwards25519
+
+const smallTables = true
+
+// basepointMultAdd is like the default one, but it builds the table of
+// multiples of B at every call, and then uses the same algorithm as
+// ScalarMult, adding p at the end.
+func (v *Point) basepointMultAdd(x *Scalar, p *projCached, identity *Point) *Point {
+	var table projLookupTable
+	table.FromP3(NewGeneratorPoint())
+	digits := x.signedRadix16()
+	v.scalarMultDigits(&digits, &table, identity)
+	wipeDigits(digits[:])
+	if p != nil {
+		tmp := &projP1xP1{}
+		v.fromP1xP1(tmp.Add(v, p))
+	}
+	return v
+}
+
+// basepointNafTableDefault is like the default one, but it builds a width-5
+// table at every call, converting it to affine coordinates with a single
+// batched inversion.
+func basepointNafTableDefault() (uint, []affineCached) {
+	var multiples [8]Point
+	multiples[0].Set(NewGeneratorPoint())
+	var b2 Point
+	b2.Add(&multiples[0], &multiples[0])
+	for i := 0; i < len(multiples)-1; i++ {
+		multiples[i+1].Add(&multiples[i], &b2)
+	}
+
+	var invZ [8]fieldElement
+	for i := range multiples {
+		invZ[i].Set(&multiples[i].z)
+	}
+	invertBatch(invZ[:])
+
+	table := make([]affineCached, len(multiples))
+	for i := range multiples {
+		p, v := &multiples[i], &table[i]
+		v.YplusX.Add(&p.y, &p.x).Multiply(&v.YplusX, &invZ[i])
+		v.YminusX.Subtract(&p.y, &p.x).Multiply(&v.YminusX, &invZ[i])
+		v.T2d.Multiply(&p.t, d2).Multiply(&v.T2d, &invZ[i])
+	}
+	return 5, table
+}
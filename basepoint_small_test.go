@@ -0,0 +1,15 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build ed25519_smalltables
+
+package edwards25519
+
+// With the ed25519_smalltables build tag, the tests that check the
+// precomputed tables of the canonical generator check them against tables
+// computed at runtime instead.
+var (
+	basepointTable    = NewPrecomputedPoint(NewGeneratorPoint()).table
+	basepointNafTable = NewNafTablePoint(NewGeneratorPoint()).table
+)
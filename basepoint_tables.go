@@ -0,0 +1,28 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !ed25519_smalltables
+
+package edwards25519
+
+// smallTables is true if the package was built with the ed25519_smalltables
+// build tag, which replaces the precomputed tables of the canonical generator
+// in table_constants.go, about 37 KiB, with small tables computed at every
+// call. That makes ScalarBaseMult about as slow as ScalarMult, and
+// VarTimeDoubleScalarBaseMult about as slow as VarTimeDoubleScalarMult.
+const smallTables = false
+
+// basepointMultAdd sets v = x * B + p, or v = x * B if p is nil, where B is the
+// canonical generator, and returns v. The accumulator starts from identity,
+// which must be a representation of the identity point.
+func (v *Point) basepointMultAdd(x *Scalar, p *projCached, identity *Point) *Point {
+	return v.fixedBaseMultAdd(&basepointTable, x, p, identity)
+}
+
+// basepointNafTableDefault returns the NAF width and the table of odd
+// multiples of the canonical generator to use for variable-time
+// multiplications, unless EnableLargeBasepointTable was called.
+func basepointNafTableDefault() (uint, []affineCached) {
+	return 8, basepointNafTable.points[:]
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+const footprintProgram = `package main
+
+import "filippo.io/edwards25519"
+
+func main() {
+	x := edwards25519.NewScalar()
+	p := new(edwards25519.Point).ScalarBaseMult(x)
+	p.VarTimeDoubleScalarBaseMult(x, p, x)
+	println(len(p.Bytes()))
+}
+`
+
+// TestSmallTablesFootprint builds a program using ScalarBaseMult and
+// VarTimeDoubleScalarBaseMult with and without the ed25519_smalltables build
+// tag, and reports the size of the two binaries. Run it with -v.
+func TestSmallTablesFootprint(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping binary builds in short mode")
+	}
+	goTool := filepath.Join(runtime.GOROOT(), "bin", "go")
+	if _, err := os.Stat(goTool); err != nil {
+		t.Skip("go tool not available")
+	}
+	pkgDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "edwards25519-footprint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	goMod := "module footprint\n\ngo 1.14\n\nrequire filippo.io/edwards25519 v1.0.0\n\n" +
+		"replace filippo.io/edwards25519 => " + pkgDir + "\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(footprintProgram), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	size := func(tags string) int64 {
+		out := filepath.Join(dir, "footprint-"+tags)
+		cmd := exec.Command(goTool, "build", "-mod=mod", "-tags", tags, "-ldflags=-s -w", "-o", out)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("go build -tags %q: %v\n%s", tags, err, output)
+		}
+		fi, err := os.Stat(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi.Size()
+	}
+	large, small := size("none"), size("ed25519_smalltables")
+	t.Logf("default tables: %d bytes, ed25519_smalltables: %d bytes (%d bytes saved)",
+		large, small, large-small)
+	if small >= large {
+		t.Errorf("ed25519_smalltables binary is not smaller: %d >= %d bytes", small, large)
+	}
+}
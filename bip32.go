@@ -0,0 +1,103 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"errors"
+	"hash"
+)
+
+// The BIP32-Ed25519 functions implement the non-hardened child key derivation
+// of "BIP32-Ed25519: Hierarchical Deterministic Keys over a Non-linear
+// Keyspace" by Khovratovich and Law, where the child of a key (a, A) with
+// chain code c at index i is (a + t, A + t * B), with the tweak t computed by
+// DeriveChildTweak from c, A, and i. Public keys can be derived without the
+// secret key, and the same tweak derives the matching secret scalar.
+//
+// The paper and its implementations represent secret keys as 256-bit
+// integers, and the child key as the integer sum, which can overflow after a
+// very large number of derivations. Here, scalars are always reduced modulo l,
+// so the sum never overflows, and the child key is deterministic. The reduced
+// scalar is equivalent for signing and for deriving public keys, but its
+// encoding is different from the unreduced 32 bytes of other implementations.
+// The nonce prefix of the child key is not derived by these functions.
+
+// DeriveChildTweak computes the tweak t and the child chain code for the
+// non-hardened child at index of the parent public key with chainCode, as
+//
+//     Z = HMAC(chainCode, 0x02 || parent || uint32le(index))
+//     t = 8 * Z[:28]
+//     childChainCode = HMAC(chainCode, 0x03 || parent || uint32le(index))[32:]
+//
+// where HMAC is computed with mac, which must return a new HMAC-SHA-512 hash
+// keyed with its argument, for example
+//
+//     func(key []byte) hash.Hash { return hmac.New(sha512.New, key) }
+//
+// It returns an error if index is 2^31 or higher, which is reserved for
+// hardened derivation, if chainCode is not 32 bytes long, or if mac returns
+// less than 64 bytes.
+func DeriveChildTweak(mac func(key []byte) hash.Hash, chainCode []byte, parent *Point, index uint32) (tweak *Scalar, childChainCode []byte, err error) {
+	if index >= 1<<31 {
+		return nil, nil, errors.New("edwards25519: hardened index for public derivation")
+	}
+	if len(chainCode) != 32 {
+		return nil, nil, errors.New("edwards25519: invalid chain code length")
+	}
+	data := make([]byte, 0, 1+32+4)
+	data = append(data, 0x02)
+	data = append(data, parent.Bytes()...)
+	data = append(data, byte(index), byte(index>>8), byte(index>>16), byte(index>>24))
+
+	h := mac(chainCode)
+	if h.Size() < 64 {
+		return nil, nil, errors.New("edwards25519: HMAC output is too short")
+	}
+	h.Write(data)
+	z := h.Sum(nil)
+
+	// 8 * Z[:28] is lower than 2^227, so it's a canonical scalar.
+	var t [32]byte
+	var carry byte
+	for i := 0; i < 28; i++ {
+		t[i] = z[i]<<3 | carry
+		carry = z[i] >> 5
+	}
+	t[28] = carry
+	tweak, err = NewScalar().SetCanonicalBytes(t[:])
+	if err != nil {
+		panic("edwards25519: internal error: BIP32-Ed25519 tweak is not canonical")
+	}
+
+	data[0] = 0x03
+	h = mac(chainCode)
+	h.Write(data)
+	childChainCode = h.Sum(nil)[32:64]
+	return tweak, childChainCode, nil
+}
+
+// DeriveChildPublic returns parent + tweak * B, the public key of the child
+// derived with tweak. It returns an error if parent or the child has small
+// order.
+//
+// Execution time depends on the inputs.
+func DeriveChildPublic(parent *Point, tweak *Scalar) (*Point, error) {
+	if (&Point{}).MultByCofactor(parent).varTimeIsIdentity() {
+		return nil, errors.New("edwards25519: small order parent public key")
+	}
+	child := (&Point{}).ScalarBaseMultAdd(tweak, parent)
+	if (&Point{}).MultByCofactor(child).varTimeIsIdentity() {
+		return nil, errors.New("edwards25519: small order child public key")
+	}
+	return child, nil
+}
+
+// DeriveChildScalar returns parent + tweak mod l, the secret scalar of the
+// child derived with tweak.
+//
+// The derivation is done in constant time.
+func DeriveChildScalar(parent, tweak *Scalar) *Scalar {
+	return NewScalar().Add(parent, tweak)
+}
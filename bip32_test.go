@@ -0,0 +1,143 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"testing"
+	"testing/quick"
+)
+
+func hmacSHA512(key []byte) hash.Hash { return hmac.New(sha512.New, key) }
+
+func TestDeriveChildInvariant(t *testing.T) {
+	derive := func(a, tweak Scalar) bool {
+		A := (&Point{}).ScalarBaseMult(&a)
+		child, err := DeriveChildPublic(A, &tweak)
+		if err != nil {
+			// Only if A or the child are the identity.
+			return a.Equal(NewScalar()) == 1 ||
+				NewScalar().Add(&a, &tweak).Equal(NewScalar()) == 1
+		}
+		expected := (&Point{}).ScalarBaseMult(DeriveChildScalar(&a, &tweak))
+		return child.Equal(expected) == 1
+	}
+	if err := quick.Check(derive, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestDeriveChildVectors checks vectors of the public child key derivation of
+// the Khovratovich and Law paper, computed with the Python reference code of
+// RFC 8032, Section 6, and Python's hmac module, under Python 3.11. SLIP-0010
+// only defines hardened derivation for Ed25519, so it has no such vectors.
+func TestDeriveChildVectors(t *testing.T) {
+	seed := make([]byte, 32)
+	chainCode := make([]byte, 32)
+	for i := range seed {
+		seed[i] = byte(i)
+		chainCode[i] = byte(32 + i)
+	}
+	a, _, err := ScalarAndPrefixFromSeed(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	A := (&Point{}).ScalarBaseMult(a)
+
+	for _, tt := range []struct {
+		index                             uint32
+		tweak, chainCode, public, private string
+	}{
+		{0,
+			"0064e5395135e377060032a51503184e84e735016bedf95c80d7c6ad07000000",
+			"df4072b2b8ddf987c1f32f7cae4c29a45afb0187674d8e700461eaf098e757c4",
+			"2955b6925302eb63db2b1104847da1b4bcd209bfc43a58443206d94a3fcce6b1",
+			"aa0011b14f3b7f5682bc99490908282621554a4149cc0c4322fdb73227ff8e0f"},
+		{1,
+			"68ba9afcd52927eec096e606cfdb8e6df4310914354add207ec4295901000000",
+			"f24b0be5b915979446916abc5a81011b03338a4c371de9aa2d46770344a3e7aa",
+			"59f705d63aaba947ae10019fae5cfaa9e51bafaaf3897ca9085cb3a9a10cbe96",
+			"1257c673d42fc3cc3c534eabc2e09e45919f1d541329f00620ea1ade20ff8e0f"},
+		{1<<31 - 1,
+			"000dd0d4daaaea1897eb0ffacd133cd0f47b42ec7f61ca7001e3a43407000000",
+			"1eb8e6dd50c8f6b16c6bda9e97bb31072df0bc644e7b82adc61801c8ee5e33ec",
+			"d6f533b14470c8a1430aa31b88f96f12a60608b5052a9bfecb5e7184f93edf00",
+			"aaa9fb4bd9b086f712a8779ec1184ca891e9562c5e40dd56a30896b926ff8e0f"},
+	} {
+		tweak, childChainCode, err := DeriveChildTweak(hmacSHA512, chainCode, A, tt.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		child, err := DeriveChildPublic(A, tweak)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range []struct {
+			name      string
+			got, want []byte
+		}{
+			{"tweak", tweak.Bytes(), decodeHex(tt.tweak)},
+			{"chain code", childChainCode, decodeHex(tt.chainCode)},
+			{"public key", child.Bytes(), decodeHex(tt.public)},
+			{"secret scalar", DeriveChildScalar(a, tweak).Bytes(), decodeHex(tt.private)},
+		} {
+			if !bytes.Equal(v.got, v.want) {
+				t.Errorf("index %d: %s = %x, expected %x", tt.index, v.name, v.got, v.want)
+			}
+		}
+	}
+}
+
+func TestDeriveChildPath(t *testing.T) {
+	// Deriving along a path on the public and private sides in parallel
+	// keeps the keys matching.
+	a := NewScalar().Set(&dalekScalar)
+	A := (&Point{}).ScalarBaseMult(a)
+	chainCode := bytes.Repeat([]byte{0xcc}, 32)
+	for _, index := range []uint32{44, 1815, 0, 0, 7} {
+		tweak, cc, err := DeriveChildTweak(hmacSHA512, chainCode, A, index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		A, err = DeriveChildPublic(A, tweak)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, chainCode = DeriveChildScalar(a, tweak), cc
+		if (&Point{}).ScalarBaseMult(a).Equal(A) != 1 {
+			t.Fatalf("index %d: derived keys do not match", index)
+		}
+	}
+}
+
+func TestDeriveChildErrors(t *testing.T) {
+	chainCode := make([]byte, 32)
+	if _, _, err := DeriveChildTweak(hmacSHA512, chainCode, B, 1<<31); err == nil {
+		t.Errorf("expected error for hardened index")
+	}
+	if _, _, err := DeriveChildTweak(hmacSHA512, chainCode[:31], B, 0); err == nil {
+		t.Errorf("expected error for short chain code")
+	}
+	hmacSHA256 := func(key []byte) hash.Hash { return hmac.New(sha256.New, key) }
+	if _, _, err := DeriveChildTweak(hmacSHA256, chainCode, B, 0); err == nil {
+		t.Errorf("expected error for short HMAC")
+	}
+
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DeriveChildPublic(lowOrder, &dalekScalar); err == nil {
+		t.Errorf("expected error for small order parent")
+	}
+	minusOne := NewScalar().Negate(&scOne)
+	if _, err := DeriveChildPublic(B, minusOne); err == nil {
+		t.Errorf("expected error for identity child")
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"crypto/sha512"
+	"errors"
+	"hash"
+)
+
+// The key blinding functions derive, from a long-term Ed25519 key (a, A) and a
+// blinding factor h, the blinded key (h * a mod l, h * A), as done by Tor v3
+// onion services, specified in rend-spec-v3, Appendix A.2. Signatures by the
+// blinded key verify as regular Ed25519 signatures under the blinded public
+// key, and can't be linked to the long-term key without knowing it.
+//
+// For Tor, the blinding factor is
+//
+//     BlindingFactor(sha3.New256(), []byte("Derive temporary signing key\x00"),
+//         A, s, basepointString, N)
+//
+// and the blinded prefix is BlindPrefix of the long-term prefix.
+
+// BlindingFactor returns the blinding factor derived by hashing params in
+// order with h, and clamping the first 32 bytes of the digest as specified in
+// RFC 8032, Section 5.1.5. h is reset first, and must produce at least 32
+// bytes, or BlindingFactor returns an error.
+func BlindingFactor(h hash.Hash, params ...[]byte) (*Scalar, error) {
+	if h.Size() < 32 {
+		return nil, errors.New("edwards25519: blinding factor hash is too short")
+	}
+	h.Reset()
+	for _, p := range params {
+		h.Write(p)
+	}
+	return NewScalar().SetBytesWithClamping(h.Sum(nil)[:32]), nil
+}
+
+// BlindPublicKey returns h * A, the blinded public key. It returns an error if
+// the result has small order, which happens if A has small order, or if h is
+// zero.
+//
+// The blinding is done in constant time, and the check in variable time.
+func BlindPublicKey(A *Point, h *Scalar) (*Point, error) {
+	p := (&Point{}).ScalarMult(h, A)
+	if (&Point{}).MultByCofactor(p).varTimeIsIdentity() {
+		return nil, errors.New("edwards25519: blinded public key has small order")
+	}
+	return p, nil
+}
+
+// BlindPrivateScalar returns h * a mod l, the secret scalar of the blinded
+// key, so that BlindPrivateScalar(a, h) * B equals BlindPublicKey(a * B, h).
+//
+// The blinding is done in constant time.
+func BlindPrivateScalar(a, h *Scalar) *Scalar {
+	return NewScalar().Multiply(h, a)
+}
+
+// BlindPrefix returns the nonce prefix of the blinded key, derived from the
+// long-term prefix returned by ScalarAndPrefixFromSeed, as the first 32 bytes
+// of SHA-512("Derive temporary signing key hash input" || prefix), like Tor.
+func BlindPrefix(prefix []byte) []byte {
+	h := sha512.New()
+	h.Write([]byte("Derive temporary signing key hash input"))
+	h.Write(prefix)
+	return h.Sum(nil)[:32]
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"testing"
+	"testing/quick"
+)
+
+func TestBlindKeyInvariant(t *testing.T) {
+	blind := func(a, h Scalar) bool {
+		if h.Equal(NewScalar()) == 1 || a.Equal(NewScalar()) == 1 {
+			return true
+		}
+		A := (&Point{}).ScalarBaseMult(&a)
+		blinded, err := BlindPublicKey(A, &h)
+		if err != nil {
+			return false
+		}
+		expected := (&Point{}).ScalarBaseMult(BlindPrivateScalar(&a, &h))
+		return blinded.Equal(expected) == 1
+	}
+	if err := quick.Check(blind, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestBlindKeyVector checks a vector with the Tor construction, except for
+// the use of SHA-512 instead of SHA3-256 for the blinding factor. The expected
+// values were computed with the Python reference code of RFC 8032, Section 6,
+// and the blinding of blindESK and blindPK in Tor's
+// src/test/ed25519_exts_ref.py, under Python 3.11.
+func TestBlindKeyVector(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	a, prefix, err := ScalarAndPrefixFromSeed(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	A := (&Point{}).ScalarBaseMult(a)
+	h, err := BlindingFactor(sha512.New(), []byte("Derive temporary signing key\x00"),
+		A.Bytes(), []byte("period 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blindedA, err := BlindPublicKey(A, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name      string
+		got, want []byte
+	}{
+		{"blinding factor", h.Bytes(), decodeHex("1dd591065723f86d914647280807e22b80fc4abf27f0edf35ba62ccc0946ac00")},
+		{"blinded public key", blindedA.Bytes(), decodeHex("8eaa24fe8b223608e3527d4024b959d360f39c15c51b436e82f556510b90671a")},
+		{"blinded scalar", BlindPrivateScalar(a, h).Bytes(), decodeHex("fbe76668c51efd631730ec774672172e7c08206abf3397cb34813b235f2fb803")},
+		{"blinded prefix", BlindPrefix(prefix), decodeHex("dbb1aaee717bb8359a9f104295474da8572d9c2822a8680a641e4f6494b8689e")},
+	} {
+		if !bytes.Equal(tt.got, tt.want) {
+			t.Errorf("%s = %x, expected %x", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestBlindKeySignature(t *testing.T) {
+	// A signature by the blinded key, computed as in RFC 8032, Section 5.1.6,
+	// with the blinded scalar and prefix, verifies under the blinded public
+	// key with crypto/ed25519.
+	seed := make([]byte, ed25519.SeedSize)
+	a, prefix, err := ScalarAndPrefixFromSeed(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	A := (&Point{}).ScalarBaseMult(a)
+	h, err := BlindingFactor(sha256.New(), A.Bytes(), []byte("epoch"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blindedA, err := BlindPublicKey(A, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blindedScalar, blindedPrefix := BlindPrivateScalar(a, h), BlindPrefix(prefix)
+
+	message := []byte("message")
+	r := sha512.New()
+	r.Write(blindedPrefix)
+	r.Write(message)
+	rScalar := NewScalar().SetUniformBytes(r.Sum(nil))
+	R := (&Point{}).ScalarBaseMult(rScalar)
+	k := sha512.New()
+	k.Write(R.Bytes())
+	k.Write(blindedA.Bytes())
+	k.Write(message)
+	kScalar := NewScalar().SetUniformBytes(k.Sum(nil))
+	S := NewScalar().MultiplyAdd(kScalar, blindedScalar, rScalar)
+	sig := append(R.Bytes(), S.Bytes()...)
+
+	if !ed25519.Verify(PublicKeyFromPoint(blindedA), message, sig) {
+		t.Errorf("signature by the blinded key does not verify")
+	}
+	if ed25519.Verify(PublicKeyFromPoint(A), message, sig) {
+		t.Errorf("signature by the blinded key verifies under the long-term key")
+	}
+}
+
+func TestBlindPublicKeySmallOrder(t *testing.T) {
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BlindPublicKey(lowOrder, &dalekScalar); err == nil {
+		t.Errorf("expected error for small order public key")
+	}
+	if _, err := BlindPublicKey(NewIdentityPoint(), &dalekScalar); err == nil {
+		t.Errorf("expected error for identity public key")
+	}
+	if _, err := BlindPublicKey(B, NewScalar()); err == nil {
+		t.Errorf("expected error for zero blinding factor")
+	}
+	if _, err := BlindingFactor(sha512.New512_224()); err == nil {
+		t.Errorf("expected error for short hash")
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build ctcheck
+
+// Command ctcheck runs the constant-time edwards25519 operations on random
+// secrets, for checking under valgrind that they don't branch on or index
+// memory with secret values. With the ctcheck build tag, the library marks
+// the secrets as undefined memory, so valgrind memcheck reports any such use.
+//
+//     go build -tags ctcheck filippo.io/edwards25519/cmd/ctcheck
+//     valgrind --error-exitcode=1 ./ctcheck
+//
+// Building requires cgo and the valgrind headers. Outside of valgrind, the
+// program just runs the operations.
+package main
+
+import (
+	"crypto/rand"
+	"flag"
+	"fmt"
+
+	"filippo.io/edwards25519"
+)
+
+func main() {
+	iterations := flag.Int("n", 4, "number of iterations")
+	flag.Parse()
+
+	for i := 0; i < *iterations; i++ {
+		x, y := randomScalar(), randomScalar()
+
+		q := edwards25519.NewIdentityPoint().ScalarBaseMult(y)
+		p := edwards25519.NewIdentityPoint().ScalarMult(x, q)
+		r := edwards25519.NewIdentityPoint().MultiScalarMult(
+			[]*edwards25519.Scalar{x, y}, []*edwards25519.Point{q, p})
+
+		s := edwards25519.NewScalar().Multiply(x, y)
+		s.Add(s, x).Subtract(s, y).Negate(s).MultiplyAdd(s, x, y)
+		p.ScalarBaseMult(s)
+
+		// Equal doesn't branch on the points, and its result is public.
+		if p.Equal(r) == 1 {
+			fmt.Println("equal")
+		}
+	}
+}
+
+func randomScalar() *edwards25519.Scalar {
+	var buf [64]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		panic(err)
+	}
+	return edwards25519.NewScalar().SetUniformBytes(buf[:])
+}
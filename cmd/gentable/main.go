@@ -0,0 +1,67 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command gentable generates Go source declaring the precomputed tables for a
+// fixed edwards25519 point, so that they can be embedded in a binary instead
+// of being computed at runtime. It is meant to be used with go:generate:
+//
+//     //go:generate go run filippo.io/edwards25519/cmd/gentable -package foo -point <hex> -precomputed hTable -o h_table.go
+//
+// Each table is a variable initialized with
+// edwards25519.NewPrecomputedPointFromSource or
+// edwards25519.NewNafTablePointFromSource from a string constant holding its
+// binary encoding.
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"filippo.io/edwards25519"
+)
+
+func main() {
+	pkg := flag.String("package", "", "package name of the generated file")
+	point := flag.String("point", "", "hex encoding of the point")
+	precomputed := flag.String("precomputed", "", "name of the PrecomputedPoint variable")
+	naf := flag.String("naf", "", "name of the NafTablePoint variable")
+	out := flag.String("o", "", "output file (default stdout)")
+	flag.Parse()
+
+	if err := run(*pkg, *point, *precomputed, *naf, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "gentable:", err)
+		os.Exit(1)
+	}
+}
+
+func run(pkg, point, precomputed, naf, out string) error {
+	b, err := hex.DecodeString(point)
+	if err != nil {
+		return fmt.Errorf("invalid -point: %v", err)
+	}
+	p, err := new(edwards25519.Point).SetBytes(b)
+	if err != nil {
+		return fmt.Errorf("invalid -point: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := edwards25519.GenerateTableSource(buf, &edwards25519.TableSourceConfig{
+		Package:          pkg,
+		Point:            p,
+		PrecomputedPoint: precomputed,
+		NafTablePoint:    naf,
+	}); err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return ioutil.WriteFile(out, buf.Bytes(), 0644)
+}
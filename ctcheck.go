@@ -0,0 +1,142 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build ctcheck
+
+package edwards25519
+
+// The constant-time entry points mark their secret inputs as poisoned, and
+// their public results as unpoisoned. With the ctcheck build tag, poisoning is
+// a valgrind memcheck client request marking the memory as undefined, so that
+// running under valgrind reports any branch or memory index that depends on a
+// secret, as in Adam Langley's ctgrind. See cmd/ctcheck for a program to run
+// under valgrind.
+//
+// Scalars are poisoned for the duration of Scalar arithmetic. Scalar
+// multiplications poison the digits of the scalar instead, since computing
+// them asserts that the scalar is reduced. Lookup table indices are poisoned
+// in the constant-time SelectInto methods, and Point.Equal poisons both
+// points. On return, the memory poisoned by an entry point is restored to its
+// state on entry, and the results are marked as defined only if all the inputs
+// were, so that memory the caller poisoned stays poisoned and keeps tainting
+// what is computed from it.
+//
+// The client requests are no-ops when not running under valgrind, so a ctcheck
+// build works normally, only a bit slower. Building it requires cgo and the
+// valgrind headers.
+
+import (
+	"unsafe"
+
+	"filippo.io/edwards25519/internal/ctcheck"
+)
+
+// ctcheckEnabled is true if the secret poisoning hooks are enabled. Pointers
+// passed to them escape to the heap, which makes some functions allocate.
+const ctcheckEnabled = true
+
+// ctState is the definedness of the inputs of an entry point, saved on entry.
+type ctState struct {
+	saved   []ctSaved
+	defined bool
+}
+
+// ctSaved is the saved definedness of a memory range poisoned by an entry
+// point.
+type ctSaved struct {
+	p     unsafe.Pointer
+	vbits []byte
+}
+
+func newCTState() *ctState {
+	return &ctState{defined: true}
+}
+
+// check records whether the n bytes at p are defined, and returns their
+// validity bits, or nil if not running under valgrind.
+func (ct *ctState) check(p unsafe.Pointer, n uintptr) []byte {
+	vbits := ctcheck.VBits(p, n)
+	for _, b := range vbits {
+		if b != 0 {
+			ct.defined = false
+		}
+	}
+	return vbits
+}
+
+// poison saves the definedness of the n bytes at p, and marks them as
+// undefined.
+func (ct *ctState) poison(p unsafe.Pointer, n uintptr) {
+	ct.saved = append(ct.saved, ctSaved{p, ct.check(p, n)})
+	ctcheck.Poison(p, n)
+}
+
+// result restores the memory poisoned by ct to its state on entry, and then
+// marks the n bytes at p as defined if all the inputs were, and as undefined
+// otherwise. p is marked last, as it may alias an input.
+func (ct *ctState) result(p unsafe.Pointer, n uintptr) {
+	for i := len(ct.saved) - 1; i >= 0; i-- {
+		ctcheck.SetVBits(ct.saved[i].p, ct.saved[i].vbits)
+	}
+	ct.saved = nil
+	if ct.defined {
+		ctcheck.Unpoison(p, n)
+	} else {
+		ctcheck.Poison(p, n)
+	}
+}
+
+func (ct *ctState) poisonScalars(xs ...*Scalar) {
+	for _, x := range xs {
+		ct.poison(unsafe.Pointer(&x.s), unsafe.Sizeof(x.s))
+	}
+}
+
+func (ct *ctState) poisonPoints(ps ...*Point) {
+	for _, p := range ps {
+		ct.poison(unsafe.Pointer(p), unsafe.Sizeof(*p))
+	}
+}
+
+// poisonDigits poisons the digits of a scalar. They are computed by the entry
+// point, so they are defined only if the scalar was.
+func (ct *ctState) poisonDigits(d []int8) {
+	if len(d) > 0 {
+		ct.poison(unsafe.Pointer(&d[0]), uintptr(len(d)))
+	}
+}
+
+func (ct *ctState) checkPoint(p *Point) {
+	ct.check(unsafe.Pointer(p), unsafe.Sizeof(*p))
+}
+
+func (ct *ctState) checkCached(p *projCached) {
+	if p != nil {
+		ct.check(unsafe.Pointer(p), unsafe.Sizeof(*p))
+	}
+}
+
+func (ct *ctState) checkTable(t *projLookupTable) {
+	ct.check(unsafe.Pointer(t), unsafe.Sizeof(*t))
+}
+
+func (ct *ctState) checkAffineTables(t *[32]affineLookupTable) {
+	ct.check(unsafe.Pointer(t), unsafe.Sizeof(*t))
+}
+
+func (ct *ctState) resultScalar(s *Scalar) {
+	ct.result(unsafe.Pointer(&s.s), unsafe.Sizeof(s.s))
+}
+
+func (ct *ctState) resultPoint(p *Point) {
+	ct.result(unsafe.Pointer(p), unsafe.Sizeof(*p))
+}
+
+func (ct *ctState) resultInt(x *int) {
+	ct.result(unsafe.Pointer(x), unsafe.Sizeof(*x))
+}
+
+func ctPoisonIndex(x *int8) {
+	ctcheck.Poison(unsafe.Pointer(x), unsafe.Sizeof(*x))
+}
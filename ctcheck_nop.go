@@ -0,0 +1,38 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !ctcheck
+
+package edwards25519
+
+// Without the ctcheck build tag, the secret poisoning hooks described in
+// ctcheck.go are empty and compile to nothing.
+
+const ctcheckEnabled = false
+
+type ctState struct{}
+
+func newCTState() *ctState { return nil }
+
+func (ct *ctState) poisonScalars(xs ...*Scalar) {}
+
+func (ct *ctState) poisonPoints(ps ...*Point) {}
+
+func (ct *ctState) poisonDigits(d []int8) {}
+
+func (ct *ctState) checkPoint(p *Point) {}
+
+func (ct *ctState) checkCached(p *projCached) {}
+
+func (ct *ctState) checkTable(t *projLookupTable) {}
+
+func (ct *ctState) checkAffineTables(t *[32]affineLookupTable) {}
+
+func (ct *ctState) resultScalar(s *Scalar) {}
+
+func (ct *ctState) resultPoint(p *Point) {}
+
+func (ct *ctState) resultInt(x *int) {}
+
+func ctPoisonIndex(x *int8) {}
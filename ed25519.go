@@ -0,0 +1,256 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	cryptorand "crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"io"
+)
+
+// Verify reports whether sig is a valid Ed25519 signature of message by
+// publicKey, following the ZIP-215 rules: A and R may be non-canonical
+// encodings, S must be canonical, and the equation is cofactored,
+//
+//     [8][S]B = [8]R + [8][k]A
+//
+// Unlike crypto/ed25519, which uses the cofactorless equation, Verify gives
+// the same result as VerifyBatch for every signature.
+//
+// Execution time depends on the inputs.
+func Verify(publicKey ed25519.PublicKey, message, sig []byte) bool {
+	A, R, S, k, ok := decodeSignature(publicKey, message, sig)
+	if !ok {
+		return false
+	}
+
+	// [S]B - [k]A - R
+	minusA := (&Point{}).Negate(A)
+	check := (&Point{}).VarTimeDoubleScalarBaseMult(k, minusA, S)
+	check.Subtract(check, R)
+	return check.MultByCofactor(check).varTimeIsIdentity()
+}
+
+// VerifyBatch reports whether all sigs are valid Ed25519 signatures of the
+// corresponding msgs by pubs, with the same rules as Verify.
+//
+// The signatures are checked together with a single multiscalar
+// multiplication, as
+//
+//     [8](sum([z_i S_i])B - sum([z_i]R_i) - sum([z_i k_i]A_i)) = 0
+//
+// where each z_i is a random 128-bit coefficient read from rand, so that
+// invalid signatures can't cancel each other out. If rand is nil,
+// crypto/rand.Reader is used. If VerifyBatch returns false, Verify can be used
+// on each signature to identify the invalid ones.
+//
+// VerifyBatch returns an error only if the inputs have different lengths or
+// reading from rand fails. An empty batch is valid.
+//
+// Execution time depends on the inputs.
+func VerifyBatch(rand io.Reader, pubs []ed25519.PublicKey, msgs, sigs [][]byte) (bool, error) {
+	if len(pubs) != len(msgs) || len(pubs) != len(sigs) {
+		return false, errors.New("edwards25519: called VerifyBatch with different size inputs")
+	}
+	n := len(pubs)
+	if n == 0 {
+		return true, nil
+	}
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+
+	randomness := make([]byte, n*16)
+	if _, err := io.ReadFull(rand, randomness); err != nil {
+		return false, err
+	}
+
+	// The terms are B, followed by each R_i, followed by each A_i.
+	scalars := make([]*Scalar, 1+2*n)
+	points := make([]*Point, 1+2*n)
+	scalarsBuf := make([]Scalar, 1+2*n)
+	for i := range scalars {
+		scalars[i] = &scalarsBuf[i]
+	}
+	points[0] = NewGeneratorPoint()
+
+	var z Scalar
+	for i := 0; i < n; i++ {
+		A, R, S, k, ok := decodeSignature(pubs[i], msgs[i], sigs[i])
+		if !ok {
+			return false, nil
+		}
+
+		var zBytes [32]byte
+		copy(zBytes[:], randomness[i*16:(i+1)*16])
+		if _, err := z.SetCanonicalBytes(zBytes[:]); err != nil {
+			panic("edwards25519: internal error: 128-bit scalar is not canonical")
+		}
+
+		scalars[0].MultiplyAdd(&z, S, scalars[0])
+		scalars[1+i].Negate(&z)
+		points[1+i] = R
+		scalars[1+n+i].Multiply(&z, k)
+		scalars[1+n+i].Negate(scalars[1+n+i])
+		points[1+n+i] = A
+	}
+
+	check := (&Point{}).VarTimeMultiScalarMult(scalars, points)
+	return check.MultByCofactor(check).varTimeIsIdentity(), nil
+}
+
+// decodeSignature decodes the public key A, the signature components R and S,
+// and computes the challenge k = SHA-512(R || A || message). It returns false
+// if any encoding is invalid.
+func decodeSignature(publicKey ed25519.PublicKey, message, sig []byte) (A, R *Point, S, k *Scalar, ok bool) {
+	if len(publicKey) != ed25519.PublicKeySize || len(sig) != ed25519.SignatureSize {
+		return nil, nil, nil, nil, false
+	}
+	A, err := (&Point{}).SetBytes(publicKey)
+	if err != nil {
+		return nil, nil, nil, nil, false
+	}
+	R, err = (&Point{}).SetBytes(sig[:32])
+	if err != nil {
+		return nil, nil, nil, nil, false
+	}
+	S, err = NewScalar().SetCanonicalBytes(sig[32:])
+	if err != nil {
+		return nil, nil, nil, nil, false
+	}
+
+	// The challenge is computed over the encodings as received, which may be
+	// non-canonical, not over re-encoded points.
+	h := sha512.New()
+	h.Write(sig[:32])
+	h.Write(publicKey)
+	h.Write(message)
+	var digest [64]byte
+	k = NewScalar().SetUniformBytes(h.Sum(digest[:0]))
+	return A, R, S, k, true
+}
+
+// VerifyOptions selects the Ed25519 verification rules applied by
+// VerifyEquation. The zero value is the most permissive set of rules, with
+// the cofactorless equation.
+//
+// For example, the ZIP-215 rules used by Verify are
+//
+//     VerifyOptions{Cofactored: true, RequireCanonicalS: true}
+//
+// and the rules of crypto/ed25519 are
+//
+//     VerifyOptions{RequireCanonicalR: true, RequireCanonicalS: true}
+type VerifyOptions struct {
+	// Cofactored selects the equation [8][S]B = [8]R + [8][k]A, instead of
+	// the cofactorless [S]B = R + [k]A.
+	Cofactored bool
+
+	// RequireCanonicalR rejects non-canonical encodings of R.
+	RequireCanonicalR bool
+
+	// RequireCanonicalS rejects values of S that are not reduced modulo l.
+	// Otherwise, S is only required to be less than 2^253, as in the original
+	// reference implementation, and is then reduced.
+	RequireCanonicalS bool
+
+	// RejectSmallOrderA rejects public keys of order 1, 2, 4, or 8.
+	RejectSmallOrderA bool
+
+	// RejectSmallOrderR rejects values of R of order 1, 2, 4, or 8.
+	RejectSmallOrderR bool
+}
+
+// VerifyEquation checks the Ed25519 verification equation for the public key
+// A, the signature components R and S, and the challenge k, which the caller
+// computes as SHA-512(R || A || M) reduced modulo l, over whichever encodings
+// of R and A its rules require. Any requirement on the encoding of A, which is
+// already decoded, is also up to the caller.
+//
+// VerifyEquation returns an error if the inputs are rejected by opts, and
+// false and no error if they are acceptable but the equation doesn't hold.
+//
+// Execution time depends on the inputs.
+func VerifyEquation(opts VerifyOptions, A *Point, R []byte, S []byte, k *Scalar) (bool, error) {
+	checkInitialized(A)
+	if len(R) != 32 || len(S) != 32 {
+		return false, errors.New("edwards25519: invalid signature length")
+	}
+
+	RPoint, err := (&Point{}).SetBytes(R)
+	if err != nil {
+		return false, err
+	}
+	if opts.RequireCanonicalR && !bytes.Equal(RPoint.Bytes(), R) {
+		return false, errors.New("edwards25519: non-canonical R encoding")
+	}
+
+	var s *Scalar
+	if opts.RequireCanonicalS {
+		if s, err = NewScalar().SetCanonicalBytes(S); err != nil {
+			return false, err
+		}
+	} else {
+		if S[31]&224 != 0 {
+			return false, errors.New("edwards25519: invalid S encoding")
+		}
+		var wide [64]byte
+		copy(wide[:], S)
+		s = NewScalar().SetUniformBytes(wide[:])
+	}
+
+	if opts.RejectSmallOrderA && (&Point{}).MultByCofactor(A).varTimeIsIdentity() {
+		return false, errors.New("edwards25519: small order public key")
+	}
+	if opts.RejectSmallOrderR && (&Point{}).MultByCofactor(RPoint).varTimeIsIdentity() {
+		return false, errors.New("edwards25519: small order R")
+	}
+
+	// [S]B - [k]A - R. Negating A rather than k matters for the cofactorless
+	// equation, because [l - k]A differs from -[k]A if A has a low order
+	// component.
+	minusA := (&Point{}).Negate(A)
+	check := (&Point{}).VarTimeDoubleScalarBaseMult(k, minusA, s)
+	check.Subtract(check, RPoint)
+	if opts.Cofactored {
+		check.MultByCofactor(check)
+	}
+	return check.varTimeIsIdentity(), nil
+}
+
+// PointFromPublicKey returns the point encoded by the Ed25519 public key pub,
+// or an error if pub is not a valid encoding. Like SetBytes, it accepts
+// non-canonical encodings.
+func PointFromPublicKey(pub ed25519.PublicKey) (*Point, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, errors.New("edwards25519: invalid public key length")
+	}
+	return (&Point{}).SetBytes(pub)
+}
+
+// PublicKeyFromPoint returns the Ed25519 public key encoding p.
+func PublicKeyFromPoint(p *Point) ed25519.PublicKey {
+	return ed25519.PublicKey(p.Bytes())
+}
+
+// ScalarAndPrefixFromSeed expands the Ed25519 private key seed as specified in
+// RFC 8032, Section 5.1.5, and returns the secret scalar, which is the public
+// key's discrete logarithm, and the 32 bytes prefix used to derive nonces.
+//
+// The seed is the first ed25519.SeedSize bytes of an ed25519.PrivateKey, as
+// returned by its Seed method.
+func ScalarAndPrefixFromSeed(seed []byte) (*Scalar, []byte, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, nil, errors.New("edwards25519: invalid seed length")
+	}
+	h := sha512.Sum512(seed)
+	s := NewScalar().SetBytesWithClamping(h[:32])
+	prefix := make([]byte, 32)
+	copy(prefix, h[32:])
+	return s, prefix, nil
+}
@@ -0,0 +1,504 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"testing"
+)
+
+// rfc8032Vectors are the test vectors from RFC 8032, Section 7.1.
+var rfc8032Vectors = []struct{ public, message, sig string }{
+	{
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		"",
+		"e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b",
+	},
+	{
+		"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		"72",
+		"92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00",
+	},
+	{
+		"fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+		"af82",
+		"6291d657deec24024827e69c3abe01a30ce548a284743a445e3680d7db5ac3ac18ff9b538d16f290ae67f760984dc6594a7c15e9716ed28dc027beceea1ec40a",
+	},
+}
+
+// randomSignatures returns n valid signatures of random messages by random
+// keys.
+func randomSignatures(rand *mathrand.Rand, n int) (pubs []ed25519.PublicKey, msgs, sigs [][]byte) {
+	for i := 0; i < n; i++ {
+		pub, priv, err := ed25519.GenerateKey(rand)
+		if err != nil {
+			panic(err)
+		}
+		msg := make([]byte, rand.Intn(100))
+		rand.Read(msg)
+		pubs = append(pubs, pub)
+		msgs = append(msgs, msg)
+		sigs = append(sigs, ed25519.Sign(priv, msg))
+	}
+	return pubs, msgs, sigs
+}
+
+// torsionSignature returns a signature of message whose R has a low order
+// component. It is valid under the cofactored equation, but not under the
+// cofactorless one.
+func torsionSignature(seed, message []byte) (ed25519.PublicKey, []byte) {
+	h := sha512.Sum512(seed)
+	a := NewScalar().SetBytesWithClamping(h[:32])
+	A := (&Point{}).ScalarBaseMult(a)
+
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		panic(err)
+	}
+	nonce := sha512.New()
+	nonce.Write(h[32:])
+	nonce.Write(message)
+	r := NewScalar().SetUniformBytes(nonce.Sum(nil))
+	R := (&Point{}).ScalarBaseMult(r)
+	R.Add(R, lowOrder)
+
+	k := sha512.New()
+	k.Write(R.Bytes())
+	k.Write(A.Bytes())
+	k.Write(message)
+	S := NewScalar().SetUniformBytes(k.Sum(nil))
+	S.MultiplyAdd(S, a, r)
+
+	return A.Bytes(), append(R.Bytes(), S.Bytes()...)
+}
+
+func TestVerifyRFC8032(t *testing.T) {
+	var pubs []ed25519.PublicKey
+	var msgs, sigs [][]byte
+	for i, v := range rfc8032Vectors {
+		pub, msg, sig := decodeHex(v.public), decodeHex(v.message), decodeHex(v.sig)
+		if !Verify(pub, msg, sig) {
+			t.Errorf("vector %d: signature is invalid", i)
+		}
+		pubs = append(pubs, pub)
+		msgs = append(msgs, msg)
+		sigs = append(sigs, sig)
+	}
+	if ok, err := VerifyBatch(nil, pubs, msgs, sigs); err != nil || !ok {
+		t.Errorf("batch of RFC 8032 vectors is invalid: %v, %v", ok, err)
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{1, 2, 16, 200} {
+		pubs, msgs, sigs := randomSignatures(rand, n)
+		other, _, _ := randomSignatures(rand, 1)
+		if ok, err := VerifyBatch(rand, pubs, msgs, sigs); err != nil || !ok {
+			t.Errorf("n = %d: valid batch is invalid: %v, %v", n, ok, err)
+		}
+
+		corruptions := []struct {
+			name    string
+			corrupt func(i int)
+		}{
+			{"R", func(i int) { sigs[i][0] ^= 1 }},
+			{"S", func(i int) { sigs[i][40] ^= 1 }},
+			{"message", func(i int) { msgs[i] = append(msgs[i], 0) }},
+			{"public key", func(i int) { pubs[i] = other[0] }},
+			{"truncated signature", func(i int) { sigs[i] = sigs[i][:63] }},
+		}
+		for _, c := range corruptions {
+			i := rand.Intn(n)
+			pub, msg, sig := pubs[i], msgs[i], append([]byte{}, sigs[i]...)
+			c.corrupt(i)
+
+			if ok, err := VerifyBatch(rand, pubs, msgs, sigs); err != nil || ok {
+				t.Errorf("n = %d: batch with corrupted %s is valid: %v, %v", n, c.name, ok, err)
+			}
+			for j := range sigs {
+				if got := Verify(pubs[j], msgs[j], sigs[j]); got != (j != i) {
+					t.Errorf("n = %d: corrupted %s at %d: Verify(%d) = %v", n, c.name, i, j, got)
+				}
+			}
+
+			pubs[i], msgs[i], sigs[i] = pub, msg, sig
+		}
+	}
+}
+
+func TestVerifyBatchCofactored(t *testing.T) {
+	// A signature with a low order component in R is rejected by
+	// crypto/ed25519, but accepted by the cofactored equation, both alone
+	// and in a batch.
+	rand := mathrand.New(mathrand.NewSource(0))
+	pubs, msgs, sigs := randomSignatures(rand, 10)
+	pub, sig := torsionSignature([]byte("seed"), msgs[3])
+	pubs[3], sigs[3] = pub, sig
+
+	if ed25519.Verify(pub, msgs[3], sig) {
+		t.Fatal("torsion signature is valid under the cofactorless equation")
+	}
+	if !Verify(pub, msgs[3], sig) {
+		t.Error("torsion signature is invalid under the cofactored equation")
+	}
+	if ok, err := VerifyBatch(rand, pubs, msgs, sigs); err != nil || !ok {
+		t.Errorf("batch with torsion signature is invalid: %v, %v", ok, err)
+	}
+}
+
+func TestVerifyNonCanonicalS(t *testing.T) {
+	v := rfc8032Vectors[0]
+	pub, sig := decodeHex(v.public), decodeHex(v.sig)
+
+	// S + l is a non-canonical encoding of S, which is rejected.
+	var S, l [32]byte
+	copy(S[:], sig[32:])
+	copy(l[:], scMinusOne.s[:])
+	l[0]++
+	var carry uint16
+	for i := range S {
+		carry += uint16(S[i]) + uint16(l[i])
+		S[i] = byte(carry)
+		carry >>= 8
+	}
+	nonCanonical := append(sig[:32:32], S[:]...)
+
+	if Verify(pub, nil, nonCanonical) {
+		t.Error("signature with non-canonical S is valid")
+	}
+	if ok, err := VerifyBatch(nil, []ed25519.PublicKey{pub}, [][]byte{nil}, [][]byte{nonCanonical}); err != nil || ok {
+		t.Errorf("batch with non-canonical S is valid: %v, %v", ok, err)
+	}
+}
+
+type errorReader struct{}
+
+func (errorReader) Read([]byte) (int, error) { return 0, errors.New("read error") }
+
+// constantReader is a broken random source that returns the same byte forever.
+type constantReader byte
+
+func (r constantReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = byte(r)
+	}
+	return len(b), nil
+}
+
+func TestVerifyBatchErrors(t *testing.T) {
+	if ok, err := VerifyBatch(nil, nil, nil, nil); err != nil || !ok {
+		t.Errorf("empty batch is invalid: %v, %v", ok, err)
+	}
+
+	rand := mathrand.New(mathrand.NewSource(0))
+	pubs, msgs, sigs := randomSignatures(rand, 3)
+	if _, err := VerifyBatch(rand, pubs, msgs[:2], sigs); err == nil {
+		t.Error("expected an error for different size inputs")
+	}
+	if _, err := VerifyBatch(errorReader{}, pubs, msgs, sigs); err == nil {
+		t.Error("expected an error from the random source")
+	}
+}
+
+// speccheckVectors are the edge cases from "Taming the many EdDSAs" by
+// Chalkias, Garillot, and Nikolaenko, from
+// https://github.com/novifinancial/ed25519-speccheck/blob/master/scripts/cases.json,
+// with whether they are accepted by crypto/ed25519, by the ZIP-215 rules,
+// and by strict rules that also reject small order public keys.
+var speccheckVectors = []struct {
+	name                   string
+	message, public, sig   string
+	stdlib, zip215, strict bool
+}{
+	{
+		"0: small order A, small order R",
+		"8c93255d71dcab10e8f379c26200f3c7bd5f09d9bc3068d3ef4edeb4853022b6",
+		"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac03fa",
+		"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a0000000000000000000000000000000000000000000000000000000000000000",
+		true, true, false,
+	},
+	{
+		"1: small order A, mixed order R",
+		"9bd9f44f4dcc75bd531b56b2cd280b0bb38fc1cd6d1230e14861d861de092e79",
+		"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac03fa",
+		"f7badec5b8abeaf699583992219b7b223f1df3fbbea919844e3f7c554a43dd43a5bb704786be79fc476f91d3f3f89b03984d8068dcf1bb7dfc6637b45450ac04",
+		true, true, false,
+	},
+	{
+		"2: mixed order A, small order R",
+		"aebf3f2601a0c8c5d39cc7d8911642f740b78168218da8471772b35f9d35b9ab",
+		"f7badec5b8abeaf699583992219b7b223f1df3fbbea919844e3f7c554a43dd43",
+		"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac03fa8c4bd45aecaca5b24fb97bc10ac27ac8751a7dfe1baff8b953ec9f5833ca260e",
+		true, true, true,
+	},
+	{
+		"3: mixed order A, mixed order R",
+		"9bd9f44f4dcc75bd531b56b2cd280b0bb38fc1cd6d1230e14861d861de092e79",
+		"cdb267ce40c5cd45306fa5d2f29731459387dbf9eb933b7bd5aed9a765b88d4d",
+		"9046a64750444938de19f227bb80485e92b83fdb4b6506c160484c016cc1852f87909e14428a7a1d62e9f22f3d3ad7802db02eb2e688b6c52fcd6648a98bd009",
+		true, true, true,
+	},
+	{
+		"4: cofactored verification only",
+		"e47d62c63f830dc7a6851a0b1f33ae4bb2f507fb6cffec4011eaccd55b53f56c",
+		"cdb267ce40c5cd45306fa5d2f29731459387dbf9eb933b7bd5aed9a765b88d4d",
+		"160a1cb0dc9c0258cd0a7d23e94d8fa878bcb1925f2c64246b2dee1796bed5125ec6bc982a269b723e0668e540911a9a6a58921d6925e434ab10aa7940551a09",
+		false, true, true,
+	},
+	{
+		"5: cofactored verification with 8(kA) != (8k mod l)A",
+		"e47d62c63f830dc7a6851a0b1f33ae4bb2f507fb6cffec4011eaccd55b53f56c",
+		"cdb267ce40c5cd45306fa5d2f29731459387dbf9eb933b7bd5aed9a765b88d4d",
+		"21122a84e0b5fca4052f5b1235c80a537878b38f3142356b2c2384ebad4668b7e40bc836dac0f71076f9abe3a53f9c03c1ceeeddb658d0030494ace586687405",
+		false, true, true,
+	},
+	{
+		"6: non-canonical S > l",
+		"85e241a07d148b41e47d62c63f830dc7a6851a0b1f33ae4bb2f507fb6cffec40",
+		"442aad9f089ad9e14647b1ef9099a1ff4798d78589e66f28eca69c11f582a623",
+		"e96f66be976d82e60150baecff9906684aebb1ef181f67a7189ac78ea23b6c0e547f7690a0e2ddcd04d87dbc3490dc19b3b3052f7ff0538cb68afb369ba3a514",
+		false, false, false,
+	},
+	{
+		"7: non-canonical S >> l",
+		"85e241a07d148b41e47d62c63f830dc7a6851a0b1f33ae4bb2f507fb6cffec40",
+		"442aad9f089ad9e14647b1ef9099a1ff4798d78589e66f28eca69c11f582a623",
+		"8ce5b96c8f26d0ab6c47958c9e68b937104cd36e13c33566acd2fe8d38aa19427e71f98a473474f2f13f06f97c20d58cc3f54b8bd0d272f42b695dd7e89a8c22",
+		false, false, false,
+	},
+	{
+		"8: non-canonical small order R, hashed reduced",
+		"9bedc267423725d473888631ebf45988bad3db83851ee85c85e241a07d148b41",
+		"f7badec5b8abeaf699583992219b7b223f1df3fbbea919844e3f7c554a43dd43",
+		"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff03be9678ac102edcd92b0210bb34d7428d12ffc5df5f37e359941266a4e35f0f",
+		false, false, false,
+	},
+	{
+		"9: non-canonical small order R, hashed as received",
+		"9bedc267423725d473888631ebf45988bad3db83851ee85c85e241a07d148b41",
+		"f7badec5b8abeaf699583992219b7b223f1df3fbbea919844e3f7c554a43dd43",
+		"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffca8c5b64cd208982aa38d4936621a4775aa233aa0505711d8fdcfdaa943d4908",
+		false, true, false,
+	},
+	{
+		"10: non-canonical small order A, hashed reduced",
+		"e96b7021eb39c1a163b6da4e3093dcd3f21387da4cc4572be588fafae23c155b",
+		"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"a9d55260f765261eb9b84e106f665e00b867287a761990d7135963ee0a7d59dca5bb704786be79fc476f91d3f3f89b03984d8068dcf1bb7dfc6637b45450ac04",
+		false, true, false,
+	},
+	{
+		"11: non-canonical small order A, hashed as received",
+		"39a591f5321bbe07fd5a23dc2f39d025d74526615746727ceefd6e82ae65c06f",
+		"ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"a9d55260f765261eb9b84e106f665e00b867287a761990d7135963ee0a7d59dca5bb704786be79fc476f91d3f3f89b03984d8068dcf1bb7dfc6637b45450ac04",
+		true, true, false,
+	},
+}
+
+func TestVerifyEquationSpeccheck(t *testing.T) {
+	stdlib := VerifyOptions{RequireCanonicalR: true, RequireCanonicalS: true}
+	zip215 := VerifyOptions{Cofactored: true, RequireCanonicalS: true}
+	strict := VerifyOptions{Cofactored: true, RequireCanonicalR: true,
+		RequireCanonicalS: true, RejectSmallOrderA: true}
+
+	for _, v := range speccheckVectors {
+		message, public, sig := decodeHex(v.message), decodeHex(v.public), decodeHex(v.sig)
+		A, err := (&Point{}).SetBytes(public)
+		if err != nil {
+			t.Fatalf("%s: %v", v.name, err)
+		}
+		h := sha512.New()
+		h.Write(sig[:32])
+		h.Write(public)
+		h.Write(message)
+		k := NewScalar().SetUniformBytes(h.Sum(nil))
+
+		for _, f := range []struct {
+			name     string
+			opts     VerifyOptions
+			expected bool
+		}{
+			{"stdlib", stdlib, v.stdlib},
+			{"ZIP-215", zip215, v.zip215},
+			{"strict", strict, v.strict},
+		} {
+			ok, err := VerifyEquation(f.opts, A, sig[:32], sig[32:], k)
+			if ok != f.expected {
+				t.Errorf("%s: %s: got %v (%v), expected %v", v.name, f.name, ok, err, f.expected)
+			}
+			if ok && err != nil {
+				t.Errorf("%s: %s: accepted with error %v", v.name, f.name, err)
+			}
+		}
+
+		if got := ed25519.Verify(public, message, sig); got != v.stdlib {
+			t.Errorf("%s: ed25519.Verify = %v, expected %v", v.name, got, v.stdlib)
+		}
+		if got := Verify(public, message, sig); got != v.zip215 {
+			t.Errorf("%s: Verify = %v, expected %v", v.name, got, v.zip215)
+		}
+	}
+}
+
+func TestVerifyEquationOptions(t *testing.T) {
+	v := rfc8032Vectors[0]
+	public, sig := decodeHex(v.public), decodeHex(v.sig)
+	A, err := (&Point{}).SetBytes(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sha512.New()
+	h.Write(sig[:32])
+	h.Write(public)
+	k := NewScalar().SetUniformBytes(h.Sum(nil))
+
+	if ok, err := VerifyEquation(VerifyOptions{}, A, sig[:32], sig[32:], k); err != nil || !ok {
+		t.Errorf("valid signature is rejected: %v, %v", ok, err)
+	}
+	if ok, err := VerifyEquation(VerifyOptions{}, A, sig[:32], sig[32:], NewScalar()); err != nil || ok {
+		t.Errorf("signature with the wrong challenge is accepted: %v, %v", ok, err)
+	}
+	if _, err := VerifyEquation(VerifyOptions{}, A, sig[:31], sig[32:], k); err == nil {
+		t.Error("expected an error for a short R")
+	}
+
+	// S + l is accepted only if canonical S is not required.
+	var S, l [32]byte
+	copy(S[:], sig[32:])
+	copy(l[:], scMinusOne.s[:])
+	l[0]++
+	var carry uint16
+	for i := range S {
+		carry += uint16(S[i]) + uint16(l[i])
+		S[i] = byte(carry)
+		carry >>= 8
+	}
+	if ok, err := VerifyEquation(VerifyOptions{}, A, sig[:32], S[:], k); err != nil || !ok {
+		t.Errorf("non-canonical S is rejected: %v, %v", ok, err)
+	}
+	if _, err := VerifyEquation(VerifyOptions{RequireCanonicalS: true}, A, sig[:32], S[:], k); err == nil {
+		t.Error("expected an error for a non-canonical S")
+	}
+
+	// The identity as R is rejected only if small order R is rejected.
+	identity := NewIdentityPoint().Bytes()
+	if _, err := VerifyEquation(VerifyOptions{}, A, identity, sig[32:], k); err != nil {
+		t.Errorf("small order R is rejected: %v", err)
+	}
+	if _, err := VerifyEquation(VerifyOptions{RejectSmallOrderR: true}, A, identity, sig[32:], k); err == nil {
+		t.Error("expected an error for a small order R")
+	}
+}
+
+func TestScalarAndPrefixFromSeed(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for i := 0; i < 50; i++ {
+		pub, priv, err := ed25519.GenerateKey(rand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, prefix, err := ScalarAndPrefixFromSeed(priv.Seed())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(prefix) != 32 {
+			t.Errorf("prefix length is %d", len(prefix))
+		}
+		A, err := PointFromPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (&Point{}).ScalarBaseMult(s).Equal(A) != 1 {
+			t.Error("scalar times B does not match the public key")
+		}
+		if !bytes.Equal(PublicKeyFromPoint(A), pub) {
+			t.Error("public key does not round-trip")
+		}
+
+		// A signature made with the scalar and prefix verifies.
+		msg := []byte("message")
+		r := sha512.New()
+		r.Write(prefix)
+		r.Write(msg)
+		rs := NewScalar().SetUniformBytes(r.Sum(nil))
+		R := (&Point{}).ScalarBaseMult(rs)
+		k := sha512.New()
+		k.Write(R.Bytes())
+		k.Write(pub)
+		k.Write(msg)
+		S := NewScalar().SetUniformBytes(k.Sum(nil))
+		S.MultiplyAdd(S, s, rs)
+		sig := append(R.Bytes(), S.Bytes()...)
+		if !bytes.Equal(sig, ed25519.Sign(priv, msg)) {
+			t.Error("signature with the expanded key does not match ed25519.Sign")
+		}
+	}
+
+	if _, _, err := ScalarAndPrefixFromSeed(make([]byte, 31)); err == nil {
+		t.Error("expected an error for a short seed")
+	}
+}
+
+func TestPointFromPublicKey(t *testing.T) {
+	invalid := []string{
+		// Not on the curve.
+		"0200000000000000000000000000000000000000000000000000000000000000",
+		// Too short and too long.
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f70751",
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a00",
+	}
+	for _, pub := range invalid {
+		if _, err := PointFromPublicKey(decodeHex(pub)); err == nil {
+			t.Errorf("%s: expected an error", pub)
+		}
+	}
+	if _, err := PointFromPublicKey(nil); err == nil {
+		t.Error("expected an error for an empty public key")
+	}
+}
+
+func TestVerifyMatchesCryptoEd25519(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	pubs, msgs, sigs := randomSignatures(rand, 50)
+	for i := range sigs {
+		if !Verify(pubs[i], msgs[i], sigs[i]) {
+			t.Errorf("signature %d is invalid", i)
+		}
+		bad := bytes.Repeat([]byte{0}, 64)
+		if Verify(pubs[i], msgs[i], bad) {
+			t.Errorf("zero signature %d is valid", i)
+		}
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{64, 1024} {
+		pubs, msgs, sigs := randomSignatures(rand, n)
+		b.Run(fmt.Sprintf("VerifyBatch/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if ok, err := VerifyBatch(nil, pubs, msgs, sigs); err != nil || !ok {
+					b.Fatal("batch is invalid")
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("ed25519.Verify/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for j := range sigs {
+					if !ed25519.Verify(pubs[j], msgs[j], sigs[j]) {
+						b.Fatal("signature is invalid")
+					}
+				}
+			}
+		})
+	}
+}
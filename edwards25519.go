@@ -191,6 +191,88 @@ func (v *Point) SetBytes(x []byte) (*Point, error) {
 	return v, nil
 }
 
+var (
+	errExtendedLength     = errors.New("edwards25519: invalid extended coordinate length")
+	errExtendedZeroZ      = errors.New("edwards25519: extended coordinate Z is zero")
+	errExtendedNotOnCurve = errors.New("edwards25519: extended coordinates are not on the curve")
+	errExtendedBadT       = errors.New("edwards25519: extended coordinate T is inconsistent with X, Y, and Z")
+)
+
+// SetExtendedCoordinates sets v = (X:Y:Z:T), in extended coordinates where
+// x = X/Z, y = Y/Z, and xy = T/Z. Each coordinate is a 32 bytes little-endian
+// field element encoding, decoded as by the curve point encoding (that is, the
+// high bit is ignored and non-canonical values are accepted).
+//
+// If Z is zero, if (X/Z, Y/Z) is not on the curve, or if XY != ZT, then
+// SetExtendedCoordinates returns nil and a distinct error for each case, and
+// the receiver is unchanged. Otherwise, SetExtendedCoordinates returns v.
+func (v *Point) SetExtendedCoordinates(X, Y, Z, T []byte) (*Point, error) {
+	p, err := (&Point{}).setExtendedCoordinates(X, Y, Z, T)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkExtendedCoordinates(p); err != nil {
+		return nil, err
+	}
+
+	return v.Set(p), nil
+}
+
+// checkExtendedCoordinates returns an error if the extended coordinates of p
+// don't represent a valid point.
+func checkExtendedCoordinates(p *Point) error {
+	if p.z.Equal(feZero) == 1 {
+		return errExtendedZeroZ
+	}
+
+	// -x² + y² = 1 + dx²y²
+	// (-X² + Y²)*Z² = Z⁴ + dX²Y²
+	var XX, YY, ZZ, ZZZZ, lhs, rhs fieldElement
+	XX.Square(&p.x)
+	YY.Square(&p.y)
+	ZZ.Square(&p.z)
+	ZZZZ.Square(&ZZ)
+	lhs.Subtract(&YY, &XX).Multiply(&lhs, &ZZ)
+	rhs.Multiply(d, &XX).Multiply(&rhs, &YY).Add(&rhs, &ZZZZ)
+	if lhs.Equal(&rhs) != 1 {
+		return errExtendedNotOnCurve
+	}
+
+	// xy = T/Z, so XY = ZT.
+	lhs.Multiply(&p.x, &p.y)
+	rhs.Multiply(&p.z, &p.t)
+	if lhs.Equal(&rhs) != 1 {
+		return errExtendedBadT
+	}
+
+	return nil
+}
+
+// SetExtendedCoordinatesUnsafe sets v = (X:Y:Z:T) like SetExtendedCoordinates,
+// but without checking that the coordinates represent a valid point.
+//
+// It is the caller's responsibility to ensure the inputs are trusted. Using an
+// invalid point with any other method produces undefined results.
+func (v *Point) SetExtendedCoordinatesUnsafe(X, Y, Z, T []byte) (*Point, error) {
+	p, err := (&Point{}).setExtendedCoordinates(X, Y, Z, T)
+	if err != nil {
+		return nil, err
+	}
+	return v.Set(p), nil
+}
+
+func (v *Point) setExtendedCoordinates(X, Y, Z, T []byte) (*Point, error) {
+	if len(X) != 32 || len(Y) != 32 || len(Z) != 32 || len(T) != 32 {
+		return nil, errExtendedLength
+	}
+	v.x.SetBytes(X)
+	v.y.SetBytes(Y)
+	v.z.SetBytes(Z)
+	v.t.SetBytes(T)
+	return v, nil
+}
+
 // BytesMontgomery converts v to a point on the birationally-equivalent
 // Curve25519 Montgomery curve, and returns its canonical 32 bytes encoding
 // according to RFC 7748.
@@ -432,6 +514,8 @@ func (v *Point) Negate(p *Point) *Point {
 // Equal returns 1 if v is equivalent to u, and 0 otherwise.
 func (v *Point) Equal(u *Point) int {
 	checkInitialized(v, u)
+	ct := newCTState()
+	ct.poisonPoints(v, u)
 
 	var t1, t2, t3, t4 fieldElement
 	t1.Multiply(&v.x, &u.z)
@@ -439,7 +523,14 @@ func (v *Point) Equal(u *Point) int {
 	t3.Multiply(&v.y, &u.z)
 	t4.Multiply(&u.y, &v.z)
 
-	return t1.Equal(&t2) & t3.Equal(&t4)
+	out := t1.Equal(&t2) & t3.Equal(&t4)
+	ct.resultInt(&out)
+	return out
+}
+
+// varTimeIsIdentity returns whether v is the identity, in variable time.
+func (v *Point) varTimeIsIdentity() bool {
+	return v.x.Equal(feZero) == 1 && v.y.Equal(&v.z) == 1
 }
 
 // Constant-time operations
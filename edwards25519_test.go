@@ -88,6 +88,72 @@ func TestInvalidEncodings(t *testing.T) {
 	checkOnCurve(t, p)
 }
 
+func TestSetExtendedCoordinates(t *testing.T) {
+	coordinates := func(p *Point) (X, Y, Z, T []byte) {
+		return p.x.Bytes(), p.y.Bytes(), p.z.Bytes(), p.t.Bytes()
+	}
+
+	// A valid point with Z != 1.
+	valid := (&Point{}).ScalarMult(&dalekScalar, B)
+	for _, p := range []*Point{B, I, valid} {
+		X, Y, Z, T := coordinates(p)
+		q, err := (&Point{}).SetExtendedCoordinates(X, Y, Z, T)
+		if err != nil {
+			t.Fatalf("valid point rejected: %v", err)
+		}
+		if q.Equal(p) != 1 {
+			t.Errorf("decoded point does not match")
+		}
+		checkOnCurve(t, q)
+	}
+
+	X, Y, Z, T := coordinates(valid)
+	var corrupted fieldElement
+	corrupted.Add(&valid.t, feOne)
+	badT := corrupted.Bytes()
+	corrupted.Add(&valid.x, feOne)
+	badX := corrupted.Bytes()
+	zero := make([]byte, 32)
+
+	tests := []struct {
+		name       string
+		X, Y, Z, T []byte
+		err        error
+	}{
+		{"corrupted T", X, Y, Z, badT, errExtendedBadT},
+		{"off-curve X", badX, Y, Z, T, errExtendedNotOnCurve},
+		{"zero Z", X, Y, zero, T, errExtendedZeroZ},
+		{"short X", X[:31], Y, Z, T, errExtendedLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewGeneratorPoint()
+			out, err := p.SetExtendedCoordinates(tt.X, tt.Y, tt.Z, tt.T)
+			if err != tt.err {
+				t.Errorf("got error %v, expected %v", err, tt.err)
+			}
+			if out != nil {
+				t.Error("SetExtendedCoordinates did not return nil on an invalid input")
+			}
+			if p.Equal(B) != 1 {
+				t.Error("the Point was modified while decoding an invalid input")
+			}
+		})
+	}
+
+	// The unchecked variant accepts inconsistent coordinates.
+	p, err := (&Point{}).SetExtendedCoordinatesUnsafe(X, Y, Z, badT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.x.Equal(&valid.x) != 1 || p.t.Equal(&valid.t) == 1 {
+		t.Error("SetExtendedCoordinatesUnsafe did not set the raw coordinates")
+	}
+	if _, err := p.SetExtendedCoordinatesUnsafe(X, Y, Z, T[:31]); err != errExtendedLength {
+		t.Errorf("got error %v, expected %v", err, errExtendedLength)
+	}
+}
+
 func TestNonCanonicalPoints(t *testing.T) {
 	type test struct {
 		name                string
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"encoding/hex"
+	"errors"
+)
+
+// The binary encoding of Point and Scalar is the canonical 32 bytes encoding
+// returned by Bytes, and the text encoding is its lowercase hexadecimal form,
+// which is also what encoding/json uses. The methods have pointer receivers, so
+// encoders only find them on addressable values, such as the fields of a
+// struct passed by pointer.
+
+// AppendBinary implements the encoding.BinaryAppender interface, appending
+// the 32 bytes encoding of v to b. It never returns an error.
+func (v *Point) AppendBinary(b []byte) ([]byte, error) {
+	var buf [32]byte
+	return append(b, v.bytes(&buf)...), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (v *Point) MarshalBinary() ([]byte, error) {
+	return v.AppendBinary(make([]byte, 0, 32))
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, with
+// the same semantics as SetBytes. On error, v is unchanged.
+func (v *Point) UnmarshalBinary(data []byte) error {
+	_, err := v.SetBytes(data)
+	return err
+}
+
+// AppendText implements the encoding.TextAppender interface, appending the
+// hexadecimal encoding of v to b. It never returns an error.
+func (v *Point) AppendText(b []byte) ([]byte, error) {
+	var buf [32]byte
+	return appendHex(b, v.bytes(&buf)), nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (v *Point) MarshalText() ([]byte, error) {
+	return v.AppendText(make([]byte, 0, 64))
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. On error,
+// v is unchanged.
+func (v *Point) UnmarshalText(text []byte) error {
+	var buf [32]byte
+	if err := decodeHex32(&buf, text); err != nil {
+		return err
+	}
+	return v.UnmarshalBinary(buf[:])
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface, appending
+// the 32 bytes encoding of s to b. It never returns an error.
+func (s *Scalar) AppendBinary(b []byte) ([]byte, error) {
+	return append(b, s.s[:]...), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (s *Scalar) MarshalBinary() ([]byte, error) {
+	return s.AppendBinary(make([]byte, 0, 32))
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, with
+// the same semantics as SetCanonicalBytes. On error, s is unchanged.
+func (s *Scalar) UnmarshalBinary(data []byte) error {
+	_, err := s.SetCanonicalBytes(data)
+	return err
+}
+
+// AppendText implements the encoding.TextAppender interface, appending the
+// hexadecimal encoding of s to b. It never returns an error.
+func (s *Scalar) AppendText(b []byte) ([]byte, error) {
+	return appendHex(b, s.s[:]), nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s *Scalar) MarshalText() ([]byte, error) {
+	return s.AppendText(make([]byte, 0, 64))
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. On error,
+// s is unchanged.
+func (s *Scalar) UnmarshalText(text []byte) error {
+	var buf [32]byte
+	if err := decodeHex32(&buf, text); err != nil {
+		return err
+	}
+	return s.UnmarshalBinary(buf[:])
+}
+
+// appendHex appends the hexadecimal encoding of src to b, growing it at most
+// once.
+func appendHex(b, src []byte) []byte {
+	n := len(b)
+	if cap(b)-n < hex.EncodedLen(len(src)) {
+		grown := make([]byte, n, 2*cap(b)+hex.EncodedLen(len(src)))
+		copy(grown, b)
+		b = grown
+	}
+	b = b[:n+hex.EncodedLen(len(src))]
+	hex.Encode(b[n:], src)
+	return b
+}
+
+// decodeHex32 decodes exactly 32 bytes from their hexadecimal encoding.
+func decodeHex32(dst *[32]byte, text []byte) error {
+	if len(text) != hex.EncodedLen(len(dst)) {
+		return errors.New("edwards25519: invalid hex encoding length")
+	}
+	if _, err := hex.Decode(dst[:], text); err != nil {
+		return errors.New("edwards25519: invalid hex encoding")
+	}
+	return nil
+}
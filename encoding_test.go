@@ -0,0 +1,186 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+// appender is encoding.BinaryAppender and encoding.TextAppender, which are not
+// available in older Go versions.
+type appender interface {
+	AppendBinary(b []byte) ([]byte, error)
+	AppendText(b []byte) ([]byte, error)
+	encoding.BinaryMarshaler
+	encoding.TextMarshaler
+}
+
+func TestAppendEncoding(t *testing.T) {
+	p := (&Point{}).ScalarBaseMult(&dalekScalar)
+	for _, tt := range []struct {
+		name  string
+		v     appender
+		bytes []byte
+	}{
+		{"Point", p, p.Bytes()},
+		{"Scalar", &dalekScalar, dalekScalar.Bytes()},
+	} {
+		prefix := []byte("prefix")
+
+		bin, err := tt.v.AppendBinary(append([]byte{}, prefix...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := append(append([]byte{}, prefix...), tt.bytes...); !bytes.Equal(bin, want) {
+			t.Errorf("%s: AppendBinary = %x, expected %x", tt.name, bin, want)
+		}
+		marshaled, err := tt.v.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(marshaled, bin[len(prefix):]) {
+			t.Errorf("%s: MarshalBinary does not match AppendBinary", tt.name)
+		}
+
+		text, err := tt.v.AppendText(append([]byte{}, prefix...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := string(prefix) + hex.EncodeToString(tt.bytes); string(text) != want {
+			t.Errorf("%s: AppendText = %q, expected %q", tt.name, text, want)
+		}
+		marshaled, err = tt.v.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(marshaled, text[len(prefix):]) {
+			t.Errorf("%s: MarshalText does not match AppendText", tt.name)
+		}
+
+		buf := make([]byte, 0, 64)
+		if n := testing.AllocsPerRun(10, func() {
+			tt.v.AppendBinary(buf[:0])
+			tt.v.AppendText(buf[:0])
+		}); n > 0 {
+			t.Errorf("%s: expected zero allocations, got %0.1f", tt.name, n)
+		}
+	}
+}
+
+func TestUnmarshalEncoding(t *testing.T) {
+	p := (&Point{}).ScalarBaseMult(&dalekScalar)
+	text, _ := p.MarshalText()
+	var gotP Point
+	if err := gotP.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if gotP.Equal(p) != 1 {
+		t.Errorf("point did not round trip through text")
+	}
+	text, _ = dalekScalar.MarshalText()
+	var gotS Scalar
+	if err := gotS.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if gotS.Equal(&dalekScalar) != 1 {
+		t.Errorf("scalar did not round trip through text")
+	}
+
+	for _, text := range []string{
+		"",
+		hex.EncodeToString(B.Bytes()[:31]),
+		hex.EncodeToString(B.Bytes()) + "00",
+		"zz" + hex.EncodeToString(B.Bytes())[2:],
+		"0200000000000000000000000000000000000000000000000000000000000000",
+	} {
+		p := (&Point{}).Set(B)
+		if err := p.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("expected error unmarshaling point %q", text)
+		}
+		if p.Equal(B) != 1 {
+			t.Errorf("point was modified by a failed UnmarshalText")
+		}
+	}
+	s := NewScalar().Set(&scOne)
+	if err := s.UnmarshalText([]byte("edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010")); err == nil {
+		t.Errorf("expected error unmarshaling non-canonical scalar")
+	}
+	if s.Equal(&scOne) != 1 {
+		t.Errorf("scalar was modified by a failed UnmarshalText")
+	}
+}
+
+func TestGobEncoding(t *testing.T) {
+	type message struct {
+		S *Scalar
+		P *Point
+	}
+	in := message{&dalekScalar, (&Point{}).ScalarBaseMult(&dalekScalar)}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	var out message
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.S.Equal(in.S) != 1 || out.P.Equal(in.P) != 1 {
+		t.Errorf("message did not round trip through gob")
+	}
+
+	// Non-canonical scalars are rejected by the binary encoding too.
+	s := NewScalar().Set(&scOne)
+	if err := s.UnmarshalBinary(scMinusOne.Bytes()[:31]); err == nil {
+		t.Errorf("expected error unmarshaling short scalar")
+	}
+	nonCanonical := decodeHex("edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010")
+	if err := s.UnmarshalBinary(nonCanonical); err == nil {
+		t.Errorf("expected error unmarshaling non-canonical scalar")
+	}
+	if s.Equal(&scOne) != 1 {
+		t.Errorf("scalar was modified by a failed UnmarshalBinary")
+	}
+}
+
+func TestJSONEncoding(t *testing.T) {
+	type state struct {
+		Nonce  Scalar
+		Shares []*Scalar
+	}
+	in := &state{dalekScalar, []*Scalar{&scOne, &scMinusOne}}
+	j, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Nonce":"` + hex.EncodeToString(dalekScalar.Bytes()) + `","Shares":["` +
+		hex.EncodeToString(scOne.Bytes()) + `","` + hex.EncodeToString(scMinusOne.Bytes()) + `"]}`
+	if string(j) != want {
+		t.Errorf("json.Marshal = %s, want %s", j, want)
+	}
+
+	var out state
+	if err := json.Unmarshal(j, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Nonce.Equal(&in.Nonce) != 1 || len(out.Shares) != 2 ||
+		out.Shares[0].Equal(&scOne) != 1 || out.Shares[1].Equal(&scMinusOne) != 1 {
+		t.Errorf("state did not round trip through JSON")
+	}
+
+	for _, bad := range []string{
+		`{"Nonce":"00"}`,
+		`{"Nonce":"edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010"}`,
+		`{"Nonce":1}`,
+	} {
+		if err := json.Unmarshal([]byte(bad), &out); err == nil {
+			t.Errorf("expected error unmarshaling %s", bad)
+		}
+	}
+}
@@ -5,8 +5,10 @@
 package edwards25519
 
 import (
+	"bytes"
 	"crypto/subtle"
 	"encoding/binary"
+	"errors"
 )
 
 // fieldElement represents an element of the field GF(2^255-19). Note that this
@@ -181,6 +183,41 @@ func (v *fieldElement) Invert(z *fieldElement) *fieldElement {
 	return v.Multiply(&t, &z11) // 2^255 - 21
 }
 
+// invertBatch sets each element of v to its inverse, using a single
+// inversion with Montgomery's trick. Zero elements are left as zero, and don't
+// affect the inverses of the other elements.
+//
+// The computation is done in constant time with respect to the values of the
+// elements, including which of them are zero.
+func invertBatch(v []fieldElement) {
+	if len(v) == 0 {
+		return
+	}
+
+	// products[i] is the product of all nonzero elements before i. Zero
+	// elements are replaced by one, so they don't contribute to it.
+	products := make([]fieldElement, len(v))
+	var acc, factor fieldElement
+	acc.One()
+	for i := range v {
+		products[i] = acc
+		factor.Select(feOne, &v[i], v[i].Equal(feZero))
+		acc.Multiply(&acc, &factor)
+	}
+
+	// acc is now the inverse of the product of all nonzero elements, and
+	// unwinding it yields each inverse in turn.
+	acc.Invert(&acc)
+	var tmp fieldElement
+	for i := len(v) - 1; i >= 0; i-- {
+		isZero := v[i].Equal(feZero)
+		tmp.Multiply(&acc, &products[i])
+		factor.Select(feOne, &v[i], isZero)
+		acc.Multiply(&acc, &factor)
+		v[i].Select(feZero, &tmp, isZero)
+	}
+}
+
 // Set sets v = a, and returns v.
 func (v *fieldElement) Set(a *fieldElement) *fieldElement {
 	*v = *a
@@ -217,6 +254,57 @@ func (v *fieldElement) SetBytes(x []byte) *fieldElement {
 	return v
 }
 
+// setCanonicalBytes sets v to x, which must be a canonical 32 bytes
+// little-endian encoding, and returns v. Unlike SetBytes, it rejects values of
+// 2^255-19 and above, including any with the most significant bit set. On
+// error, v is unchanged.
+func (v *fieldElement) setCanonicalBytes(x []byte) (*fieldElement, error) {
+	if len(x) != 32 {
+		return nil, errors.New("edwards25519: invalid field element length")
+	}
+	var t fieldElement
+	t.SetBytes(x)
+	if !bytes.Equal(t.Bytes(), x) {
+		return nil, errors.New("edwards25519: non-canonical field element encoding")
+	}
+	return v.Set(&t), nil
+}
+
+// setWideBytes sets v to x mod p, where x is a little-endian encoding of up to
+// 64 bytes, and returns v.
+func (v *fieldElement) setWideBytes(x []byte) *fieldElement {
+	if len(x) > 64 {
+		panic("edwards25519: invalid field element input size")
+	}
+	var buf [64]byte
+	copy(buf[:], x)
+
+	// x = lo + hi * 2^256, and SetBytes ignores the top bit of each half,
+	// which is worth 2^255 = 19 mod p. Then, 2^256 = 38 mod p.
+	var lo, hi, top fieldElement
+	lo.SetBytes(buf[:32])
+	top.Mult32(feOne, uint32(buf[31]>>7)*19)
+	lo.Add(&lo, &top)
+	hi.SetBytes(buf[32:])
+	top.Mult32(feOne, uint32(buf[63]>>7)*19)
+	hi.Add(&hi, &top)
+	hi.Mult32(&hi, 38)
+	return v.Add(&lo, &hi)
+}
+
+// setWideBytesBigEndian is like setWideBytes, but x is a big-endian encoding,
+// as in the OS2IP step of hash_to_field from RFC 9380.
+func (v *fieldElement) setWideBytesBigEndian(x []byte) *fieldElement {
+	if len(x) > 64 {
+		panic("edwards25519: invalid field element input size")
+	}
+	var buf [64]byte
+	for i := range x {
+		buf[i] = x[len(x)-1-i]
+	}
+	return v.setWideBytes(buf[:len(x)])
+}
+
 // Bytes returns the canonical 32 bytes little-endian encoding of v.
 func (v *fieldElement) Bytes() []byte {
 	// This function is outlined to make the allocations inline in the caller
@@ -389,7 +477,12 @@ var sqrtM1 = &fieldElement{1718705420411056, 234908883556509,
 //
 // If u/v is square, SqrtRatio returns r and 1. If u/v is not square, SqrtRatio
 // sets r according to Section 4.3 of draft-irtf-cfrg-ristretto255-decaf448-00,
-// and returns r and 0.
+// that is to the non-negative square root of sqrtM1 * u/v, and returns r and 0.
+// If u is zero, r is zero and SqrtRatio returns 1, even if v is zero. If only
+// v is zero, r is zero and SqrtRatio returns 0.
+//
+// A field element is non-negative if the least significant bit of its
+// canonical encoding is 0. The computation is done in constant time.
 func (r *fieldElement) SqrtRatio(u, v *fieldElement) (rr *fieldElement, wasSquare int) {
 	var a, b fieldElement
 
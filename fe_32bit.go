@@ -0,0 +1,15 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build 386 arm mips mipsle wasm
+
+package edwards25519
+
+func feMul(v, x, y *fieldElement) { feMul32(v, x, y) }
+
+func feSquare(v, x *fieldElement) { feSquare32(v, x) }
+
+func (v *fieldElement) carryPropagate() *fieldElement {
+	return v.carryPropagateGeneric()
+}
@@ -34,3 +34,24 @@ func BenchmarkMul32(b *testing.B) {
 		x.Mult32(&x, 0xaa42aa42)
 	}
 }
+
+func BenchmarkInvert(b *testing.B) {
+	x := *sqrtM1
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Invert(&x)
+	}
+}
+
+// BenchmarkInvertBatch inverts 64 elements at a time, and should be compared
+// with 64 times BenchmarkInvert.
+func BenchmarkInvertBatch(b *testing.B) {
+	v := make([]fieldElement, 64)
+	for i := range v {
+		v[i].Mult32(feOne, uint32(i+1))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		invertBatch(v)
+	}
+}
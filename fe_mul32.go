@@ -0,0 +1,267 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+// feMul32 and feSquare32 are implementations of feMul and feSquare for
+// platforms without a fast 64 x 64 -> 128 bit multiplication, like 386, arm,
+// and wasm, where math/bits.Mul64 is done in software.
+//
+// They split each 51-bit limb into two limbs of 26 and 25 bits, in the
+// radix 2^25.5 representation of ref10, so that all products are 32 x 32 -> 64
+// bit multiplications, and then carry and recombine the result into 51-bit
+// limbs. Limb 2i has offset 51i, and limb 2i+1 has offset 51i + 26, so the
+// product of two odd limbs lands one bit above the offset of its output limb,
+// and is doubled, while products past limb 9 wrap around multiplied by 19.
+//
+// The inputs limbs can be up to 52 bits, like the outputs of the other
+// operations, which makes the odd limbs up to 26 bits. The largest output
+// coefficient is then a sum of ten products of at most 2^27 * 19 * 2^26, which
+// fits in 61 bits.
+
+const (
+	maskLow25Bits = (1 << 25) - 1
+	maskLow26Bits = (1 << 26) - 1
+)
+
+func feMul32(v, x, y *fieldElement) {
+	x0 := uint32(x.l0) & maskLow26Bits
+	x1 := uint32(x.l0 >> 26)
+	x2 := uint32(x.l1) & maskLow26Bits
+	x3 := uint32(x.l1 >> 26)
+	x4 := uint32(x.l2) & maskLow26Bits
+	x5 := uint32(x.l2 >> 26)
+	x6 := uint32(x.l3) & maskLow26Bits
+	x7 := uint32(x.l3 >> 26)
+	x8 := uint32(x.l4) & maskLow26Bits
+	x9 := uint32(x.l4 >> 26)
+
+	y0 := uint32(y.l0) & maskLow26Bits
+	y1 := uint32(y.l0 >> 26)
+	y2 := uint32(y.l1) & maskLow26Bits
+	y3 := uint32(y.l1 >> 26)
+	y4 := uint32(y.l2) & maskLow26Bits
+	y5 := uint32(y.l2 >> 26)
+	y6 := uint32(y.l3) & maskLow26Bits
+	y7 := uint32(y.l3 >> 26)
+	y8 := uint32(y.l4) & maskLow26Bits
+	y9 := uint32(y.l4 >> 26)
+
+	x1_2, x3_2, x5_2, x7_2, x9_2 := x1<<1, x3<<1, x5<<1, x7<<1, x9<<1
+	y1_19, y2_19, y3_19 := y1*19, y2*19, y3*19
+	y4_19, y5_19, y6_19 := y4*19, y5*19, y6*19
+	y7_19, y8_19, y9_19 := y7*19, y8*19, y9*19
+
+	h0 := uint64(x0)*uint64(y0) +
+		uint64(x1_2)*uint64(y9_19) +
+		uint64(x2)*uint64(y8_19) +
+		uint64(x3_2)*uint64(y7_19) +
+		uint64(x4)*uint64(y6_19) +
+		uint64(x5_2)*uint64(y5_19) +
+		uint64(x6)*uint64(y4_19) +
+		uint64(x7_2)*uint64(y3_19) +
+		uint64(x8)*uint64(y2_19) +
+		uint64(x9_2)*uint64(y1_19)
+	h1 := uint64(x0)*uint64(y1) +
+		uint64(x1)*uint64(y0) +
+		uint64(x2)*uint64(y9_19) +
+		uint64(x3)*uint64(y8_19) +
+		uint64(x4)*uint64(y7_19) +
+		uint64(x5)*uint64(y6_19) +
+		uint64(x6)*uint64(y5_19) +
+		uint64(x7)*uint64(y4_19) +
+		uint64(x8)*uint64(y3_19) +
+		uint64(x9)*uint64(y2_19)
+	h2 := uint64(x0)*uint64(y2) +
+		uint64(x1_2)*uint64(y1) +
+		uint64(x2)*uint64(y0) +
+		uint64(x3_2)*uint64(y9_19) +
+		uint64(x4)*uint64(y8_19) +
+		uint64(x5_2)*uint64(y7_19) +
+		uint64(x6)*uint64(y6_19) +
+		uint64(x7_2)*uint64(y5_19) +
+		uint64(x8)*uint64(y4_19) +
+		uint64(x9_2)*uint64(y3_19)
+	h3 := uint64(x0)*uint64(y3) +
+		uint64(x1)*uint64(y2) +
+		uint64(x2)*uint64(y1) +
+		uint64(x3)*uint64(y0) +
+		uint64(x4)*uint64(y9_19) +
+		uint64(x5)*uint64(y8_19) +
+		uint64(x6)*uint64(y7_19) +
+		uint64(x7)*uint64(y6_19) +
+		uint64(x8)*uint64(y5_19) +
+		uint64(x9)*uint64(y4_19)
+	h4 := uint64(x0)*uint64(y4) +
+		uint64(x1_2)*uint64(y3) +
+		uint64(x2)*uint64(y2) +
+		uint64(x3_2)*uint64(y1) +
+		uint64(x4)*uint64(y0) +
+		uint64(x5_2)*uint64(y9_19) +
+		uint64(x6)*uint64(y8_19) +
+		uint64(x7_2)*uint64(y7_19) +
+		uint64(x8)*uint64(y6_19) +
+		uint64(x9_2)*uint64(y5_19)
+	h5 := uint64(x0)*uint64(y5) +
+		uint64(x1)*uint64(y4) +
+		uint64(x2)*uint64(y3) +
+		uint64(x3)*uint64(y2) +
+		uint64(x4)*uint64(y1) +
+		uint64(x5)*uint64(y0) +
+		uint64(x6)*uint64(y9_19) +
+		uint64(x7)*uint64(y8_19) +
+		uint64(x8)*uint64(y7_19) +
+		uint64(x9)*uint64(y6_19)
+	h6 := uint64(x0)*uint64(y6) +
+		uint64(x1_2)*uint64(y5) +
+		uint64(x2)*uint64(y4) +
+		uint64(x3_2)*uint64(y3) +
+		uint64(x4)*uint64(y2) +
+		uint64(x5_2)*uint64(y1) +
+		uint64(x6)*uint64(y0) +
+		uint64(x7_2)*uint64(y9_19) +
+		uint64(x8)*uint64(y8_19) +
+		uint64(x9_2)*uint64(y7_19)
+	h7 := uint64(x0)*uint64(y7) +
+		uint64(x1)*uint64(y6) +
+		uint64(x2)*uint64(y5) +
+		uint64(x3)*uint64(y4) +
+		uint64(x4)*uint64(y3) +
+		uint64(x5)*uint64(y2) +
+		uint64(x6)*uint64(y1) +
+		uint64(x7)*uint64(y0) +
+		uint64(x8)*uint64(y9_19) +
+		uint64(x9)*uint64(y8_19)
+	h8 := uint64(x0)*uint64(y8) +
+		uint64(x1_2)*uint64(y7) +
+		uint64(x2)*uint64(y6) +
+		uint64(x3_2)*uint64(y5) +
+		uint64(x4)*uint64(y4) +
+		uint64(x5_2)*uint64(y3) +
+		uint64(x6)*uint64(y2) +
+		uint64(x7_2)*uint64(y1) +
+		uint64(x8)*uint64(y0) +
+		uint64(x9_2)*uint64(y9_19)
+	h9 := uint64(x0)*uint64(y9) +
+		uint64(x1)*uint64(y8) +
+		uint64(x2)*uint64(y7) +
+		uint64(x3)*uint64(y6) +
+		uint64(x4)*uint64(y5) +
+		uint64(x5)*uint64(y4) +
+		uint64(x6)*uint64(y3) +
+		uint64(x7)*uint64(y2) +
+		uint64(x8)*uint64(y1) +
+		uint64(x9)*uint64(y0)
+
+	feCarry32(v, h0, h1, h2, h3, h4, h5, h6, h7, h8, h9)
+}
+
+func feSquare32(v, x *fieldElement) {
+	x0 := uint32(x.l0) & maskLow26Bits
+	x1 := uint32(x.l0 >> 26)
+	x2 := uint32(x.l1) & maskLow26Bits
+	x3 := uint32(x.l1 >> 26)
+	x4 := uint32(x.l2) & maskLow26Bits
+	x5 := uint32(x.l2 >> 26)
+	x6 := uint32(x.l3) & maskLow26Bits
+	x7 := uint32(x.l3 >> 26)
+	x8 := uint32(x.l4) & maskLow26Bits
+	x9 := uint32(x.l4 >> 26)
+
+	x0_2, x1_2, x2_2, x3_2, x4_2 := x0<<1, x1<<1, x2<<1, x3<<1, x4<<1
+	x5_2, x6_2, x7_2, x8_2, x9_2 := x5<<1, x6<<1, x7<<1, x8<<1, x9<<1
+	x5_19, x6_19, x7_19, x8_19, x9_19 := x5*19, x6*19, x7*19, x8*19, x9*19
+	x7_38, x9_38 := x7*38, x9*38
+
+	h0 := uint64(x0)*uint64(x0) +
+		uint64(x1_2)*uint64(x9_38) +
+		uint64(x2_2)*uint64(x8_19) +
+		uint64(x3_2)*uint64(x7_38) +
+		uint64(x4_2)*uint64(x6_19) +
+		uint64(x5_2)*uint64(x5_19)
+	h1 := uint64(x0_2)*uint64(x1) +
+		uint64(x2_2)*uint64(x9_19) +
+		uint64(x3_2)*uint64(x8_19) +
+		uint64(x4_2)*uint64(x7_19) +
+		uint64(x5_2)*uint64(x6_19)
+	h2 := uint64(x0_2)*uint64(x2) +
+		uint64(x1_2)*uint64(x1) +
+		uint64(x3_2)*uint64(x9_38) +
+		uint64(x4_2)*uint64(x8_19) +
+		uint64(x5_2)*uint64(x7_38) +
+		uint64(x6)*uint64(x6_19)
+	h3 := uint64(x0_2)*uint64(x3) +
+		uint64(x1_2)*uint64(x2) +
+		uint64(x4_2)*uint64(x9_19) +
+		uint64(x5_2)*uint64(x8_19) +
+		uint64(x6_2)*uint64(x7_19)
+	h4 := uint64(x0_2)*uint64(x4) +
+		uint64(x1_2)*uint64(x3_2) +
+		uint64(x2)*uint64(x2) +
+		uint64(x5_2)*uint64(x9_38) +
+		uint64(x6_2)*uint64(x8_19) +
+		uint64(x7_2)*uint64(x7_19)
+	h5 := uint64(x0_2)*uint64(x5) +
+		uint64(x1_2)*uint64(x4) +
+		uint64(x2_2)*uint64(x3) +
+		uint64(x6_2)*uint64(x9_19) +
+		uint64(x7_2)*uint64(x8_19)
+	h6 := uint64(x0_2)*uint64(x6) +
+		uint64(x1_2)*uint64(x5_2) +
+		uint64(x2_2)*uint64(x4) +
+		uint64(x3_2)*uint64(x3) +
+		uint64(x7_2)*uint64(x9_38) +
+		uint64(x8)*uint64(x8_19)
+	h7 := uint64(x0_2)*uint64(x7) +
+		uint64(x1_2)*uint64(x6) +
+		uint64(x2_2)*uint64(x5) +
+		uint64(x3_2)*uint64(x4) +
+		uint64(x8_2)*uint64(x9_19)
+	h8 := uint64(x0_2)*uint64(x8) +
+		uint64(x1_2)*uint64(x7_2) +
+		uint64(x2_2)*uint64(x6) +
+		uint64(x3_2)*uint64(x5_2) +
+		uint64(x4)*uint64(x4) +
+		uint64(x9_2)*uint64(x9_19)
+	h9 := uint64(x0_2)*uint64(x9) +
+		uint64(x1_2)*uint64(x8) +
+		uint64(x2_2)*uint64(x7) +
+		uint64(x3_2)*uint64(x6) +
+		uint64(x4_2)*uint64(x5)
+
+	feCarry32(v, h0, h1, h2, h3, h4, h5, h6, h7, h8, h9)
+}
+
+// feCarry32 carries the radix 2^25.5 coefficients h0 to h9 into limbs of 26
+// and 25 bits, and sets v to their recombination into 51-bit limbs.
+func feCarry32(v *fieldElement, h0, h1, h2, h3, h4, h5, h6, h7, h8, h9 uint64) {
+	h1 += h0 >> 26
+	h0 &= maskLow26Bits
+	h2 += h1 >> 25
+	h1 &= maskLow25Bits
+	h3 += h2 >> 26
+	h2 &= maskLow26Bits
+	h4 += h3 >> 25
+	h3 &= maskLow25Bits
+	h5 += h4 >> 26
+	h4 &= maskLow26Bits
+	h6 += h5 >> 25
+	h5 &= maskLow25Bits
+	h7 += h6 >> 26
+	h6 &= maskLow26Bits
+	h8 += h7 >> 25
+	h7 &= maskLow25Bits
+	h9 += h8 >> 26
+	h8 &= maskLow26Bits
+	h0 += (h9 >> 25) * 19
+	h9 &= maskLow25Bits
+	h1 += h0 >> 26
+	h0 &= maskLow26Bits
+
+	v.l0 = h0 | h1<<26
+	v.l1 = h2 | h3<<26
+	v.l2 = h4 | h5<<26
+	v.l3 = h6 | h7<<26
+	v.l4 = h8 | h9<<26
+}
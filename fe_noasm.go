@@ -3,6 +3,7 @@
 // license that can be found in the LICENSE file.
 
 // +build !amd64,!arm64 !gc purego
+// +build !386,!arm,!mips,!mipsle,!wasm
 
 package edwards25519
 
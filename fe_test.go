@@ -404,6 +404,99 @@ func TestInvert(t *testing.T) {
 	}
 }
 
+func TestInvertBatch(t *testing.T) {
+	invertBatchMatches := func(a, b, c fieldElement) bool {
+		v := []fieldElement{{}, a, {}, b, c, {}}
+		invertBatch(v)
+		var expected fieldElement
+		for i, x := range []fieldElement{{}, a, {}, b, c, {}} {
+			if v[i].Equal(expected.Invert(&x)) != 1 {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(invertBatchMatches, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+
+	invertBatch(nil)
+}
+
+func TestSetCanonicalBytes(t *testing.T) {
+	roundTrip := func(x fieldElement) bool {
+		var v fieldElement
+		_, err := v.setCanonicalBytes(x.Bytes())
+		return err == nil && v.Equal(&x) == 1 && bytes.Equal(v.Bytes(), x.Bytes())
+	}
+	if err := quick.Check(roundTrip, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		x    string
+	}{
+		{"p", "edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f"},
+		{"p + 1", "eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f"},
+		{"2^255 - 1", "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f"},
+		{"2^255", "0000000000000000000000000000000000000000000000000000000000000080"},
+		{"2^256 - 1", "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"},
+		{"short", "00"},
+	} {
+		v := *sqrtM1
+		if _, err := v.setCanonicalBytes(decodeHex(tt.x)); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+		if v.Equal(sqrtM1) != 1 {
+			t.Errorf("%s: receiver was modified", tt.name)
+		}
+	}
+	// p - 1 is the largest canonical encoding.
+	pMinusOne := decodeHex("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	if v, err := new(fieldElement).setCanonicalBytes(pMinusOne); err != nil || v.Equal(feMinusOne) != 1 {
+		t.Errorf("p - 1 was not decoded as -1")
+	}
+}
+
+func TestSetWideBytes(t *testing.T) {
+	p, _ := new(big.Int).SetString("7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffed", 16)
+	setWideBytesMatches := func(x [64]byte, n uint8) bool {
+		in := x[:n%65]
+		expected := new(big.Int).SetBytes(swapEndianness(append([]byte{}, in...)))
+		expected.Mod(expected, p)
+		var v fieldElement
+		v.setWideBytes(in)
+		return isInBounds(&v) && v.toBig().Cmp(expected) == 0
+	}
+	if err := quick.Check(setWideBytesMatches, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+
+	var ones [64]byte
+	for i := range ones {
+		ones[i] = 0xff
+	}
+	for n := uint8(0); n <= 64; n++ {
+		if !setWideBytesMatches(ones, n) {
+			t.Errorf("all ones input of %d bytes failed", n)
+		}
+	}
+}
+
+func TestSetWideBytesBigEndian(t *testing.T) {
+	matchesLittleEndian := func(x [64]byte, n uint8) bool {
+		in := x[:n%65]
+		var v, expected fieldElement
+		v.setWideBytesBigEndian(in)
+		expected.setWideBytes(swapEndianness(append([]byte{}, in...)))
+		return v.Equal(&expected) == 1
+	}
+	if err := quick.Check(matchesLittleEndian, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestSelectSwap(t *testing.T) {
 	a := fieldElement{358744748052810, 1691584618240980, 977650209285361, 1429865912637724, 560044844278676}
 	b := fieldElement{84926274344903, 473620666599931, 365590438845504, 1028470286882429, 2146499180330972}
@@ -451,6 +544,25 @@ func TestMul32(t *testing.T) {
 	if err := quick.Check(mul32EquivalentToMul, quickCheckConfig1024); err != nil {
 		t.Error(err)
 	}
+
+	// Limbs just below 2^52 and the largest constant produce the largest
+	// carries, and chaining checks the output bounds are good inputs.
+	maxLimbs := fieldElement{1<<52 - 1, 1<<52 - 1, 1<<52 - 1, 1<<52 - 1, 1<<52 - 1}
+	for _, x := range []fieldElement{maxLimbs, *feMinusOne, *sqrtM1} {
+		for _, y := range []uint32{0, 1, 19, 121666, 1<<32 - 1} {
+			if !mul32EquivalentToMul(x, y) {
+				t.Errorf("Mult32(%v, %d) does not match Multiply", x, y)
+			}
+			var chained, expected fieldElement
+			chained.Mult32(&x, y)
+			chained.Mult32(&chained, y)
+			expected.Multiply(&x, &fieldElement{uint64(y), 0, 0, 0, 0})
+			expected.Multiply(&expected, &fieldElement{uint64(y), 0, 0, 0, 0})
+			if chained.Equal(&expected) != 1 || !isInBounds(&chained) {
+				t.Errorf("chained Mult32(%v, %d) does not match Multiply", x, y)
+			}
+		}
+	}
 }
 
 func TestSqrtRatio(t *testing.T) {
@@ -512,6 +624,39 @@ func TestSqrtRatio(t *testing.T) {
 	}
 }
 
+func TestSqrtRatioBig(t *testing.T) {
+	p, _ := new(big.Int).SetString("7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffed", 16)
+	i := sqrtM1.toBig()
+	sqrtRatioMatchesBig := func(u, v fieldElement) bool {
+		// Subtract expects b.l0 to be a little below 2^52, like the outputs of
+		// the arithmetic operations, which the weird inputs might exceed.
+		u.reduce()
+		v.reduce()
+		if v.Equal(feZero) == 1 {
+			return true
+		}
+		// ratio = u / v, or i * u / v if that is not square.
+		ratio := new(big.Int).ModInverse(v.toBig(), p)
+		ratio.Mul(ratio, u.toBig())
+		ratio.Mod(ratio, p)
+		square := big.Jacobi(ratio, p) >= 0
+		if !square {
+			ratio.Mul(ratio, i)
+			ratio.Mod(ratio, p)
+		}
+		expected := new(big.Int).ModSqrt(ratio, p)
+		if expected.Bit(0) == 1 {
+			expected.Sub(p, expected)
+		}
+
+		r, wasSquare := new(fieldElement).SqrtRatio(&u, &v)
+		return r.toBig().Cmp(expected) == 0 && (wasSquare == 1) == square
+	}
+	if err := quick.Check(sqrtRatioMatchesBig, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestCarryPropagate(t *testing.T) {
 	asmLikeGeneric := func(a [5]uint64) bool {
 		t1 := &fieldElement{a[0], a[1], a[2], a[3], a[4]}
@@ -535,3 +680,85 @@ func TestCarryPropagate(t *testing.T) {
 		t.Errorf("failed for {0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff, 0xffffffffffffffff}")
 	}
 }
+
+func TestFeMulAsmLikeGeneric(t *testing.T) {
+	// The outputs are only compared as field elements, since the assembly
+	// carries differently and can return a different limb representation.
+	// These are the limb patterns with the largest intermediate products:
+	// all limbs just below 2^52, and p - 1 and p in reduced form.
+	adversarial := []fieldElement{
+		{1<<52 - 1, 1<<52 - 1, 1<<52 - 1, 1<<52 - 1, 1<<52 - 1},
+		{1<<51 - 20, 1<<51 - 1, 1<<51 - 1, 1<<51 - 1, 1<<51 - 1},
+		{1<<51 - 19, 1<<51 - 1, 1<<51 - 1, 1<<51 - 1, 1<<51 - 1},
+	}
+
+	mulLikeGeneric := func(x, y fieldElement) bool {
+		var t1, t2 fieldElement
+		feMul(&t1, &x, &y)
+		feMulGeneric(&t2, &x, &y)
+		return t1.Equal(&t2) == 1 && isInBounds(&t1)
+	}
+	squareLikeGeneric := func(x fieldElement) bool {
+		var t1, t2 fieldElement
+		feSquare(&t1, &x)
+		feSquareGeneric(&t2, &x)
+		return t1.Equal(&t2) == 1 && isInBounds(&t1)
+	}
+
+	if err := quick.Check(mulLikeGeneric, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+	if err := quick.Check(squareLikeGeneric, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+	for _, x := range adversarial {
+		if !squareLikeGeneric(x) {
+			t.Errorf("feSquare(%v) does not match feSquareGeneric", x)
+		}
+		for _, y := range adversarial {
+			if !mulLikeGeneric(x, y) {
+				t.Errorf("feMul(%v, %v) does not match feMulGeneric", x, y)
+			}
+		}
+	}
+}
+
+func TestFeMul32(t *testing.T) {
+	mul32LikeGeneric := func(x, y fieldElement) bool {
+		var t1, t2 fieldElement
+		feMul32(&t1, &x, &y)
+		feMulGeneric(&t2, &x, &y)
+		return t1.Equal(&t2) == 1 && isInBounds(&t1)
+	}
+	square32LikeGeneric := func(x fieldElement) bool {
+		var t1, t2 fieldElement
+		feSquare32(&t1, &x)
+		feSquareGeneric(&t2, &x)
+		return t1.Equal(&t2) == 1 && isInBounds(&t1)
+	}
+
+	if err := quick.Check(mul32LikeGeneric, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+	if err := quick.Check(square32LikeGeneric, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+
+	// Maximal limbs produce the largest coefficients, and chaining checks
+	// the output bounds are good inputs.
+	max := fieldElement{1<<52 - 1, 1<<52 - 1, 1<<52 - 1, 1<<52 - 1, 1<<52 - 1}
+	if !mul32LikeGeneric(max, max) || !square32LikeGeneric(max) {
+		t.Errorf("maximal limbs failed")
+	}
+	var t1, t2 fieldElement
+	t1, t2 = max, max
+	for i := 0; i < 100; i++ {
+		feMul32(&t1, &t1, &max)
+		feSquare32(&t1, &t1)
+		feMulGeneric(&t2, &t2, &max)
+		feSquareGeneric(&t2, &t2)
+	}
+	if t1.Equal(&t2) != 1 {
+		t.Errorf("chained operations failed")
+	}
+}
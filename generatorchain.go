@@ -0,0 +1,126 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import "sync"
+
+// A GeneratorChain is a pair of generator vectors G_0, G_1, ... and H_0, H_1,
+// ... derived from a domain label, as used by Bulletproofs range proofs, with
+// precomputed lookup tables for multiscalar multiplications over them. The
+// vectors are extended as needed, and their existing elements are reused.
+//
+// The generators are
+//
+//     G_i = DeriveGenerator("GeneratorChain G " + domain, i)
+//     H_i = DeriveGenerator("GeneratorChain H " + domain, i)
+//
+// which, like the BulletproofGens of the dalek-cryptography bulletproofs
+// crate, derive the two vectors independently from a label, but using RFC
+// 9380 hash to curve instead of a SHAKE256 stream mapped to ristretto255, so
+// they are not interoperable. The base pair of the commitments is that of
+// DefaultPedersenParams.
+//
+// A GeneratorChain is safe for concurrent use.
+type GeneratorChain struct {
+	gDomain, hDomain string
+
+	mu sync.Mutex
+	// tables holds the tables of G_0, H_0, G_1, H_1, ..., so that any prefix
+	// covers the first elements of both vectors. It's replaced, not modified,
+	// when extended.
+	tables *MSMTables
+}
+
+// NewGeneratorChain returns a new GeneratorChain for the domain label, with the
+// first capacity generators of each vector already derived.
+func NewGeneratorChain(domain string, capacity int) *GeneratorChain {
+	c := &GeneratorChain{
+		gDomain: "GeneratorChain G " + domain,
+		hDomain: "GeneratorChain H " + domain,
+		tables:  &MSMTables{},
+	}
+	c.Extend(capacity)
+	return c
+}
+
+// Len returns the number of generators derived so far in each vector.
+func (c *GeneratorChain) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tables.Len() / 2
+}
+
+// Extend derives the generators of each vector up to n, if they weren't
+// already. n must not be negative.
+func (c *GeneratorChain) Extend(n int) {
+	c.prefix(n)
+}
+
+// prefix returns the tables of G_0, H_0, ..., G_(n-1), H_(n-1), extending the
+// chain if necessary.
+func (c *GeneratorChain) prefix(n int) *MSMTables {
+	if n < 0 {
+		panic("edwards25519: negative number of generators")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if have := c.tables.Len() / 2; have < n {
+		points := make([]*Point, 0, 2*(n-have))
+		for i := have; i < n; i++ {
+			points = append(points, DeriveGenerator(c.gDomain, uint64(i)),
+				DeriveGenerator(c.hDomain, uint64(i)))
+		}
+		c.tables = c.tables.extend(points)
+	}
+	return c.tables.prefix(2 * n)
+}
+
+// Base returns the base pair of the commitments, DefaultPedersenParams.
+func (c *GeneratorChain) Base() *PedersenParams {
+	return DefaultPedersenParams()
+}
+
+// Generators returns copies of G_0, ..., G_(n-1) and H_0, ..., H_(n-1),
+// extending the chain if necessary.
+func (c *GeneratorChain) Generators(n int) (G, H []*Point) {
+	t := c.prefix(n)
+	G, H = make([]*Point, n), make([]*Point, n)
+	for i := 0; i < n; i++ {
+		G[i] = (&Point{}).Set(&t.points[2*i])
+		H[i] = (&Point{}).Set(&t.points[2*i+1])
+	}
+	return G, H
+}
+
+// interleave returns gScalars and hScalars interleaved, to match the order of
+// the tables.
+func interleave(gScalars, hScalars []*Scalar) []*Scalar {
+	if len(gScalars) != len(hScalars) {
+		panic("edwards25519: called GeneratorChain multiscalar multiplication with different size inputs")
+	}
+	scalars := make([]*Scalar, 0, 2*len(gScalars))
+	for i := range gScalars {
+		scalars = append(scalars, gScalars[i], hScalars[i])
+	}
+	return scalars
+}
+
+// MultiScalarMult returns sum(gScalars[i] * G_i + hScalars[i] * H_i), extending
+// the chain if necessary. gScalars and hScalars must have the same length.
+//
+// Execution time depends only on the lengths of the two slices.
+func (c *GeneratorChain) MultiScalarMult(gScalars, hScalars []*Scalar) *Point {
+	scalars := interleave(gScalars, hScalars)
+	return (&Point{}).MultiScalarMultWithTables(scalars, c.prefix(len(gScalars)))
+}
+
+// VarTimeMultiScalarMult is like MultiScalarMult, but executes in variable
+// time, which is faster and suitable for verifiers working with public values.
+//
+// Execution time depends on the inputs.
+func (c *GeneratorChain) VarTimeMultiScalarMult(gScalars, hScalars []*Scalar) *Point {
+	scalars := interleave(gScalars, hScalars)
+	return (&Point{}).VarTimeMultiScalarMultWithTables(scalars, c.prefix(len(gScalars)))
+}
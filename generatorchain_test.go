@@ -0,0 +1,126 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	mathrand "math/rand"
+	"sync"
+	"testing"
+)
+
+func TestGeneratorChainVectors(t *testing.T) {
+	// These outputs are a compatibility promise, and must never change.
+	G, H := NewGeneratorChain("test", 0).Generators(2)
+	for i, tt := range []struct{ g, h string }{
+		{"940fc6fcf8985e4f212293f6f95796c32d1890ee78389f2cf689e1b85cbcab6f",
+			"a91b2862e0ed7df845ffff752012813b3349193b2ea612baceaa5dd8ffa2e5a6"},
+		{"e93620ee1bb72ec849cbb9a2997daf8ee3d7d2466118530e3487eef054d2c65d",
+			"da95dec207553b0a1c4e22c871d390dab50e1779628de9f4e2cc657dab351bff"},
+	} {
+		if g := G[i].Bytes(); !bytes.Equal(g, decodeHex(tt.g)) {
+			t.Errorf("G_%d = %x, expected %s", i, g, tt.g)
+		}
+		if h := H[i].Bytes(); !bytes.Equal(h, decodeHex(tt.h)) {
+			t.Errorf("H_%d = %x, expected %s", i, h, tt.h)
+		}
+		if G[i].Equal(DeriveGenerator("GeneratorChain G test", uint64(i))) != 1 ||
+			H[i].Equal(DeriveGenerator("GeneratorChain H test", uint64(i))) != 1 {
+			t.Errorf("generators %d don't match DeriveGenerator", i)
+		}
+	}
+}
+
+func TestGeneratorChainExtend(t *testing.T) {
+	c := NewGeneratorChain("test", 4)
+	if c.Len() != 4 {
+		t.Fatalf("got Len() = %d, expected 4", c.Len())
+	}
+	G4, H4 := c.Generators(4)
+	G8, H8 := c.Generators(8)
+	if c.Len() != 8 {
+		t.Fatalf("got Len() = %d, expected 8", c.Len())
+	}
+	c.Extend(2)
+	if c.Len() != 8 {
+		t.Fatalf("got Len() = %d after a smaller Extend, expected 8", c.Len())
+	}
+
+	// A chain extended incrementally matches one derived all at once.
+	G, H := NewGeneratorChain("test", 8).Generators(8)
+	for i := 0; i < 8; i++ {
+		if G8[i].Equal(G[i]) != 1 || H8[i].Equal(H[i]) != 1 {
+			t.Errorf("generators %d changed with lazy extension", i)
+		}
+		if i < 4 && (G4[i].Equal(G[i]) != 1 || H4[i].Equal(H[i]) != 1) {
+			t.Errorf("generators %d changed after extension", i)
+		}
+	}
+
+	// The returned generators are copies.
+	G8[0].Add(G8[0], B)
+	if G, _ := c.Generators(1); G[0].Equal(G4[0]) != 1 {
+		t.Errorf("G_0 was modified through Generators")
+	}
+
+	// Different domains produce different generators.
+	Gother, _ := NewGeneratorChain("other", 1).Generators(1)
+	if Gother[0].Equal(G[0]) == 1 {
+		t.Errorf("different domains produced the same generator")
+	}
+}
+
+func TestGeneratorChainMultiScalarMult(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	c := NewGeneratorChain("test", 2)
+	for _, n := range []int{0, 1, 3, 2, 9} {
+		gScalars, _ := randomMultiScalarMultInputs(rand, n)
+		hScalars, _ := randomMultiScalarMultInputs(rand, n)
+		G, H := c.Generators(n)
+		want := (&Point{}).VarTimeMultiScalarMult(append(gScalars, hScalars...), append(G, H...))
+		got := c.MultiScalarMult(gScalars, hScalars)
+		gotVarTime := c.VarTimeMultiScalarMult(gScalars, hScalars)
+		checkOnCurve(t, got, gotVarTime)
+		if got.Equal(want) != 1 || gotVarTime.Equal(want) != 1 {
+			t.Errorf("n = %d: multiscalar multiplication does not match", n)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("different size inputs did not panic")
+		}
+	}()
+	c.MultiScalarMult([]*Scalar{&scOne}, nil)
+}
+
+func TestGeneratorChainConcurrent(t *testing.T) {
+	c := NewGeneratorChain("test", 0)
+	G, H := NewGeneratorChain("test", 16).Generators(16)
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for n := w; n <= 16; n += 4 {
+				scalars := make([]*Scalar, n)
+				for i := range scalars {
+					scalars[i] = &scOne
+				}
+				want := AddCommitments(append(G[:n:n], H[:n]...)...)
+				if c.VarTimeMultiScalarMult(scalars, scalars).Equal(want) != 1 {
+					t.Errorf("n = %d: concurrent multiscalar multiplication does not match", n)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func BenchmarkGeneratorChainExtend(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewGeneratorChain("test", 64)
+	}
+}
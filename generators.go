@@ -0,0 +1,53 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import "encoding/binary"
+
+// deriveGeneratorDST is the hash to curve domain separation tag of
+// DeriveGenerator. The domain is part of the message instead, so that it can
+// have any length.
+var deriveGeneratorDST = []byte("edwards25519_XMD:SHA-512_ELL2_RO_DeriveGenerator")
+
+// DeriveGenerator returns a generator of the prime order subgroup derived from
+// domain and index, whose discrete logarithm with respect to any other point
+// is unknown. It's the output of HashToCurve for the message
+//
+//     domain || uint64be(index) || counter
+//
+// with the domain separation tag
+// "edwards25519_XMD:SHA-512_ELL2_RO_DeriveGenerator", where counter is a
+// single byte starting at zero, and incremented if the output is the identity
+// or the canonical generator. (That never happens in practice.)
+//
+// The output for a given domain and index will never change.
+func DeriveGenerator(domain string, index uint64) *Point {
+	msg := make([]byte, 0, len(domain)+8+1)
+	msg = append(msg, domain...)
+	msg = append(msg, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(msg[len(domain):], index)
+	for ctr := 0; ctr < 256; ctr++ {
+		msg[len(msg)-1] = byte(ctr)
+		p := HashToCurve(msg, deriveGeneratorDST)
+		if p.Equal(NewIdentityPoint()) == 1 || p.Equal(NewGeneratorPoint()) == 1 {
+			continue
+		}
+		return p
+	}
+	panic("edwards25519: DeriveGenerator failed to find a generator")
+}
+
+// DeriveGenerators returns the n generators DeriveGenerator(domain, i) for i
+// from 0 to n - 1.
+func DeriveGenerators(domain string, n int) []*Point {
+	if n < 0 {
+		panic("edwards25519: negative number of generators")
+	}
+	points := make([]*Point, n)
+	for i := range points {
+		points[i] = DeriveGenerator(domain, uint64(i))
+	}
+	return points
+}
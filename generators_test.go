@@ -0,0 +1,69 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDeriveGenerator(t *testing.T) {
+	// These outputs are a compatibility promise, and must never change.
+	for _, tt := range []struct {
+		domain string
+		index  uint64
+		out    string
+	}{
+		{"", 0, "1dd93b4b5fc5f5616238938ba449c018b8cc278470b20efa20324b6fa0707c19"},
+		{"", 1, "9a2710cedb49998bc8c1d9c5276ae8a6053bb2145942b9769c6f57b740dce9ab"},
+		{"", 2, "589f0ea1d57e87f323ffcc8a929f815ce45f1547fe53c13538af6d12a17ce52c"},
+		{"test domain", 0, "dbd950adedf78bb78bb7e496352741b005dcf4fc4dc216724ac2225b52f6b27e"},
+		{"test domain", 1, "3cb23bde6ebfb57aad55c5e668ee7e720e25704881d18ac49be14bbb70c3bcd0"},
+		{"test domain", 2, "5d7f0d70258619ae6825bb6f9280fbc1f1568b875de345d6274be53400471cc6"},
+		{"test domain", 1 << 63, "afaf11a92e519d69dc818326ce69660a8994660007774ba2e470f036e07eaa36"},
+	} {
+		p := DeriveGenerator(tt.domain, tt.index)
+		if out := p.Bytes(); !bytes.Equal(out, decodeHex(tt.out)) {
+			t.Errorf("DeriveGenerator(%q, %d) = %x, expected %s", tt.domain, tt.index, out, tt.out)
+		}
+	}
+}
+
+func TestDeriveGenerators(t *testing.T) {
+	const n = 64
+	seen := make(map[[32]byte]bool)
+	seen[[32]byte{1}] = true // identity
+	var basepoint [32]byte
+	copy(basepoint[:], B.Bytes())
+	seen[basepoint] = true
+
+	for _, domain := range []string{"", "test domain", "test domain\x00"} {
+		points := DeriveGenerators(domain, n)
+		if len(points) != n {
+			t.Fatalf("%q: got %d generators, expected %d", domain, len(points), n)
+		}
+		for i, p := range points {
+			checkOnCurve(t, p)
+			if p.Equal(DeriveGenerator(domain, uint64(i))) != 1 {
+				t.Errorf("%q: generator %d doesn't match DeriveGenerator", domain, i)
+			}
+			// p has prime order l if (l - 1) * p + p is the identity.
+			q := (&Point{}).ScalarMult(&scMinusOne, p)
+			if q.Add(q, p).Equal(I) != 1 {
+				t.Errorf("%q: generator %d is not in the prime order subgroup", domain, i)
+			}
+			var enc [32]byte
+			copy(enc[:], p.Bytes())
+			if seen[enc] {
+				t.Errorf("%q: generator %d is the identity, the basepoint, or a repeat", domain, i)
+			}
+			seen[enc] = true
+		}
+	}
+
+	if len(DeriveGenerators("", 0)) != 0 {
+		t.Errorf("expected no generators")
+	}
+}
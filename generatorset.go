@@ -0,0 +1,145 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+// A GeneratorSet is a fixed blinding generator H and a vector of generators
+// G_0, ..., G_(n-1), with precomputed lookup tables for committing to vectors
+// of values, as in Pedersen vector commitments
+//
+//     C = r * H + v_0 * G_0 + ... + v_(k-1) * G_(k-1)
+//
+// A GeneratorSet is immutable once built, and is safe for concurrent use.
+type GeneratorSet struct {
+	// tables holds the tables of H followed by those of the generators.
+	tables *MSMTables
+}
+
+// NewGeneratorSet returns a new GeneratorSet for the blinding generator h and
+// the vector of generators.
+func NewGeneratorSet(h *Point, generators []*Point) *GeneratorSet {
+	points := make([]*Point, 0, len(generators)+1)
+	points = append(points, h)
+	points = append(points, generators...)
+	return &GeneratorSet{tables: PrecomputeMSMTables(points)}
+}
+
+// Len returns the number of generators in s, not counting the blinding
+// generator.
+func (s *GeneratorSet) Len() int {
+	return s.tables.Len() - 1
+}
+
+// prefix returns the scalars and tables to commit to values with blinding,
+// using the first len(values) generators.
+func (s *GeneratorSet) prefix(values []*Scalar, blinding *Scalar) ([]*Scalar, *MSMTables) {
+	if len(values) > s.Len() {
+		panic("edwards25519: committed to more values than generators")
+	}
+	n := len(values) + 1
+	scalars := make([]*Scalar, 0, n)
+	scalars = append(scalars, blinding)
+	scalars = append(scalars, values...)
+	return scalars, s.tables.prefix(n)
+}
+
+// Commit returns blinding * H + sum(values[i] * G_i). If values is shorter
+// than the set, only the first len(values) generators are used. Commit panics
+// if values is longer than the set.
+//
+// Execution time depends only on the length of values.
+func (s *GeneratorSet) Commit(values []*Scalar, blinding *Scalar) *Point {
+	scalars, tables := s.prefix(values, blinding)
+	return (&Point{}).MultiScalarMultWithTables(scalars, tables)
+}
+
+// CommitVarTime is like Commit, but executes in variable time, which is
+// faster and suitable for verifiers working with public values.
+//
+// Execution time depends on the inputs.
+func (s *GeneratorSet) CommitVarTime(values []*Scalar, blinding *Scalar) *Point {
+	scalars, tables := s.prefix(values, blinding)
+	return (&Point{}).VarTimeMultiScalarMultWithTables(scalars, tables)
+}
+
+// subset returns the tables of the generators at indices, which may repeat.
+func (s *GeneratorSet) subset(indices []int) *MSMTables {
+	n, size := s.Len(), nafTableSize(5)
+	t := &MSMTables{
+		points:    make([]Point, len(indices)),
+		tables:    make([]projLookupTable, len(indices)),
+		nafTables: make([]projCached, len(indices)*size),
+	}
+	for j, i := range indices {
+		if i < 0 || i >= n {
+			panic("edwards25519: generator index out of range")
+		}
+		t.points[j] = s.tables.points[i+1]
+		t.tables[j] = s.tables.tables[i+1]
+		copy(t.nafTables[j*size:(j+1)*size], s.tables.nafTables[(i+1)*size:(i+2)*size])
+	}
+	return t
+}
+
+// An UpdatableCommitment is a vector commitment over a GeneratorSet, which can
+// be updated in place when a value or the blinding factor changes, at the cost
+// of a scalar multiplication per changed position, instead of a multiscalar
+// multiplication over the whole vector.
+//
+// An UpdatableCommitment must not be used by multiple goroutines at the same
+// time.
+type UpdatableCommitment struct {
+	set *GeneratorSet
+	c   Point
+}
+
+// NewUpdatableCommitment returns an UpdatableCommitment over s, set to
+// s.Commit(values, blinding).
+func NewUpdatableCommitment(s *GeneratorSet, values []*Scalar, blinding *Scalar) *UpdatableCommitment {
+	u := &UpdatableCommitment{set: s}
+	u.c.Set(s.Commit(values, blinding))
+	return u
+}
+
+// Point returns a copy of the current commitment.
+func (u *UpdatableCommitment) Point() *Point {
+	return (&Point{}).Set(&u.c)
+}
+
+// Update changes the value at index i from oldValue to newValue, by adding
+// (newValue - oldValue) * G_i to the commitment. Update panics if i is out of
+// range for the GeneratorSet.
+//
+// Execution time doesn't depend on the values.
+func (u *UpdatableCommitment) Update(i int, oldValue, newValue *Scalar) {
+	u.UpdateBatch([]int{i}, []*Scalar{oldValue}, []*Scalar{newValue})
+}
+
+// UpdateBatch is like calling Update for each index with the corresponding old
+// and new values, but with a single multiscalar multiplication. Indices may
+// repeat, in which case the updates are applied in order. The three slices
+// must have the same length.
+//
+// Execution time depends only on the length of the slices.
+func (u *UpdatableCommitment) UpdateBatch(indices []int, oldValues, newValues []*Scalar) {
+	if len(indices) != len(oldValues) || len(indices) != len(newValues) {
+		panic("edwards25519: called UpdateBatch with different size inputs")
+	}
+	tables := u.set.subset(indices)
+	deltas := make([]*Scalar, len(indices))
+	for j := range indices {
+		deltas[j] = NewScalar().Subtract(newValues[j], oldValues[j])
+	}
+	u.c.Add(&u.c, (&Point{}).MultiScalarMultWithTables(deltas, tables))
+}
+
+// UpdateBlinding changes the blinding factor from oldR to newR, by adding
+// (newR - oldR) * H to the commitment.
+//
+// Execution time doesn't depend on the blinding factors.
+func (u *UpdatableCommitment) UpdateBlinding(oldR, newR *Scalar) {
+	delta := NewScalar().Subtract(newR, oldR)
+	h := u.set.tables.prefix(1)
+	u.c.Add(&u.c, (&Point{}).MultiScalarMultWithTables([]*Scalar{delta}, h))
+}
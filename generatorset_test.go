@@ -0,0 +1,209 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	mathrand "math/rand"
+	"testing"
+)
+
+func naiveCommit(h *Point, generators []*Point, values []*Scalar, blinding *Scalar) *Point {
+	c := (&Point{}).ScalarMult(blinding, h)
+	for i := range values {
+		c.Add(c, (&Point{}).ScalarMult(values[i], generators[i]))
+	}
+	return c
+}
+
+func TestGeneratorSetCommit(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, generators := randomMultiScalarMultInputs(rand, 16)
+	h := (&Point{}).ScalarBaseMult(&dalekScalar)
+	set := NewGeneratorSet(h, generators)
+	if set.Len() != len(generators) {
+		t.Fatalf("got Len() = %d, expected %d", set.Len(), len(generators))
+	}
+
+	for _, n := range []int{0, 1, 2, 7, 16} {
+		values, _ := randomMultiScalarMultInputs(rand, n)
+		blinding := Scalar{}.Generate(rand, 0).Interface().(Scalar)
+		want := naiveCommit(h, generators, values, &blinding)
+		got := set.Commit(values, &blinding)
+		gotVarTime := set.CommitVarTime(values, &blinding)
+		checkOnCurve(t, got, gotVarTime)
+		if got.Equal(want) != 1 || gotVarTime.Equal(want) != 1 {
+			t.Errorf("n = %d: commitment does not match ScalarMult and Add", n)
+		}
+	}
+
+	zeros := make([]*Scalar, len(generators))
+	for i := range zeros {
+		zeros[i] = NewScalar()
+	}
+	if set.Commit(zeros, NewScalar()).Equal(I) != 1 {
+		t.Error("commitment to the zero vector with zero blinding is not the identity")
+	}
+	if set.CommitVarTime(zeros, &scOne).Equal(h) != 1 {
+		t.Error("commitment to the zero vector with unit blinding is not H")
+	}
+}
+
+func TestGeneratorSetTooManyValues(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	values, generators := randomMultiScalarMultInputs(rand, 4)
+	set := NewGeneratorSet(B, generators[:3])
+	for name, commit := range map[string]func([]*Scalar, *Scalar) *Point{
+		"Commit": set.Commit, "CommitVarTime": set.CommitVarTime,
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic with more values than generators", name)
+				}
+			}()
+			commit(values, &scOne)
+		}()
+	}
+}
+
+func BenchmarkGeneratorSetCommit(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	values, generators := randomMultiScalarMultInputs(rand, 64)
+	set := NewGeneratorSet(B, generators)
+	scalars := append([]*Scalar{&dalekScalar}, values...)
+	points := append([]*Point{B}, generators...)
+	b.Run("Commit", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			set.Commit(values, &dalekScalar)
+		}
+	})
+	b.Run("MultiScalarMult", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.MultiScalarMult(scalars, points)
+		}
+	})
+	b.Run("CommitVarTime", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			set.CommitVarTime(values, &dalekScalar)
+		}
+	})
+	b.Run("VarTimeMultiScalarMult", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeMultiScalarMult(scalars, points)
+		}
+	})
+}
+
+func TestUpdatableCommitment(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, generators := randomMultiScalarMultInputs(rand, 16)
+	h := (&Point{}).ScalarBaseMult(&dalekScalar)
+	set := NewGeneratorSet(h, generators)
+
+	values, _ := randomMultiScalarMultInputs(rand, 16)
+	blinding := Scalar{}.Generate(rand, 0).Interface().(Scalar)
+	u := NewUpdatableCommitment(set, values, &blinding)
+
+	for round := 0; round < 32; round++ {
+		switch round % 3 {
+		case 0:
+			i := rand.Intn(len(values))
+			newValues, _ := randomMultiScalarMultInputs(rand, 1)
+			u.Update(i, values[i], newValues[0])
+			values[i] = newValues[0]
+		case 1:
+			// Repeated indices are applied in order.
+			indices := []int{rand.Intn(len(values)), rand.Intn(len(values)), 0, 0}
+			newValues, _ := randomMultiScalarMultInputs(rand, len(indices))
+			oldValues := make([]*Scalar, len(indices))
+			for j, i := range indices {
+				oldValues[j] = values[i]
+				values[i] = newValues[j]
+			}
+			u.UpdateBatch(indices, oldValues, newValues)
+		case 2:
+			newBlinding := Scalar{}.Generate(rand, 0).Interface().(Scalar)
+			u.UpdateBlinding(&blinding, &newBlinding)
+			blinding = newBlinding
+		}
+		if u.Point().Equal(set.Commit(values, &blinding)) != 1 {
+			t.Fatalf("round %d: updated commitment does not match recomputation", round)
+		}
+	}
+
+	// An empty batch is a no-op, and the returned point is a copy.
+	before := u.Point()
+	u.UpdateBatch(nil, nil, nil)
+	u.Point().Add(before, B)
+	if u.Point().Equal(before) != 1 {
+		t.Errorf("commitment changed without updates")
+	}
+}
+
+func TestUpdatableCommitmentOutOfRange(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	values, generators := randomMultiScalarMultInputs(rand, 4)
+	set := NewGeneratorSet(B, generators)
+	u := NewUpdatableCommitment(set, values, &scOne)
+	before := u.Point()
+	for _, tt := range []struct {
+		name   string
+		update func()
+	}{
+		{"negative index", func() { u.Update(-1, &scOne, &scOne) }},
+		{"index equal to length", func() { u.Update(4, &scOne, &scOne) }},
+		{"out of range in batch", func() {
+			u.UpdateBatch([]int{0, 5}, []*Scalar{&scOne, &scOne}, []*Scalar{&scZero, &scZero})
+		}},
+		{"different size inputs", func() {
+			u.UpdateBatch([]int{0, 1}, []*Scalar{&scOne}, []*Scalar{&scZero, &scZero})
+		}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: did not panic", tt.name)
+				}
+			}()
+			tt.update()
+		}()
+		if u.Point().Equal(before) != 1 {
+			t.Errorf("%s: commitment changed", tt.name)
+		}
+	}
+}
+
+func BenchmarkUpdatableCommitment(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	values, generators := randomMultiScalarMultInputs(rand, 1024)
+	set := NewGeneratorSet(B, generators)
+	b.Run("Recommit", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			values[i%1024] = &dalekScalar
+			set.Commit(values, &scOne)
+		}
+	})
+	b.Run("Update", func(b *testing.B) {
+		u := NewUpdatableCommitment(set, values, &scOne)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			u.Update(i%1024, values[i%1024], &dalekScalar)
+		}
+	})
+	b.Run("UpdateBatch8", func(b *testing.B) {
+		u := NewUpdatableCommitment(set, values, &scOne)
+		indices := []int{0, 100, 200, 300, 400, 500, 600, 700}
+		oldValues, newValues := make([]*Scalar, 8), make([]*Scalar, 8)
+		for j, i := range indices {
+			oldValues[j], newValues[j] = values[i], &dalekScalar
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			u.UpdateBatch(indices, oldValues, newValues)
+		}
+	})
+}
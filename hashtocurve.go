@@ -0,0 +1,194 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"crypto/sha512"
+	"errors"
+)
+
+// HashToCurve hashes msg to a point with the edwards25519_XMD:SHA-512_ELL2_RO_
+// suite of RFC 9380, with the domain separation tag dst, and returns it. The
+// output is uniformly distributed in the prime order subgroup.
+//
+// dst must not be empty, and should be unique to the protocol and its use of
+// HashToCurve, as described in RFC 9380, Section 3.1.
+func HashToCurve(msg, dst []byte) *Point {
+	var u [2]fieldElement
+	hashToField(u[:], msg, dst)
+	p := mapToCurveElligator2(&u[0])
+	p.Add(p, mapToCurveElligator2(&u[1]))
+	return p.MultByCofactor(p)
+}
+
+// EncodeToCurve hashes msg to a point with the edwards25519_XMD:SHA-512_ELL2_NU_
+// suite of RFC 9380, with the domain separation tag dst, and returns it. It is
+// faster than HashToCurve, but its output is not uniformly distributed, and
+// only a fraction of the points in the prime order subgroup can be returned.
+//
+// dst must not be empty, and should be unique to the protocol and its use of
+// EncodeToCurve, as described in RFC 9380, Section 3.1.
+func EncodeToCurve(msg, dst []byte) *Point {
+	var u [1]fieldElement
+	hashToField(u[:], msg, dst)
+	p := mapToCurveElligator2(&u[0])
+	return p.MultByCofactor(p)
+}
+
+// MapToCurveElligator2 returns the output of the map_to_curve function of the
+// edwards25519 suites of RFC 9380, Section 6.8.2, for the field element
+// encoded in u, without clearing the cofactor. It's the building block of
+// HashToCurve and EncodeToCurve, for protocols that derive field elements
+// differently.
+//
+// u must be 32 bytes long. As in RFC 7748, the most significant bit is ignored,
+// and non-canonical values are reduced. The map is computed in constant time.
+func MapToCurveElligator2(u []byte) (*Point, error) {
+	if len(u) != 32 {
+		return nil, errors.New("edwards25519: invalid field element length")
+	}
+	return mapToCurveElligator2(new(fieldElement).SetBytes(u)), nil
+}
+
+// hashToField sets u to len(u) field elements derived from msg and dst,
+// according to RFC 9380, Section 5.2, with expand_message_xmd and SHA-512.
+func hashToField(u []fieldElement, msg, dst []byte) {
+	// L = ceil((ceil(log2(p)) + k) / 8), where k = 128 is the security level.
+	const L = 48
+	uniform := expandMessageXMD(msg, dst, L*len(u))
+	for i := range u {
+		u[i].setWideBytesBigEndian(uniform[i*L : (i+1)*L])
+	}
+}
+
+// expandMessageXMD implements expand_message_xmd from RFC 9380, Section 5.3.1,
+// with SHA-512.
+func expandMessageXMD(msg, dst []byte, length int) []byte {
+	if len(dst) == 0 {
+		panic("edwards25519: empty hash to curve domain separation tag")
+	}
+	const bInBytes, sInBytes = sha512.Size, sha512.BlockSize
+	ell := (length + bInBytes - 1) / bInBytes
+	if ell > 255 || length > 65535 {
+		panic("edwards25519: invalid expand_message_xmd length")
+	}
+
+	// Tags longer than 255 bytes are hashed, as in RFC 9380, Section 5.3.3.
+	if len(dst) > 255 {
+		h := sha512.New()
+		h.Write([]byte("H2C-OVERSIZE-DST-"))
+		h.Write(dst)
+		dst = h.Sum(nil)
+	}
+	dstPrime := append(dst[:len(dst):len(dst)], byte(len(dst)))
+
+	h := sha512.New()
+	h.Write(make([]byte, sInBytes))
+	h.Write(msg)
+	h.Write([]byte{byte(length >> 8), byte(length), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	out := make([]byte, 0, ell*bInBytes)
+	out = append(out, bi...)
+	for i := 2; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j]
+		}
+		h.Reset()
+		h.Write(bi)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(bi[:0])
+		out = append(out, bi...)
+	}
+	return out[:length]
+}
+
+// montgomeryA is the A coefficient of Curve25519, 486662.
+var montgomeryA = &fieldElement{486662, 0, 0, 0, 0}
+
+// sqrtMinusAPlus2 is the non-negative square root of -(A + 2) = -486664, used
+// in the rational map from Curve25519 to edwards25519.
+var sqrtMinusAPlus2 = func() *fieldElement {
+	v, _ := new(fieldElement).SqrtRatio(new(fieldElement).Negate(&fieldElement{486664, 0, 0, 0, 0}), feOne)
+	return v
+}()
+
+// mapToCurveElligator2 maps u to a point with the Elligator 2 method for
+// Curve25519, followed by the rational map to edwards25519, as specified in
+// RFC 9380, Section 6.8.2 and Appendix D.1. The output is not cofactor
+// cleared. The computation is done in constant time.
+func mapToCurveElligator2(u *fieldElement) *Point {
+	// The candidate Montgomery x-coordinates are x1 = -A / (1 + 2u²) and
+	// x2 = -x1 - A = -A * 2u² / (1 + 2u²), with a shared denominator xd. One
+	// of g(x1) and g(x2) is square, where g(x) = x³ + Ax² + x.
+	var tv1, xd, x1n, x2n fieldElement
+	tv1.Square(u)
+	tv1.Add(&tv1, &tv1)
+	xd.Add(&tv1, feOne)
+	x1n.Negate(montgomeryA)
+	x2n.Multiply(&x1n, &tv1)
+
+	// g(xn / xd) = (xn³ + A xn² xd + xn xd²) / xd³
+	var xd2, gxd, gx1, gx2 fieldElement
+	xd2.Square(&xd)
+	gxd.Multiply(&xd2, &xd)
+	montgomeryG := func(gx, xn *fieldElement) {
+		var t fieldElement
+		t.Multiply(montgomeryA, &xd)
+		t.Add(&t, xn)
+		t.Multiply(&t, xn)
+		t.Add(&t, &xd2)
+		gx.Multiply(&t, xn)
+	}
+	montgomeryG(&gx1, &x1n)
+	montgomeryG(&gx2, &x2n)
+
+	var y1, y2 fieldElement
+	_, e1 := y1.SqrtRatio(&gx1, &gxd)
+	y2.SqrtRatio(&gx2, &gxd)
+
+	// If g(x1) is square, y is the negative root, otherwise the non-negative
+	// root of g(x2), to match sgn0 as specified.
+	var xn, y fieldElement
+	xn.Select(&x1n, &x2n, e1)
+	y1.Negate(&y1)
+	y.Select(&y1, &y2, e1)
+
+	// The rational map is (x, y) -> (sqrt(-486664) x / y, (x - 1) / (x + 1)),
+	// which in projective coordinates with x = xn / xd is
+	//
+	//     X = sqrt(-486664) xn (xn + xd)
+	//     Y = (xn - xd) xd y
+	//     Z = xd y (xn + xd)
+	//     T = sqrt(-486664) xn (xn - xd)
+	//
+	// The exceptional cases, where y = 0 or x = -1, map to the identity.
+	var sum, diff, t fieldElement
+	sum.Add(&xn, &xd)
+	diff.Subtract(&xn, &xd)
+	p := &Point{}
+	t.Multiply(sqrtMinusAPlus2, &xn)
+	p.x.Multiply(&t, &sum)
+	p.t.Multiply(&t, &diff)
+	t.Multiply(&xd, &y)
+	p.y.Multiply(&t, &diff)
+	p.z.Multiply(&t, &sum)
+
+	exceptional := p.z.Equal(feZero)
+	identity := NewIdentityPoint()
+	p.x.Select(&identity.x, &p.x, exceptional)
+	p.y.Select(&identity.y, &p.y, exceptional)
+	p.z.Select(&identity.z, &p.z, exceptional)
+	p.t.Select(&identity.t, &p.t, exceptional)
+	return p
+}
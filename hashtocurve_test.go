@@ -0,0 +1,171 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// hashToCurveVectors are the test vectors from RFC 9380, Appendix J.5.1, with
+// DST = "QUUX-V01-CS02-with-edwards25519_XMD:SHA-512_ELL2_RO_".
+var hashToCurveVectors = []struct {
+	msg  string
+	x, y string
+	u    []string
+}{
+	{
+		"",
+		"3c3da6925a3c3c268448dcabb47ccde5439559d9599646a8260e47b1e4822fc6",
+		"09a6c8561a0b22bef63124c588ce4c62ea83a3c899763af26d795302e115dc21",
+		[]string{"03fef4813c8cb5f98c6eef88fae174e6e7d5380de2b007799ac7ee712d203f3a", "780bdddd137290c8f589dc687795aafae35f6b674668d92bf92ae793e6a60c75"},
+	},
+	{
+		"abc",
+		"608040b42285cc0d72cbb3985c6b04c935370c7361f4b7fbdb1ae7f8c1a8ecad",
+		"1a8395b88338f22e435bbd301183e7f20a5f9de643f11882fb237f88268a5531",
+		[]string{"5081955c4141e4e7d02ec0e36becffaa1934df4d7a270f70679c78f9bd57c227", "005bdc17a9b378b6272573a31b04361f21c371b256252ae5463119aa0b925b76"},
+	},
+	{
+		"abcdef0123456789",
+		"6d7fabf47a2dc03fe7d47f7dddd21082c5fb8f86743cd020f3fb147d57161472",
+		"53060a3d140e7fbcda641ed3cf42c88a75411e648a1add71217f70ea8ec561a6",
+		[]string{"285ebaa3be701b79871bcb6e225ecc9b0b32dff2d60424b4c50642636a78d5b3", "2e253e6a0ef658fedb8e4bd6a62d1544fd6547922acb3598ec6b369760b81b31"},
+	},
+	{
+		"q128_" + strings.Repeat("q", 128),
+		"5fb0b92acedd16f3bcb0ef83f5c7b7a9466b5f1e0d8d217421878ea3686f8524",
+		"2eca15e355fcfa39d2982f67ddb0eea138e2994f5956ed37b7f72eea5e89d2f7",
+		[]string{"4fedd25431c41f2a606952e2945ef5e3ac905a42cf64b8b4d4a83c533bf321af", "02f20716a5801b843987097a8276b6d869295b2e11253751ca72c109d37485a9"},
+	},
+	{
+		"a512_" + strings.Repeat("a", 512),
+		"0efcfde5898a839b00997fbe40d2ebe950bc81181afbd5cd6b9618aa336c1e8c",
+		"6dc2fc04f266c5c27f236a80b14f92ccd051ef1ff027f26a07f8c0f327d8f995",
+		[]string{"6e34e04a5106e9bd59f64aba49601bf09d23b27f7b594e56d5de06df4a4ea33b", "1c1c2cb59fc053f44b86c5d5eb8c1954b64976d0302d3729ff66e84068f5fd96"},
+	},
+}
+
+// encodeToCurveVectors are the test vectors from RFC 9380, Appendix J.5.2, with
+// DST = "QUUX-V01-CS02-with-edwards25519_XMD:SHA-512_ELL2_NU_".
+var encodeToCurveVectors = []struct {
+	msg  string
+	x, y string
+	u    []string
+}{
+	{
+		"",
+		"1ff2b70ecf862799e11b7ae744e3489aa058ce805dd323a936375a84695e76da",
+		"222e314d04a4d5725e9f2aff9fb2a6b69ef375a1214eb19021ceab2d687f0f9b",
+		[]string{"7f3e7fb9428103ad7f52db32f9df32505d7b427d894c5093f7a0f0374a30641d"},
+	},
+	{
+		"abc",
+		"5f13cc69c891d86927eb37bd4afc6672360007c63f68a33ab423a3aa040fd2a8",
+		"67732d50f9a26f73111dd1ed5dba225614e538599db58ba30aaea1f5c827fa42",
+		[]string{"09cfa30ad79bd59456594a0f5d3a76f6b71c6787b04de98be5cd201a556e253b"},
+	},
+	{
+		"abcdef0123456789",
+		"1dd2fefce934ecfd7aae6ec998de088d7dd03316aa1847198aecf699ba6613f1",
+		"2f8a6c24dd1adde73909cada6a4a137577b0f179d336685c4a955a0a8e1a86fb",
+		[]string{"475ccff99225ef90d78cc9338e9f6a6bb7b17607c0c4428937de75d33edba941"},
+	},
+	{
+		"q128_" + strings.Repeat("q", 128),
+		"35fbdc5143e8a97afd3096f2b843e07df72e15bfca2eaf6879bf97c5d3362f73",
+		"2af6ff6ef5ebba128b0774f4296cb4c2279a074658b083b8dcca91f57a603450",
+		[]string{"049a1c8bd51bcb2aec339f387d1ff51428b88d0763a91bcdf6929814ac95d03d"},
+	},
+	{
+		"a512_" + strings.Repeat("a", 512),
+		"6e5e1f37e99345887fc12111575fc1c3e36df4b289b8759d23af14d774b66bff",
+		"2c90c3d39eb18ff291d33441b35f3262cdd307162cc97c31bfcc7a4245891a37",
+		[]string{"3cb0178a8137cefa5b79a3a57c858d7eeeaa787b2781be4a362a2f0750d24fa0"},
+	},
+}
+
+func TestHashToCurve(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-edwards25519_XMD:SHA-512_ELL2_RO_")
+	for _, v := range hashToCurveVectors {
+		checkHashToFieldVector(t, v.msg, dst, v.u)
+		checkAffineCoordinates(t, HashToCurve([]byte(v.msg), dst), v.x, v.y)
+	}
+}
+
+func TestEncodeToCurve(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-edwards25519_XMD:SHA-512_ELL2_NU_")
+	for _, v := range encodeToCurveVectors {
+		checkHashToFieldVector(t, v.msg, dst, v.u)
+		checkAffineCoordinates(t, EncodeToCurve([]byte(v.msg), dst), v.x, v.y)
+	}
+}
+
+func checkHashToFieldVector(t *testing.T, msg string, dst []byte, expected []string) {
+	t.Helper()
+	u := make([]fieldElement, len(expected))
+	hashToField(u, []byte(msg), dst)
+	for i := range u {
+		if got := u[i].toBig().Text(16); got != strings.TrimLeft(expected[i], "0") {
+			t.Errorf("%.10q: u[%d] = %s, expected %s", msg, i, got, expected[i])
+		}
+	}
+}
+
+func checkAffineCoordinates(t *testing.T, p *Point, x, y string) {
+	t.Helper()
+	checkOnCurve(t, p)
+	var zInv, px, py fieldElement
+	zInv.Invert(&p.z)
+	px.Multiply(&p.x, &zInv)
+	py.Multiply(&p.y, &zInv)
+	expectedX, _ := new(big.Int).SetString(x, 16)
+	expectedY, _ := new(big.Int).SetString(y, 16)
+	if px.toBig().Cmp(expectedX) != 0 || py.toBig().Cmp(expectedY) != 0 {
+		t.Errorf("got (%x, %x), expected (%s, %s)", px.toBig(), py.toBig(), x, y)
+	}
+}
+
+func TestExpandMessageXMDLongDST(t *testing.T) {
+	// A tag longer than 255 bytes is replaced by its hash.
+	long := []byte(strings.Repeat("x", 256))
+	h := expandMessageXMD([]byte("msg"), long, 48)
+	if len(h) != 48 {
+		t.Fatalf("got %d bytes, expected 48", len(h))
+	}
+	if string(h) == string(expandMessageXMD([]byte("msg"), long[:255], 48)) {
+		t.Error("long tag was truncated instead of hashed")
+	}
+}
+
+func TestMapToCurveExceptional(t *testing.T) {
+	// u = 0 maps to the Montgomery point (0, 0), which has order 2, and is an
+	// exceptional case of the rational map.
+	if p := mapToCurveElligator2(&fieldElement{}); p.Equal(I) != 1 {
+		t.Error("u = 0 did not map to the identity")
+	}
+}
+
+func TestMapToCurveElligator2(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-edwards25519_XMD:SHA-512_ELL2_NU_")
+	for _, v := range encodeToCurveVectors {
+		// The vectors are big-endian.
+		u := decodeHex(v.u[0])
+		for i, j := 0, len(u)-1; i < j; i, j = i+1, j-1 {
+			u[i], u[j] = u[j], u[i]
+		}
+		p, err := MapToCurveElligator2(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.MultByCofactor(p).Equal(EncodeToCurve([]byte(v.msg), dst)) != 1 {
+			t.Errorf("%.10q: cleared map output does not match EncodeToCurve", v.msg)
+		}
+	}
+	if _, err := MapToCurveElligator2(make([]byte, 31)); err == nil {
+		t.Error("expected error for short input")
+	}
+}
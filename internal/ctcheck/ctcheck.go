@@ -0,0 +1,56 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build ctcheck
+
+// Package ctcheck wraps the valgrind memcheck client requests used by the
+// ctcheck build of edwards25519. It is a separate package because cgo can't
+// be used in a package with Go assembly.
+//
+// The client requests are no-ops when not running under valgrind. Building
+// this package requires cgo and the valgrind headers.
+package ctcheck
+
+/*
+#include <stddef.h>
+#include <valgrind/memcheck.h>
+
+static void ct_poison(void *p, size_t n) { VALGRIND_MAKE_MEM_UNDEFINED(p, n); }
+static void ct_unpoison(void *p, size_t n) { VALGRIND_MAKE_MEM_DEFINED(p, n); }
+static unsigned ct_get_vbits(void *p, void *vbits, size_t n) { return VALGRIND_GET_VBITS(p, vbits, n); }
+static void ct_set_vbits(void *p, void *vbits, size_t n) { VALGRIND_SET_VBITS(p, vbits, n); }
+*/
+import "C"
+
+import "unsafe"
+
+// Poison marks the n bytes at p as undefined, so that valgrind reports any
+// branch or memory index that depends on them.
+func Poison(p unsafe.Pointer, n uintptr) {
+	C.ct_poison(p, C.size_t(n))
+}
+
+// Unpoison marks the n bytes at p as defined again.
+func Unpoison(p unsafe.Pointer, n uintptr) {
+	C.ct_unpoison(p, C.size_t(n))
+}
+
+// VBits returns the validity bits of the n bytes at p, where a set bit marks
+// an undefined bit, or nil if not running under valgrind.
+func VBits(p unsafe.Pointer, n uintptr) []byte {
+	vbits := make([]byte, n)
+	if n == 0 || C.ct_get_vbits(p, unsafe.Pointer(&vbits[0]), C.size_t(n)) != 1 {
+		return nil
+	}
+	return vbits
+}
+
+// SetVBits sets the validity bits of the len(vbits) bytes at p, as returned by
+// VBits. It does nothing if vbits is empty.
+func SetVBits(p unsafe.Pointer, vbits []byte) {
+	if len(vbits) == 0 {
+		return
+	}
+	C.ct_set_vbits(p, unsafe.Pointer(&vbits[0]), C.size_t(len(vbits)))
+}
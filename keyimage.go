@@ -0,0 +1,70 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import "errors"
+
+// keyImageDST is the hash to curve domain separation tag of
+// KeyImageHashToPoint.
+var keyImageDST = []byte("edwards25519_XMD:SHA-512_ELL2_RO_KeyImage")
+
+// KeyImageHashToPoint is the default hash to point function of KeyImage. It
+// returns HashToCurve(b) with the domain separation tag
+// "edwards25519_XMD:SHA-512_ELL2_RO_KeyImage", which is in the prime order
+// subgroup.
+//
+// It is not the hash to point function of Monero, and the key images it
+// produces are not compatible with it.
+func KeyImageHashToPoint(b []byte) *Point {
+	return HashToCurve(b, keyImageDST)
+}
+
+// KeyImage returns the key image x * hashToPoint(P) of the key pair (x, P),
+// for linkable ring signatures. If hashToPoint is nil, KeyImageHashToPoint is
+// used. The key image is the same every time the same key is used, but can't
+// be linked to P without knowing x.
+//
+// KeyImage returns an error if P is not x * B, if hashToPoint(P) has small
+// order, or if the key image is the identity. The key image is in the prime
+// order subgroup if hashToPoint returns points in it, as KeyImageHashToPoint
+// does.
+//
+// The multiplication is done in constant time.
+func KeyImage(x *Scalar, P *Point, hashToPoint func([]byte) *Point) (*Point, error) {
+	if hashToPoint == nil {
+		hashToPoint = KeyImageHashToPoint
+	}
+	if (&Point{}).ScalarBaseMult(x).Equal(P) != 1 {
+		return nil, errors.New("edwards25519: key image public key does not match the secret key")
+	}
+	H := hashToPoint(P.Bytes())
+	if (&Point{}).MultByCofactor(H).varTimeIsIdentity() {
+		return nil, errors.New("edwards25519: key image base point has small order")
+	}
+	I := (&Point{}).ScalarMult(x, H)
+	if I.Equal(NewIdentityPoint()) == 1 {
+		return nil, errors.New("edwards25519: key image is the identity")
+	}
+	return I, nil
+}
+
+// VerifyKeyImageTorsionFree reports whether the key image I is in the prime
+// order subgroup, and is not the identity.
+//
+// Verifiers of linkable ring signatures must check this before comparing key
+// images. Otherwise, adding a small order component to a key image can
+// produce up to eight different images of the same key accepted by the ring
+// signature equations, allowing double spends, as in the 2017 CryptoNote key
+// image bug.
+//
+// Execution time depends on the inputs.
+func VerifyKeyImageTorsionFree(I *Point) bool {
+	if I.varTimeIsIdentity() {
+		return false
+	}
+	// I has prime order l if (l - 1) * I + I is the identity.
+	p := (&Point{}).ScalarMult(&scMinusOne, I)
+	return p.Add(p, I).varTimeIsIdentity()
+}
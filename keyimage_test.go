@@ -0,0 +1,107 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import "testing"
+
+func TestKeyImage(t *testing.T) {
+	x := NewScalar().Set(&dalekScalar)
+	P := (&Point{}).ScalarBaseMult(x)
+	y := NewScalar().Add(x, &scOne)
+	Q := (&Point{}).ScalarBaseMult(y)
+
+	I1, err := KeyImage(x, P, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	I2, err := KeyImage(x, P, KeyImageHashToPoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	J, err := KeyImage(y, Q, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOnCurve(t, I1, J)
+
+	// Linkability: the same key gives the same image.
+	if I1.Equal(I2) != 1 {
+		t.Errorf("the same key produced different key images")
+	}
+	expected := (&Point{}).ScalarMult(x, HashToCurve(P.Bytes(), keyImageDST))
+	if I1.Equal(expected) != 1 {
+		t.Errorf("key image is not x * HashToCurve(P)")
+	}
+	// Unlinkability across keys.
+	if I1.Equal(J) == 1 {
+		t.Errorf("different keys produced the same key image")
+	}
+	if !VerifyKeyImageTorsionFree(I1) || !VerifyKeyImageTorsionFree(J) {
+		t.Errorf("key image is not torsion free")
+	}
+
+	// A custom hash to point function is used if supplied.
+	custom := func(b []byte) *Point { return EncodeToCurve(b, []byte("custom")) }
+	I3, err := KeyImage(x, P, custom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if I3.Equal(I1) == 1 {
+		t.Errorf("custom hash to point function was not used")
+	}
+}
+
+func TestKeyImageErrors(t *testing.T) {
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := NewScalar().Set(&dalekScalar)
+	P := (&Point{}).ScalarBaseMult(x)
+
+	if _, err := KeyImage(x, B, nil); err == nil {
+		t.Errorf("expected error for mismatched key pair")
+	}
+	if _, err := KeyImage(NewScalar(), I, nil); err == nil {
+		t.Errorf("expected error for zero secret key")
+	}
+	smallOrder := func([]byte) *Point { return lowOrder }
+	if _, err := KeyImage(x, P, smallOrder); err == nil {
+		t.Errorf("expected error for small order hash to point output")
+	}
+}
+
+func TestVerifyKeyImageTorsionFree(t *testing.T) {
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := NewScalar().Set(&dalekScalar)
+	image, err := KeyImage(x, (&Point{}).ScalarBaseMult(x), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyKeyImageTorsionFree(image) {
+		t.Errorf("valid key image rejected")
+	}
+
+	// A key image with a torsion component is a different point, which
+	// would be accepted as a new key image if not rejected.
+	torsioned := (&Point{}).Add(image, lowOrder)
+	if torsioned.Equal(image) == 1 {
+		t.Fatalf("adding a torsion component did not change the key image")
+	}
+	if VerifyKeyImageTorsionFree(torsioned) {
+		t.Errorf("key image with a torsion component accepted")
+	}
+	if (&Point{}).MultByCofactor(torsioned).Equal((&Point{}).MultByCofactor(image)) != 1 {
+		t.Errorf("torsioned key image is not equivalent modulo the cofactor")
+	}
+	for _, p := range []*Point{lowOrder, NewIdentityPoint()} {
+		if VerifyKeyImageTorsionFree(p) {
+			t.Errorf("small order key image %x accepted", p.Bytes())
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import "errors"
+
+// LagrangeCoefficients returns the Lagrange coefficients for interpolating at
+// zero a polynomial over the scalar field from its values at indices, such as
+// the participant identifiers of a Shamir or FROST threshold scheme. For a
+// polynomial f of degree less than len(indices),
+//
+//     f(0) = sum(coefficients[i] * f(indices[i]))
+//
+// where
+//
+//     coefficients[i] = prod(indices[j] / (indices[j] - indices[i])), for j != i
+//
+// The indices must be nonzero and distinct, or LagrangeCoefficients returns an
+// error. They are usually public, and execution time depends on them.
+func LagrangeCoefficients(indices []uint32) ([]*Scalar, error) {
+	if len(indices) == 0 {
+		return nil, errors.New("edwards25519: no indices for Lagrange interpolation")
+	}
+	seen := make(map[uint32]bool, len(indices))
+	xs := make([]Scalar, len(indices))
+	for i, index := range indices {
+		if index == 0 {
+			return nil, errors.New("edwards25519: zero index for Lagrange interpolation")
+		}
+		if seen[index] {
+			return nil, errors.New("edwards25519: duplicate index for Lagrange interpolation")
+		}
+		seen[index] = true
+		xs[i].SetUint64(uint64(index))
+	}
+
+	// coefficients[i] = prod(xs) / (xs[i] * prod(xs[j] - xs[i])), for j != i,
+	// so a single batch inversion of the denominators is enough.
+	num := NewScalar().Set(&scOne)
+	coefficients := make([]*Scalar, len(xs))
+	for i := range xs {
+		num.Multiply(num, &xs[i])
+		den := NewScalar().Set(&xs[i])
+		diff := NewScalar()
+		for j := range xs {
+			if j != i {
+				den.Multiply(den, diff.Subtract(&xs[j], &xs[i]))
+			}
+		}
+		coefficients[i] = den
+	}
+	InvertScalars(coefficients, coefficients)
+	for _, c := range coefficients {
+		c.Multiply(c, num)
+	}
+	return coefficients, nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	mathrand "math/rand"
+	"testing"
+)
+
+func TestLagrangeCoefficients(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{1, 2, 3, 5, 10} {
+		// A random polynomial f of degree n-1.
+		poly := randomScalars(rand, n)
+		eval := func(x uint32) *Scalar {
+			xs := NewScalar().SetUint64(uint64(x))
+			y := NewScalar()
+			for i := len(poly) - 1; i >= 0; i-- {
+				y.MultiplyAdd(y, xs, poly[i])
+			}
+			return y
+		}
+
+		indices := make([]uint32, n)
+		for i := range indices {
+			indices[i] = uint32(1+rand.Intn(1000))*uint32(n) + uint32(i) // distinct
+		}
+		if n == 10 {
+			indices[9] = 1<<32 - 1
+		}
+		coefficients, err := LagrangeCoefficients(indices)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := NewScalar()
+		for i, index := range indices {
+			got.MultiplyAdd(coefficients[i], eval(index), got)
+		}
+		if got.Equal(poly[0]) != 1 {
+			t.Errorf("n = %d: interpolation at zero is wrong", n)
+		}
+	}
+
+	// Known values for the indices 1, 2, 3: 3, -3, 1.
+	coefficients, err := LagrangeCoefficients([]uint32{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int64{3, -3, 1} {
+		if coefficients[i].Equal(NewScalar().SetInt64(want)) != 1 {
+			t.Errorf("coefficient %d is not %d", i, want)
+		}
+	}
+
+	for _, indices := range [][]uint32{nil, {}, {0}, {1, 0, 2}, {1, 2, 1}} {
+		if _, err := LagrangeCoefficients(indices); err == nil {
+			t.Errorf("expected an error for %v", indices)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"testing/quick"
+	"unsafe"
+)
+
+// enableLargeBasepointTableForTest computes a fresh width-10 table and makes
+// it active, bypassing largeBasepointOnce, which would otherwise only work the
+// first time. It checks that the table is in use, and returns a function that
+// restores the default table.
+func enableLargeBasepointTableForTest(t testing.TB) (table *nafLookupTable10, restore func()) {
+	table = &nafLookupTable10{}
+	table.FromP3(NewGeneratorPoint())
+	largeBasepointTable.Store(table)
+	checkBasepointTableWidth(t, 10)
+	return table, func() {
+		largeBasepointTable.Store((*nafLookupTable10)(nil))
+		checkBasepointTableWidth(t, defaultBasepointTableWidth())
+	}
+}
+
+// defaultBasepointTableWidth is the NAF width of the default basepoint table,
+// which is smaller with the ed25519_smalltables build tag.
+func defaultBasepointTableWidth() uint {
+	w, _ := basepointNafTableDefault()
+	return w
+}
+
+func checkBasepointTableWidth(t testing.TB, want uint) {
+	t.Helper()
+	if w, _ := basepointNafTableWide(); w != want {
+		t.Fatalf("basepoint table width is %d, want %d", w, want)
+	}
+}
+
+// withBasepointTables runs f once with the default basepoint table, and once
+// with the large one enabled.
+func withBasepointTables(t testing.TB, f func(t testing.TB)) {
+	checkBasepointTableWidth(t, defaultBasepointTableWidth())
+	f(t)
+	_, restore := enableLargeBasepointTableForTest(t)
+	defer restore()
+	f(t)
+}
+
+func TestEnableLargeBasepointTable(t *testing.T) {
+	checkBasepointTableWidth(t, defaultBasepointTableWidth())
+	// Reset the Once, in case an earlier run with -count already spent it.
+	largeBasepointOnce = sync.Once{}
+	EnableLargeBasepointTable()
+	defer largeBasepointTable.Store((*nafLookupTable10)(nil))
+	checkBasepointTableWidth(t, 10)
+	EnableLargeBasepointTable()
+	checkBasepointTableWidth(t, 10)
+}
+
+func TestLargeBasepointTable(t *testing.T) {
+	table, restore := enableLargeBasepointTableForTest(t)
+	restore()
+
+	for i := range basepointNafTable.points {
+		if table.points[i] != basepointNafTable.points[i] {
+			t.Fatalf("entry %d does not match basepointNafTable", i)
+		}
+	}
+	var x Scalar
+	for i := 0; i < len(table.points); i += 17 {
+		x.s[0], x.s[1] = byte(2*i+1), byte((2*i+1)>>8)
+		var p Point
+		p.ScalarBaseMult(&x)
+		var expected affineCached
+		expected.FromP3(&p)
+		if table.points[i] != expected {
+			t.Errorf("entry %d is not %d * B", i, 2*i+1)
+		}
+	}
+}
+
+func TestLargeBasepointTableVarTime(t *testing.T) {
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+	aTable := NewNafTablePoint(A)
+	withBasepointTables(t, func(t testing.TB) {
+		var p Point
+		p.VarTimeDoubleScalarBaseMult(NewScalar(), B, &dalekScalar)
+		if dalekScalarBasepoint.Equal(&p) != 1 {
+			t.Error("VarTimeDoubleScalarBaseMult does not match dalek")
+		}
+
+		matches := func(a, b Scalar) bool {
+			var check, p1, p2, p3 Point
+			check.MultiScalarMult([]*Scalar{&a, &b}, []*Point{A, B})
+			p1.VarTimeDoubleScalarBaseMult(&a, A, &b)
+			p2.varTimeDoubleScalarBaseMultWide(&a, A, &b)
+			p3.VarTimeDoubleScalarBaseMultPrecomputed(&a, aTable, &b)
+			return p1.Equal(&check) == 1 && p2.Equal(&check) == 1 && p3.Equal(&check) == 1
+		}
+		if err := quick.Check(matches, quickCheckConfig32); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestScalarNonAdjacentFormWide(t *testing.T) {
+	reconstruct := func(naf [256]int16) *big.Int {
+		sum := new(big.Int)
+		for i := 255; i >= 0; i-- {
+			sum.Lsh(sum, 1)
+			sum.Add(sum, big.NewInt(int64(naf[i])))
+		}
+		return sum
+	}
+	f := func(x Scalar) bool {
+		xBig := bigIntFromLittleEndianBytes(x.s[:])
+		for _, w := range []uint{5, 9, 10, 12} {
+			naf := x.nonAdjacentFormWide(w)
+			if reconstruct(naf).Cmp(xBig) != 0 {
+				return false
+			}
+			for i, d := range naf {
+				if d != 0 && (d%2 == 0 || d >= 1<<(w-1) || d <= -1<<(w-1)) {
+					return false
+				}
+				// Nonzero digits are at least w positions apart.
+				for j := i + 1; d != 0 && j < i+int(w) && j < 256; j++ {
+					if naf[j] != 0 {
+						return false
+					}
+				}
+			}
+		}
+		narrow := x.nonAdjacentForm(5)
+		for i, d := range x.nonAdjacentFormWide(5) {
+			if int16(narrow[i]) != d {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(f, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkLargeBasepointTable(b *testing.B) {
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+	run := func(b *testing.B, tableSize uintptr) {
+		b.ReportMetric(float64(tableSize), "table-bytes")
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeDoubleScalarBaseMult(&dalekScalar, A, &dalekScalar)
+		}
+	}
+	b.Run("default", func(b *testing.B) {
+		checkBasepointTableWidth(b, defaultBasepointTableWidth())
+		run(b, unsafe.Sizeof(basepointNafTable))
+	})
+	_, restore := enableLargeBasepointTableForTest(b)
+	defer restore()
+	b.Run("width-10", func(b *testing.B) {
+		checkBasepointTableWidth(b, 10)
+		run(b, unsafe.Sizeof(nafLookupTable10{}))
+	})
+}
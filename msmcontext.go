@@ -0,0 +1,145 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+// MSMContext holds the scratch buffers used by variable-time multiscalar
+// multiplications, so that they can be reused across calls. The buffers grow
+// as needed, and a call with no more inputs than a previous one performs no
+// heap allocations.
+//
+// The zero value is ready to use. An MSMContext must not be used by multiple
+// goroutines at the same time.
+type MSMContext struct {
+	nafWidth      uint
+	maxScalarBits int
+
+	scalars []*Scalar
+	points  []*Point
+	tables  []projCached
+	nafs    [][256]int8
+	digits  []int8
+	cached  []projCached
+	buckets []Point
+}
+
+// VarTimeMultiScalarMultWithContext is like VarTimeMultiScalarMult, but uses
+// the scratch buffers in c instead of allocating new ones.
+//
+// Execution time depends on the inputs.
+func (v *Point) VarTimeMultiScalarMultWithContext(c *MSMContext, scalars []*Scalar, points []*Point) *Point {
+	if len(scalars) != len(points) {
+		panic("edwards25519: called VarTimeMultiScalarMultWithContext with different size inputs")
+	}
+	checkInitialized(points...)
+
+	return v.varTimeMultiScalarMult(c, scalars, points)
+}
+
+// SetNAFWidth sets the NAF width used by the Straus method for subsequent
+// calls with c, overriding the default of 5, and returns c. A width of zero
+// restores the default. w must be zero or between 2 and 8, or
+// SetNAFWidth will panic.
+//
+// This is mostly useful for benchmarking.
+func (c *MSMContext) SetNAFWidth(w int) *MSMContext {
+	if w != 0 && (w < 2 || w > 8) {
+		panic("edwards25519: invalid NAF width")
+	}
+	c.nafWidth = uint(w)
+	return c
+}
+
+// nonZeroTerms returns the pairs of scalars and points where neither the
+// scalar is zero nor the point is the identity, as they don't contribute to
+// the sum. It runs in variable time.
+func (c *MSMContext) nonZeroTerms(scalars []*Scalar, points []*Point) ([]*Scalar, []*Point) {
+	c.scalars, c.points = c.scalars[:0], c.points[:0]
+	for i := range scalars {
+		if isZeroTerm(scalars[i], points[i]) {
+			continue
+		}
+		c.scalars = append(c.scalars, scalars[i])
+		c.points = append(c.points, points[i])
+	}
+	return c.scalars, c.points
+}
+
+// countNonZeroTerms returns the number of terms nonZeroTerms would keep,
+// without copying them.
+func countNonZeroTerms(scalars []*Scalar, points []*Point) int {
+	n := 0
+	for i := range scalars {
+		if !isZeroTerm(scalars[i], points[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+func isZeroTerm(s *Scalar, p *Point) bool {
+	return *s == (Scalar{}) || p.varTimeIsIdentity()
+}
+
+// clearTerms drops the references to the caller's scalars and points kept by
+// nonZeroTerms, so that c doesn't keep them alive, or reachable, after the call.
+func (c *MSMContext) clearTerms() {
+	for i := range c.scalars {
+		c.scalars[i] = nil
+	}
+	for i := range c.points {
+		c.points[i] = nil
+	}
+	c.scalars, c.points = c.scalars[:0], c.points[:0]
+}
+
+// SetMaxScalarBits declares that the scalars of subsequent calls with c are
+// lower than 2^bits, and returns c. Those calls panic if a scalar exceeds the
+// bound. A bound of zero removes it. bits must not be negative.
+//
+// Multiscalar multiplications already skip the doublings for the leading zero
+// bits shared by all scalars, so the bound is mostly an assertion, for example
+// that batch verification coefficients are 128 bits long.
+func (c *MSMContext) SetMaxScalarBits(bits int) *MSMContext {
+	if bits < 0 {
+		panic("edwards25519: invalid scalar bit bound")
+	}
+	c.maxScalarBits = bits
+	return c
+}
+
+func (c *MSMContext) nafTables(n int) []projCached {
+	if cap(c.tables) < n {
+		c.tables = make([]projCached, n)
+	}
+	return c.tables[:n]
+}
+
+func (c *MSMContext) nafDigits(n int) [][256]int8 {
+	if cap(c.nafs) < n {
+		c.nafs = make([][256]int8, n)
+	}
+	return c.nafs[:n]
+}
+
+func (c *MSMContext) radixDigits(n int) []int8 {
+	if cap(c.digits) < n {
+		c.digits = make([]int8, n)
+	}
+	return c.digits[:n]
+}
+
+func (c *MSMContext) cachedPoints(n int) []projCached {
+	if cap(c.cached) < n {
+		c.cached = make([]projCached, n)
+	}
+	return c.cached[:n]
+}
+
+func (c *MSMContext) bucketPoints(n int) []Point {
+	if cap(c.buckets) < n {
+		c.buckets = make([]Point, n)
+	}
+	return c.buckets[:n]
+}
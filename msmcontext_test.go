@@ -0,0 +1,128 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"fmt"
+	mathrand "math/rand"
+	"testing"
+)
+
+func TestMSMContext(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	c := &MSMContext{}
+	// Shrink and grow the context between calls, across both methods.
+	for _, n := range []int{8, 1, 300, 2, 0, 16} {
+		scalars, points := randomMultiScalarMultInputs(rand, n)
+		var p, check Point
+		p.VarTimeMultiScalarMultWithContext(c, scalars, points)
+		check.VarTimeMultiScalarMult(scalars, points)
+		if p.Equal(&check) != 1 {
+			t.Errorf("n = %d: result does not match VarTimeMultiScalarMult", n)
+		}
+	}
+}
+
+func TestMSMContextDropsInputs(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	scalars, points := randomMultiScalarMultInputs(rand, 16)
+	c := &MSMContext{}
+	var p Point
+	p.VarTimeMultiScalarMultWithContext(c, scalars, points)
+	if cap(c.scalars) == 0 || cap(c.points) == 0 {
+		t.Fatal("the context did not use its scratch slices")
+	}
+	for i, s := range c.scalars[:cap(c.scalars)] {
+		if s != nil {
+			t.Errorf("the context still references scalar %d", i)
+		}
+	}
+	for i, q := range c.points[:cap(c.points)] {
+		if q != nil {
+			t.Errorf("the context still references point %d", i)
+		}
+	}
+}
+
+func TestMSMContextAllocations(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{16, 300} {
+		scalars, points := randomMultiScalarMultInputs(rand, n)
+		c := &MSMContext{}
+		var p Point
+		p.VarTimeMultiScalarMultWithContext(c, scalars, points)
+		allocs := testing.AllocsPerRun(5, func() {
+			p.VarTimeMultiScalarMultWithContext(c, scalars, points)
+		})
+		if allocs != 0 {
+			t.Errorf("n = %d: expected zero allocations, got %v", n, allocs)
+		}
+	}
+}
+
+// random128BitScalars returns n random scalars lower than 2^128, like batch
+// verification coefficients.
+func random128BitScalars(rand *mathrand.Rand, n int) []*Scalar {
+	scalars := make([]*Scalar, n)
+	for i := range scalars {
+		var b [32]byte
+		rand.Read(b[:16])
+		scalars[i], _ = NewScalar().SetCanonicalBytes(b[:])
+	}
+	return scalars
+}
+
+func TestMSMContextMaxScalarBits(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{1, 16, 300} {
+		_, points := randomMultiScalarMultInputs(rand, n)
+		scalars := random128BitScalars(rand, n)
+		var p, q, check Point
+		c := (&MSMContext{}).SetMaxScalarBits(128)
+		p.VarTimeMultiScalarMultWithContext(c, scalars, points)
+		q.VarTimeMultiScalarMult(scalars, points)
+		check.MultiScalarMult(scalars, points)
+		if p.Equal(&check) != 1 || q.Equal(&check) != 1 {
+			t.Errorf("n = %d: result does not match MultiScalarMult", n)
+		}
+
+		scalars[n-1].Set(&scMinusOne)
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("n = %d: scalar above the bound did not panic", n)
+				}
+			}()
+			p.VarTimeMultiScalarMultWithContext(c, scalars, points)
+		}()
+
+		c.SetMaxScalarBits(0)
+		p.VarTimeMultiScalarMultWithContext(c, scalars, points)
+		check.MultiScalarMult(scalars, points)
+		if p.Equal(&check) != 1 {
+			t.Errorf("n = %d: result without bound does not match MultiScalarMult", n)
+		}
+	}
+}
+
+func BenchmarkVarTimeMultiScalarMult128Bit(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{64, 256} {
+		full, points := randomMultiScalarMultInputs(rand, n)
+		short := random128BitScalars(rand, n)
+		b.Run(fmt.Sprintf("%d/256-bit", n), func(b *testing.B) {
+			var p Point
+			for i := 0; i < b.N; i++ {
+				p.VarTimeMultiScalarMult(full, points)
+			}
+		})
+		b.Run(fmt.Sprintf("%d/128-bit", n), func(b *testing.B) {
+			var p Point
+			for i := 0; i < b.N; i++ {
+				p.VarTimeMultiScalarMult(short, points)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package oprf implements the base mode of the oblivious pseudorandom function
+// protocol of RFC 9497, over edwards25519 instead of ristretto255. This is not
+// one of the RFC 9497 suites, and is not interoperable with them. The
+// differences are
+//
+//   - the input is hashed with edwards25519.HashToCurve, with the domain
+//     separation tag "HashToGroup-OPRFV1-\x00-edwards25519-SHA512", and
+//   - Evaluate discards any small order component of the blinded point, so
+//     that a malicious client can't learn the key modulo the cofactor.
+//
+// The client calls Blind, sends the blinded point to the server, which calls
+// Evaluate with its secret key, and finally calls Unblind on the result, to
+// obtain key * HashToCurve(input).
+package oprf
+
+import (
+	"errors"
+	"io"
+
+	"filippo.io/edwards25519"
+)
+
+// dst is the HashToGroup domain separation tag, "HashToGroup-" ||
+// contextString, where contextString = "OPRFV1-" || I2OSP(mode, 1) || "-" ||
+// identifier, for the base mode 0x00.
+var dst = []byte("HashToGroup-OPRFV1-\x00-edwards25519-SHA512")
+
+// invEight is 1/8 mod l.
+var invEight = edwards25519.NewScalar().DivPow2(edwards25519.NewScalar().SetUint64(1), 3)
+
+// Blind hashes input to a point, and blinds it with a random scalar read from
+// rand, as in the Blind function of RFC 9497, Section 3.3.1. It returns the
+// blinded point to send to the server, and the blind to pass to Unblind.
+//
+// It returns an error if reading from rand fails, or if rand keeps returning
+// values that reduce to a zero blind.
+//
+// The blinding is done in constant time.
+func Blind(input []byte, rand io.Reader) (blindedPoint *edwards25519.Point, blind *edwards25519.Scalar, err error) {
+	P := edwards25519.HashToCurve(input, dst)
+	if P.Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return nil, nil, errors.New("oprf: input hashes to the identity")
+	}
+
+	blind = edwards25519.NewScalar()
+	var buf [64]byte
+	// A zero blind happens with negligible probability, so a reader that
+	// keeps producing one is stuck, and would otherwise make us loop forever.
+	for i := 0; i < 128; i++ {
+		if _, err := io.ReadFull(rand, buf[:]); err != nil {
+			return nil, nil, err
+		}
+		blind.SetUniformBytes(buf[:])
+		if blind.Equal(edwards25519.NewScalar()) == 0 {
+			return (&edwards25519.Point{}).ScalarMult(blind, P), blind, nil
+		}
+	}
+	return nil, nil, errors.New("oprf: random source returned too many zero blinds")
+}
+
+// Evaluate returns key * blinded, as in the BlindEvaluate function of RFC
+// 9497, Section 3.3.1, after discarding any small order component of blinded.
+// The caller should reject a blinded point that is the identity.
+//
+// The evaluation is done in constant time.
+func Evaluate(key *edwards25519.Scalar, blinded *edwards25519.Point) *edwards25519.Point {
+	// Multiplying by 8 and by 1/8 mod l leaves prime order points unchanged,
+	// and zeroes the small order component of others.
+	p := (&edwards25519.Point{}).ScalarMult(invEight, blinded)
+	p.MultByCofactor(p)
+	return p.ScalarMult(key, p)
+}
+
+// Unblind removes the blind from the point evaluated by the server, as in
+// the Finalize function of RFC 9497, Section 3.3.1, and returns
+// key * HashToCurve(input). It returns an error if evaluated is the identity.
+//
+// The unblinding is done in constant time.
+func Unblind(evaluated *edwards25519.Point, blind *edwards25519.Scalar) (*edwards25519.Point, error) {
+	if evaluated.Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return nil, errors.New("oprf: evaluation is the identity")
+	}
+	inv := edwards25519.NewScalar().Invert(blind)
+	return (&edwards25519.Point{}).ScalarMult(inv, evaluated), nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oprf
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	mathrand "math/rand"
+	"testing"
+
+	"filippo.io/edwards25519"
+)
+
+func TestOPRF(t *testing.T) {
+	r := mathrand.New(mathrand.NewSource(0))
+	var keyBytes [64]byte
+	r.Read(keyBytes[:])
+	key := edwards25519.NewScalar().SetUniformBytes(keyBytes[:])
+
+	for _, input := range []string{"", "input", "another input"} {
+		blinded, blind, err := Blind([]byte(input), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		evaluated := Evaluate(key, blinded)
+		output, err := Unblind(evaluated, blind)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The output matches the evaluation without blinding.
+		expected := Evaluate(key, edwards25519.HashToCurve([]byte(input), dst))
+		if output.Equal(expected) != 1 {
+			t.Errorf("%q: unblinded output does not match the unblinded evaluation", input)
+		}
+
+		// A second run uses a different blind, and the same output.
+		blinded2, blind2, err := Blind([]byte(input), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if blinded2.Equal(blinded) == 1 || blind2.Equal(blind) == 1 {
+			t.Errorf("%q: blinding is not randomized", input)
+		}
+		output2, err := Unblind(Evaluate(key, blinded2), blind2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output2.Equal(output) != 1 {
+			t.Errorf("%q: output depends on the blind", input)
+		}
+	}
+}
+
+func TestOPRFEvaluateSmallOrder(t *testing.T) {
+	// A small order component of the blinded point doesn't affect the
+	// evaluation, so it can't leak the key modulo the cofactor.
+	lowOrderBytes, _ := hex.DecodeString("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85")
+	lowOrder, err := (&edwards25519.Point{}).SetBytes(lowOrderBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	B := edwards25519.NewGeneratorPoint()
+	I := edwards25519.NewIdentityPoint()
+	var keyBytes [64]byte
+	mathrand.New(mathrand.NewSource(1)).Read(keyBytes[:])
+	key := edwards25519.NewScalar().SetUniformBytes(keyBytes[:])
+	mixed := (&edwards25519.Point{}).Add(B, lowOrder)
+	if Evaluate(key, mixed).Equal(Evaluate(key, B)) != 1 {
+		t.Error("small order component affected the evaluation")
+	}
+	if Evaluate(key, B).Equal((&edwards25519.Point{}).ScalarMult(key, B)) != 1 {
+		t.Error("evaluation of a prime order point is not key * point")
+	}
+	if Evaluate(key, lowOrder).Equal(I) != 1 {
+		t.Error("evaluation of a small order point is not the identity")
+	}
+
+	if _, err := Unblind(I, key); err == nil {
+		t.Error("Unblind accepted the identity")
+	}
+}
+
+type errorReader struct{}
+
+func (errorReader) Read([]byte) (int, error) { return 0, errors.New("read error") }
+
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
+
+func TestOPRFBlindRandError(t *testing.T) {
+	if _, _, err := Blind([]byte("input"), errorReader{}); err == nil {
+		t.Error("expected an error from the random source")
+	}
+	if _, _, err := Blind([]byte("input"), zeroReader{}); err == nil {
+		t.Error("expected an error from a random source stuck at zero")
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"errors"
+	"sync"
+)
+
+// PedersenParams are the generators G and H of Pedersen commitments
+//
+//     C = v * G + r * H
+//
+// to a value v with blinding factor r. The discrete logarithm of H with
+// respect to G must be unknown for commitments to be binding.
+//
+// PedersenParams are immutable, and safe for concurrent use.
+type PedersenParams struct {
+	g, h Point
+
+	// gTable is nil if G is the canonical generator, which has its own
+	// tables.
+	gTable, hTable *PrecomputedPoint
+}
+
+// pedersenDST is the hash to curve domain separation tag of the default H.
+var pedersenDST = []byte("edwards25519_PedersenParams_H_XMD:SHA-512_ELL2_RO_")
+
+var defaultPedersenParams struct {
+	sync.Once
+	p *PedersenParams
+}
+
+// DefaultPedersenParams returns the PedersenParams with G the canonical
+// generator, and H the output of HashToCurve for an empty message and the
+// domain separation tag "edwards25519_PedersenParams_H_XMD:SHA-512_ELL2_RO_".
+//
+// Nobody knows the discrete logarithm of H, as it is the output of a hash.
+func DefaultPedersenParams() *PedersenParams {
+	defaultPedersenParams.Do(func() {
+		h := HashToCurve(nil, pedersenDST)
+		defaultPedersenParams.p = &PedersenParams{
+			g: *NewGeneratorPoint(), h: *h,
+			hTable: NewPrecomputedPoint(h),
+		}
+	})
+	return defaultPedersenParams.p
+}
+
+// NewPedersenParams returns new PedersenParams for the generators g and h. It
+// returns an error if either has small order, or if they are equal.
+func NewPedersenParams(g, h *Point) (*PedersenParams, error) {
+	checkInitialized(g, h)
+	if (&Point{}).MultByCofactor(g).varTimeIsIdentity() ||
+		(&Point{}).MultByCofactor(h).varTimeIsIdentity() {
+		return nil, errors.New("edwards25519: small order Pedersen generator")
+	}
+	if g.Equal(h) == 1 {
+		return nil, errors.New("edwards25519: equal Pedersen generators")
+	}
+	p := &PedersenParams{g: *g, h: *h, hTable: NewPrecomputedPoint(h)}
+	if g.Equal(NewGeneratorPoint()) != 1 {
+		p.gTable = NewPrecomputedPoint(g)
+	}
+	return p, nil
+}
+
+// G returns a copy of the value generator G.
+func (p *PedersenParams) G() *Point {
+	return (&Point{}).Set(&p.g)
+}
+
+// H returns a copy of the blinding generator H.
+func (p *PedersenParams) H() *Point {
+	return (&Point{}).Set(&p.h)
+}
+
+// Commit returns value * G + blinding * H.
+//
+// The commitment is computed in constant time.
+func (p *PedersenParams) Commit(value, blinding *Scalar) *Point {
+	c := p.hTable.ScalarMult(blinding)
+	if p.gTable == nil {
+		return c.ScalarBaseMultAdd(value, c)
+	}
+	return c.Add(c, p.gTable.ScalarMult(value))
+}
+
+// VerifyOpen returns whether c is the commitment to value with blinding.
+//
+// The check is done in constant time.
+func (p *PedersenParams) VerifyOpen(c *Point, value, blinding *Scalar) bool {
+	return p.Commit(value, blinding).Equal(c) == 1
+}
+
+// AddCommitments returns the sum of the commitments, which is the commitment
+// to the sum of their values, with the sum of their blinding factors.
+func AddCommitments(commitments ...*Point) *Point {
+	checkInitialized(commitments...)
+	sum := NewIdentityPoint()
+	for _, c := range commitments {
+		sum.Add(sum, c)
+	}
+	return sum
+}
+
+// ScalarMulCommitment returns x * c, which is the commitment to x times the
+// value of c, with x times its blinding factor.
+//
+// The multiplication is done in constant time.
+func ScalarMulCommitment(x *Scalar, c *Point) *Point {
+	return (&Point{}).ScalarMult(x, c)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"testing"
+	"testing/quick"
+)
+
+func TestDefaultPedersenParams(t *testing.T) {
+	p := DefaultPedersenParams()
+	if p.G().Equal(B) != 1 {
+		t.Errorf("default G is not the canonical generator")
+	}
+
+	// The derivation of the default H must never change, or commitments
+	// would stop opening.
+	expected := decodeHex("7f4d02b6b4919573d5d8aa4ead2d809c21339b8415e84fd6ba982d577f56b2de")
+	if h := p.H().Bytes(); !bytes.Equal(h, expected) {
+		t.Errorf("default H = %x, expected %x", h, expected)
+	}
+	if p.H().Equal(HashToCurve(nil, pedersenDST)) != 1 {
+		t.Errorf("default H is not the output of HashToCurve")
+	}
+
+	// The returned generators are copies.
+	p.H().Add(B, B)
+	if !bytes.Equal(p.H().Bytes(), expected) {
+		t.Errorf("default H was modified through H()")
+	}
+}
+
+func TestPedersenCommit(t *testing.T) {
+	h := HashToCurve([]byte("H"), []byte("edwards25519 test DST"))
+	g := (&Point{}).ScalarBaseMult(&dalekScalar)
+	custom, err := NewPedersenParams(g, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []*PedersenParams{DefaultPedersenParams(), custom} {
+		commit := func(v, r Scalar) bool {
+			c := p.Commit(&v, &r)
+			expected := (&Point{}).VarTimeMultiScalarMult(
+				[]*Scalar{&v, &r}, []*Point{p.G(), p.H()})
+			return c.Equal(expected) == 1 && p.VerifyOpen(c, &v, &r) &&
+				!p.VerifyOpen(c, NewScalar().Add(&v, &scOne), &r) &&
+				!p.VerifyOpen(c, &v, NewScalar().Add(&r, &scOne))
+		}
+		if err := quick.Check(commit, quickCheckConfig32); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestPedersenHomomorphism(t *testing.T) {
+	p := DefaultPedersenParams()
+
+	add := func(a, ra, b, rb Scalar) bool {
+		sum := AddCommitments(p.Commit(&a, &ra), p.Commit(&b, &rb))
+		return p.VerifyOpen(sum, NewScalar().Add(&a, &b), NewScalar().Add(&ra, &rb))
+	}
+	if err := quick.Check(add, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+
+	mul := func(x, v, r Scalar) bool {
+		c := ScalarMulCommitment(&x, p.Commit(&v, &r))
+		return p.VerifyOpen(c, NewScalar().Multiply(&x, &v), NewScalar().Multiply(&x, &r))
+	}
+	if err := quick.Check(mul, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+
+	if AddCommitments().Equal(I) != 1 {
+		t.Errorf("empty sum of commitments is not the identity")
+	}
+	if !p.VerifyOpen(AddCommitments(), NewScalar(), NewScalar()) {
+		t.Errorf("identity does not open to zero")
+	}
+}
+
+func TestNewPedersenParamsErrors(t *testing.T) {
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := DefaultPedersenParams().H()
+	for _, tt := range []struct {
+		name string
+		g, h *Point
+	}{
+		{"identity G", NewIdentityPoint(), h},
+		{"identity H", B, NewIdentityPoint()},
+		{"small order G", lowOrder, h},
+		{"small order H", B, lowOrder},
+		{"equal generators", h, h},
+	} {
+		if _, err := NewPedersenParams(tt.g, tt.h); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func BenchmarkPedersenCommit(b *testing.B) {
+	p := DefaultPedersenParams()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.Commit(&dalekScalar, &dalekScalar)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import "encoding/binary"
+
+// pippengerThreshold is the input size above which VarTimeMultiScalarMult
+// switches from the Straus method to the Pippenger bucket method. It was
+// determined with BenchmarkVarTimeMultiScalarMultSizes.
+const pippengerThreshold = 190
+
+// pippengerWindow returns the Pippenger window width for n input points.
+func pippengerWindow(n int) uint {
+	// Roughly log2(n), clamped to what the digits can represent.
+	switch {
+	case n < 500:
+		return 6
+	case n < 800:
+		return 7
+	default:
+		return 8
+	}
+}
+
+// signedRadix2w computes the signed radix-2^w digits of s, each in
+// [-2^(w-1), 2^(w-1)), into digits, which must have length (256+w-1)/w.
+//
+// w must be between 2 and 8.
+func (s *Scalar) signedRadix2w(w uint, digits []int8) {
+	if s.s[31] > 127 {
+		panic("scalar has high bit set illegally")
+	}
+
+	var limbs [5]uint64
+	for i := 0; i < 4; i++ {
+		limbs[i] = binary.LittleEndian.Uint64(s.s[i*8:])
+	}
+
+	radix := uint64(1 << w)
+	windowMask := radix - 1
+
+	carry := uint64(0)
+	for i := range digits {
+		pos := uint(i) * w
+		indexU64 := pos / 64
+		indexBit := pos % 64
+		var bitBuf uint64
+		if indexBit < 64-w {
+			bitBuf = limbs[indexU64] >> indexBit
+		} else {
+			bitBuf = (limbs[indexU64] >> indexBit) | (limbs[1+indexU64] << (64 - indexBit))
+		}
+
+		window := carry + (bitBuf & windowMask)
+
+		// Recenter all but the top digit, which can absorb the final carry
+		// because s < 2^255.
+		if i == len(digits)-1 {
+			digits[i] = int8(window)
+			break
+		}
+		carry = (window + radix/2) >> w
+		digits[i] = int8(int64(window) - int64(carry<<w))
+	}
+}
+
+// pippengerChunkSize is the number of inputs varTimePippenger decomposes at a
+// time, which bounds its scratch memory regardless of the input size.
+const pippengerChunkSize = 4096
+
+// varTimePippenger sets v = sum(scalars[i] * points[i]) using the Pippenger
+// bucket method, and returns v. The window is sized for n nonzero terms, which
+// may be fewer than len(points).
+func (v *Point) varTimePippenger(c *MSMContext, scalars []*Scalar, points []*Point, n int) *Point {
+	w := pippengerWindow(n)
+	numDigits := int((256 + w - 1) / w)
+	numBuckets := 1 << (w - 1)
+
+	// There is a bucket for each window and nonzero digit absolute value.
+	// Buckets are kept for all windows at once, so that the inputs can be
+	// processed a chunk at a time, and only the chunk is decomposed.
+	buckets := c.bucketPoints(numDigits * numBuckets)
+	for i := range buckets {
+		buckets[i].Set(NewIdentityPoint())
+	}
+	chunk := len(points)
+	if chunk > pippengerChunkSize {
+		chunk = pippengerChunkSize
+	}
+	digits := c.radixDigits(chunk * numDigits)
+	cached := c.cachedPoints(chunk)
+	tmp1 := &projP1xP1{}
+	tmp2 := &projP2{}
+
+	// Track the top window with a nonzero digit, to skip the others as with
+	// short scalars.
+	top := 0
+	for start := 0; start < len(points); start += chunk {
+		end := start + chunk
+		if end > len(points) {
+			end = len(points)
+		}
+		for i := start; i < end; i++ {
+			d := digits[(i-start)*numDigits : (i-start+1)*numDigits]
+			if points[i].varTimeIsIdentity() {
+				// Identity points don't contribute, use all zero digits.
+				for j := range d {
+					d[j] = 0
+				}
+				continue
+			}
+			scalars[i].signedRadix2w(w, d)
+			cached[i-start].FromP3(points[i])
+		}
+
+		// Sort the points into buckets by their digit in each window.
+		for i := 0; i < end-start; i++ {
+			d := digits[i*numDigits : (i+1)*numDigits]
+			for j, digit := range d {
+				if digit > 0 {
+					b := &buckets[j*numBuckets+int(digit)-1]
+					b.fromP1xP1(tmp1.Add(b, &cached[i]))
+				} else if digit < 0 {
+					b := &buckets[j*numBuckets-int(digit)-1]
+					b.fromP1xP1(tmp1.Sub(b, &cached[i]))
+				}
+				if digit != 0 && j > top {
+					top = j
+				}
+			}
+		}
+	}
+
+	bucketSum := &Point{}
+	windowSum := &Point{}
+	bucketCached := &projCached{}
+
+	v.Set(NewIdentityPoint())
+	for d := top; d >= 0; d-- {
+		// Multiply the accumulator by 2^w.
+		tmp2.FromP3(v)
+		for i := uint(0); i < w; i++ {
+			tmp1.Double(tmp2)
+			tmp2.FromP1xP1(tmp1)
+		}
+		v.fromP1xP1(tmp1)
+
+		// Compute sum(k * buckets[k-1]) with the running-sum trick: adding
+		// the buckets from the top down into bucketSum, and bucketSum into
+		// windowSum after each step, adds bucket k exactly k times.
+		windowBuckets := buckets[d*numBuckets : (d+1)*numBuckets]
+		bucketSum.Set(NewIdentityPoint())
+		windowSum.Set(NewIdentityPoint())
+		for k := len(windowBuckets) - 1; k >= 0; k-- {
+			bucketCached.FromP3(&windowBuckets[k])
+			bucketSum.fromP1xP1(tmp1.Add(bucketSum, bucketCached))
+			bucketCached.FromP3(bucketSum)
+			windowSum.fromP1xP1(tmp1.Add(windowSum, bucketCached))
+		}
+
+		bucketCached.FromP3(windowSum)
+		v.fromP1xP1(tmp1.Add(v, bucketCached))
+	}
+
+	return v
+}
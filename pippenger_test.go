@@ -0,0 +1,159 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"fmt"
+	"math/big"
+	mathrand "math/rand"
+	"runtime"
+	"testing"
+	"testing/quick"
+)
+
+func TestScalarSignedRadix2w(t *testing.T) {
+	for w := uint(2); w <= 8; w++ {
+		digits := make([]int8, (256+w-1)/w)
+		signedRadix2wReconstructs := func(x Scalar) bool {
+			x.signedRadix2w(w, digits)
+			sum := new(big.Int)
+			for i := len(digits) - 1; i >= 0; i-- {
+				if i < len(digits)-1 && (digits[i] < -(1<<(w-1)) || int(digits[i]) >= 1<<(w-1)) {
+					return false
+				}
+				sum.Lsh(sum, w)
+				sum.Add(sum, big.NewInt(int64(digits[i])))
+			}
+			return sum.Cmp(bigIntFromLittleEndianBytes(x.s[:])) == 0
+		}
+		if err := quick.Check(signedRadix2wReconstructs, nil); err != nil {
+			t.Errorf("w = %d: %v", w, err)
+		}
+	}
+}
+
+// randomMultiScalarMultInputs returns n random scalars and points, with some
+// zero scalars and repeated points mixed in.
+func randomMultiScalarMultInputs(rand *mathrand.Rand, n int) ([]*Scalar, []*Point) {
+	scalars := make([]*Scalar, n)
+	points := make([]*Point, n)
+	for i := range scalars {
+		s := Scalar{}.Generate(rand, 0).Interface().(Scalar)
+		switch rand.Intn(10) {
+		case 0:
+			s = scZero
+		case 1:
+			s = scMinusOne
+		}
+		scalars[i] = &s
+
+		if i > 0 && rand.Intn(10) == 0 {
+			points[i] = points[rand.Intn(i)]
+			continue
+		}
+		k := Scalar{}.Generate(rand, 0).Interface().(Scalar)
+		points[i] = (&Point{}).ScalarBaseMult(&k)
+	}
+	return scalars, points
+}
+
+func TestVarTimePippengerMatchesStraus(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{1, 2, 3, 17, 190, 191, 499, 500, 800, 4096} {
+		scalars, points := randomMultiScalarMultInputs(rand, n)
+		var p, check Point
+		p.varTimePippenger(&MSMContext{}, scalars, points, len(points))
+		check.varTimeStraus(&MSMContext{}, scalars, points, 5)
+		checkOnCurve(t, &p)
+		if p.Equal(&check) != 1 {
+			t.Errorf("n = %d: Pippenger does not match Straus", n)
+		}
+	}
+}
+
+// sparseMultiScalarMultInputs returns n terms, of which only one in ten has
+// both a nonzero scalar and a point other than the identity.
+func sparseMultiScalarMultInputs(rand *mathrand.Rand, n int) ([]*Scalar, []*Point) {
+	scalars, points := randomMultiScalarMultInputs(rand, n)
+	for i := range scalars {
+		switch i % 10 {
+		case 0:
+		case 1, 3, 5, 7:
+			scalars[i] = NewScalar()
+		default:
+			points[i] = NewIdentityPoint()
+		}
+	}
+	return scalars, points
+}
+
+func TestVarTimeMultiScalarMultSparse(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	scalars, points := sparseMultiScalarMultInputs(rand, 1000)
+	c := &MSMContext{}
+	var p, check Point
+	p.VarTimeMultiScalarMultWithContext(c, scalars, points)
+	check.MultiScalarMult(scalars, points)
+	if p.Equal(&check) != 1 {
+		t.Error("result does not match MultiScalarMult")
+	}
+	// The 100 nonzero terms are below pippengerThreshold, so the zero terms
+	// must have been dropped before picking the method.
+	if cap(c.buckets) != 0 {
+		t.Error("mostly zero input was processed with the bucket method")
+	}
+}
+
+func BenchmarkVarTimeMultiScalarMultSizes(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{64, 128, 192, 256, 512, 1024} {
+		scalars, points := randomMultiScalarMultInputs(rand, n)
+		b.Run(fmt.Sprintf("straus/%d", n), func(b *testing.B) {
+			var p Point
+			for i := 0; i < b.N; i++ {
+				p.varTimeStraus(&MSMContext{}, scalars, points, 5)
+			}
+		})
+		b.Run(fmt.Sprintf("pippenger/%d", n), func(b *testing.B) {
+			var p Point
+			for i := 0; i < b.N; i++ {
+				p.varTimePippenger(&MSMContext{}, scalars, points, len(points))
+			}
+		})
+	}
+}
+
+func TestVarTimePippengerMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping large multiscalar multiplication in short mode")
+	}
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, distinct := randomMultiScalarMultInputs(rand, 16)
+	n := 200000
+	scalars := make([]*Scalar, n)
+	points := make([]*Point, n)
+	for i := range scalars {
+		s := Scalar{}.Generate(rand, 0).Interface().(Scalar)
+		scalars[i] = &s
+		points[i] = distinct[i%len(distinct)]
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	var p Point
+	p.VarTimeMultiScalarMult(scalars, points)
+	runtime.ReadMemStats(&after)
+
+	// The scratch space is bounded by the chunk size, not by n.
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 4<<20 {
+		t.Errorf("allocated %d bytes for %d inputs", allocated, n)
+	}
+
+	var check Point
+	check.VarTimeMultiScalarMultDedup(scalars, points)
+	if p.Equal(&check) != 1 {
+		t.Error("result does not match VarTimeMultiScalarMultDedup")
+	}
+}
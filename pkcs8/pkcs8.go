@@ -0,0 +1,74 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pkcs8 parses and marshals Ed25519 keys in the PKCS #8 and PKIX
+// SubjectPublicKeyInfo ASN.1 DER forms of RFC 8410, as edwards25519 scalars
+// and points.
+//
+// It is kept separate from the edwards25519 package so that programs that
+// don't need it don't link in crypto/x509.
+package pkcs8
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+)
+
+// ParsePrivateScalar parses an Ed25519 private key in PKCS #8, ASN.1 DER
+// form, as specified in RFC 8410, and returns its secret scalar, nonce
+// prefix, and public point, as edwards25519.ScalarAndPrefixFromSeed.
+//
+// It returns an error if der is not a valid PKCS #8 key, or if it's not an
+// Ed25519 key.
+func ParsePrivateScalar(der []byte) (s *edwards25519.Scalar, prefix []byte, A *edwards25519.Point, err error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("pkcs8: %v", err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("pkcs8: PKCS #8 key is a %T, not an Ed25519 key", key)
+	}
+	s, prefix, err = edwards25519.ScalarAndPrefixFromSeed(priv.Seed())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return s, prefix, (&edwards25519.Point{}).ScalarBaseMult(s), nil
+}
+
+// ParsePublicPoint parses an Ed25519 public key in PKIX
+// SubjectPublicKeyInfo, ASN.1 DER form, as specified in RFC 8410, and returns
+// its point.
+//
+// It returns an error if der is not a valid SubjectPublicKeyInfo, if it's not
+// an Ed25519 key, if the key is not a valid point encoding, or if the point
+// has small order. Like Point.SetBytes, it accepts non-canonical encodings.
+func ParsePublicPoint(der []byte) (*edwards25519.Point, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs8: %v", err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("pkcs8: SubjectPublicKeyInfo key is a %T, not an Ed25519 key", key)
+	}
+	A, err := edwards25519.PointFromPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	if (&edwards25519.Point{}).MultByCofactor(A).Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return nil, errors.New("pkcs8: small order public key")
+	}
+	return A, nil
+}
+
+// MarshalPublicPoint returns the PKIX SubjectPublicKeyInfo, ASN.1 DER
+// form of the Ed25519 public key A, as specified in RFC 8410.
+func MarshalPublicPoint(A *edwards25519.Point) ([]byte, error) {
+	return x509.MarshalPKIXPublicKey(edwards25519.PublicKeyFromPoint(A))
+}
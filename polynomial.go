@@ -0,0 +1,136 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	cryptorand "crypto/rand"
+	"io"
+)
+
+// ScalarPolynomial is a polynomial over the scalar field, such as the ones
+// used by Shamir secret sharing, Feldman VSS, and DKG protocols.
+//
+// The degree of a ScalarPolynomial is nominal: it is set by the number of
+// coefficients, regardless of whether the leading ones are zero, so that it
+// never depends on secret values. The zero value is the zero polynomial,
+// with degree -1.
+//
+// Like Scalar, the methods of ScalarPolynomial allow their arguments and
+// receivers to alias.
+type ScalarPolynomial struct {
+	// coefficients holds the coefficients, starting from the constant term.
+	coefficients []Scalar
+}
+
+// NewScalarPolynomial returns a new ScalarPolynomial with the given
+// coefficients, starting from the constant term. The coefficients are copied.
+func NewScalarPolynomial(coefficients []*Scalar) *ScalarPolynomial {
+	p := &ScalarPolynomial{coefficients: make([]Scalar, len(coefficients))}
+	for i, c := range coefficients {
+		p.coefficients[i].Set(c)
+	}
+	return p
+}
+
+// RandomPolynomial returns a new ScalarPolynomial of the given degree with
+// uniformly random coefficients read from rand. If rand is nil,
+// crypto/rand.Reader is used. If reading from rand fails, RandomPolynomial
+// returns nil and the error.
+//
+// For secret sharing, set the constant term to the secret with SetCoefficient.
+func RandomPolynomial(degree int, rand io.Reader) (*ScalarPolynomial, error) {
+	if degree < 0 {
+		panic("edwards25519: negative polynomial degree")
+	}
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	p := &ScalarPolynomial{coefficients: make([]Scalar, degree+1)}
+	var buf [64]byte
+	for i := range p.coefficients {
+		if _, err := io.ReadFull(rand, buf[:]); err != nil {
+			return nil, err
+		}
+		p.coefficients[i].SetUniformBytes(buf[:])
+	}
+	return p, nil
+}
+
+// Degree returns the nominal degree of p, which is one less than its number
+// of coefficients.
+func (p *ScalarPolynomial) Degree() int {
+	return len(p.coefficients) - 1
+}
+
+// Coefficient returns a copy of the coefficient of x^i in p, which is zero if
+// i is larger than the degree of p.
+func (p *ScalarPolynomial) Coefficient(i int) *Scalar {
+	if i < 0 {
+		panic("edwards25519: negative polynomial coefficient index")
+	}
+	if i >= len(p.coefficients) {
+		return NewScalar()
+	}
+	return NewScalar().Set(&p.coefficients[i])
+}
+
+// SetCoefficient sets the coefficient of x^i in p to c, raising the degree of
+// p to i if it was lower, and returns p.
+func (p *ScalarPolynomial) SetCoefficient(i int, c *Scalar) *ScalarPolynomial {
+	if i < 0 {
+		panic("edwards25519: negative polynomial coefficient index")
+	}
+	if i >= len(p.coefficients) {
+		p.coefficients = append(p.coefficients, make([]Scalar, i+1-len(p.coefficients))...)
+	}
+	p.coefficients[i].Set(c)
+	return p
+}
+
+// Evaluate returns p(x).
+//
+// Execution time depends only on the degree of p.
+func (p *ScalarPolynomial) Evaluate(x *Scalar) *Scalar {
+	// Horner's method.
+	y := NewScalar()
+	for i := len(p.coefficients) - 1; i >= 0; i-- {
+		y.MultiplyAdd(y, x, &p.coefficients[i])
+	}
+	return y
+}
+
+// Add sets v = p + q, and returns v. The degree of v is the larger of the
+// degrees of p and q.
+func (v *ScalarPolynomial) Add(p, q *ScalarPolynomial) *ScalarPolynomial {
+	if len(p.coefficients) < len(q.coefficients) {
+		p, q = q, p
+	}
+	sum := make([]Scalar, len(p.coefficients))
+	copy(sum, p.coefficients)
+	for i := range q.coefficients {
+		sum[i].Add(&sum[i], &q.coefficients[i])
+	}
+	v.coefficients = sum
+	return v
+}
+
+// Mul sets v = p * q, and returns v. The degree of v is the sum of the degrees
+// of p and q, or -1 if either is the zero value.
+//
+// Execution time depends only on the degrees of p and q.
+func (v *ScalarPolynomial) Mul(p, q *ScalarPolynomial) *ScalarPolynomial {
+	if len(p.coefficients) == 0 || len(q.coefficients) == 0 {
+		v.coefficients = nil
+		return v
+	}
+	product := make([]Scalar, len(p.coefficients)+len(q.coefficients)-1)
+	for i := range p.coefficients {
+		for j := range q.coefficients {
+			product[i+j].MultiplyAdd(&p.coefficients[i], &q.coefficients[j], &product[i+j])
+		}
+	}
+	v.coefficients = product
+	return v
+}
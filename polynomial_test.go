@@ -0,0 +1,100 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	mathrand "math/rand"
+	"testing"
+)
+
+func TestScalarPolynomial(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, degrees := range [][2]int{{0, 0}, {0, 3}, {2, 2}, {4, 1}, {7, 9}} {
+		p, err := RandomPolynomial(degrees[0], rand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, err := RandomPolynomial(degrees[1], rand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Degree() != degrees[0] || q.Degree() != degrees[1] {
+			t.Fatalf("wrong degrees %d, %d for %v", p.Degree(), q.Degree(), degrees)
+		}
+
+		sum := new(ScalarPolynomial).Add(p, q)
+		product := new(ScalarPolynomial).Mul(p, q)
+		want := degrees[0]
+		if degrees[1] > want {
+			want = degrees[1]
+		}
+		if sum.Degree() != want {
+			t.Errorf("%v: sum degree = %d, want %d", degrees, sum.Degree(), want)
+		}
+		if want := degrees[0] + degrees[1]; product.Degree() != want {
+			t.Errorf("%v: product degree = %d, want %d", degrees, product.Degree(), want)
+		}
+
+		for _, x := range randomScalars(rand, 5) {
+			px, qx := p.Evaluate(x), q.Evaluate(x)
+			if sum.Evaluate(x).Equal(NewScalar().Add(px, qx)) != 1 {
+				t.Errorf("%v: (p + q)(x) != p(x) + q(x)", degrees)
+			}
+			if product.Evaluate(x).Equal(NewScalar().Multiply(px, qx)) != 1 {
+				t.Errorf("%v: (p * q)(x) != p(x) * q(x)", degrees)
+			}
+		}
+		if p.Evaluate(NewScalar()).Equal(p.Coefficient(0)) != 1 {
+			t.Errorf("%v: p(0) is not the constant term", degrees)
+		}
+
+		// Aliasing the receiver with the arguments.
+		pCopy := NewScalarPolynomial([]*Scalar{})
+		for i := 0; i <= p.Degree(); i++ {
+			pCopy.SetCoefficient(i, p.Coefficient(i))
+		}
+		x := randomScalars(rand, 1)[0]
+		square := NewScalar().Multiply(p.Evaluate(x), p.Evaluate(x))
+		if pCopy.Mul(pCopy, pCopy).Evaluate(x).Equal(square) != 1 {
+			t.Errorf("%v: aliased p * p is wrong", degrees)
+		}
+	}
+}
+
+func TestScalarPolynomialCoefficients(t *testing.T) {
+	// 1 + 2x + 3x^2, evaluated at 10.
+	p := NewScalarPolynomial([]*Scalar{
+		NewScalar().SetUint64(1), NewScalar().SetUint64(2), NewScalar().SetUint64(3),
+	})
+	if p.Evaluate(NewScalar().SetUint64(10)).Equal(NewScalar().SetUint64(321)) != 1 {
+		t.Error("p(10) != 321")
+	}
+	if p.Coefficient(5).Equal(NewScalar()) != 1 {
+		t.Error("coefficient beyond the degree is not zero")
+	}
+	p.Coefficient(1).Set(&scMinusOne)
+	if p.Coefficient(1).Equal(NewScalar().SetUint64(2)) != 1 {
+		t.Error("Coefficient does not return a copy")
+	}
+	p.SetCoefficient(4, &scOne)
+	if p.Degree() != 4 || p.Coefficient(3).Equal(NewScalar()) != 1 {
+		t.Error("SetCoefficient did not extend the polynomial with zeroes")
+	}
+
+	var zero ScalarPolynomial
+	if zero.Degree() != -1 || zero.Evaluate(&scOne).Equal(NewScalar()) != 1 {
+		t.Error("the zero value is not the zero polynomial")
+	}
+	if new(ScalarPolynomial).Mul(p, &zero).Degree() != -1 {
+		t.Error("p * 0 is not the zero polynomial")
+	}
+	if new(ScalarPolynomial).Add(&zero, p).Evaluate(&scOne).Equal(p.Evaluate(&scOne)) != 1 {
+		t.Error("0 + p != p")
+	}
+
+	if _, err := RandomPolynomial(2, errorReader{}); err == nil {
+		t.Error("expected an error from a failing reader")
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+// PrecomputedPoint is a point with a precomputed table of multiples, for
+// repeated constant-time scalar multiplications by the same fixed base.
+//
+// A PrecomputedPoint is immutable once built, and is safe for concurrent use.
+type PrecomputedPoint struct {
+	// table holds 32 affineLookupTables, where table i holds the multiples
+	// of (16^2i)*Q, in the same layout as basepointTable.
+	table [32]affineLookupTable
+}
+
+// NewPrecomputedPoint returns a new PrecomputedPoint for q.
+//
+// Building the table is much slower than a single scalar multiplication, and
+// pays off only if the result is used for many of them.
+func NewPrecomputedPoint(q *Point) *PrecomputedPoint {
+	checkInitialized(q)
+
+	v := &PrecomputedPoint{}
+	tmp1 := &projP1xP1{}
+	tmp2 := &projP2{}
+	tmp3 := (&Point{}).Set(q)
+	for i := range v.table {
+		v.table[i].FromP3(tmp3)
+
+		// Set tmp3 = (16^2)*tmp3 = 256*tmp3 = 2^8*tmp3
+		tmp2.FromP3(tmp3)
+		for j := 0; j < 7; j++ {
+			tmp1.Double(tmp2)
+			tmp2.FromP1xP1(tmp1)
+		}
+		tmp1.Double(tmp2)
+		tmp3.fromP1xP1(tmp1)
+	}
+	return v
+}
+
+// ScalarMult returns x * Q, where Q is the point v was built from.
+//
+// The scalar multiplication is done in constant time.
+func (v *PrecomputedPoint) ScalarMult(x *Scalar) *Point {
+	return (&Point{}).fixedBaseMult(&v.table, x)
+}
+
+// NafTablePoint is a point with a precomputed width-8 NAF table of its odd
+// multiples, for repeated variable-time scalar multiplications by the same
+// base, such as verifying many signatures from the same public key.
+//
+// A NafTablePoint is immutable once built, and is safe for concurrent use.
+type NafTablePoint struct {
+	table nafLookupTable8
+}
+
+// NewNafTablePoint returns a new NafTablePoint for q.
+//
+// Building the table is slower than a single scalar multiplication, and pays
+// off only if the result is used for several of them.
+func NewNafTablePoint(q *Point) *NafTablePoint {
+	checkInitialized(q)
+	v := &NafTablePoint{}
+	v.table.FromP3(q)
+	return v
+}
+
+// VarTimeScalarMultPrecomputed sets v = a * A, and returns v.
+//
+// Execution time depends on the inputs.
+func (v *Point) VarTimeScalarMultPrecomputed(a *Scalar, A *NafTablePoint) *Point {
+	aNaf := a.nonAdjacentFormWide(8)
+	return v.varTimeAffineNafMult([]*[256]int16{&aNaf},
+		[][]affineCached{A.table.points[:]})
+}
+
+// VarTimeDoubleScalarBaseMultPrecomputed sets v = a * A + b * B, where B is the
+// canonical generator, and returns v. It is equivalent to
+// VarTimeDoubleScalarBaseMult, but uses the precomputed table of A.
+//
+// Execution time depends on the inputs.
+func (v *Point) VarTimeDoubleScalarBaseMultPrecomputed(a *Scalar, A *NafTablePoint, b *Scalar) *Point {
+	aNaf := a.nonAdjacentFormWide(8)
+	bWidth, bTable := basepointNafTableWide()
+	bNaf := b.nonAdjacentFormWide(bWidth)
+	return v.varTimeAffineNafMult([]*[256]int16{&aNaf, &bNaf},
+		[][]affineCached{A.table.points[:], bTable})
+}
+
+// varTimeAffineNafMult sets v = sum(nafs[i] * tables[i]), where tables hold
+// the odd multiples of the corresponding points, as many as needed for the
+// width of each NAF, and returns v.
+func (v *Point) varTimeAffineNafMult(nafs []*[256]int16, tables [][]affineCached) *Point {
+	// Find the first nonzero coefficient.
+	i := 255
+	for ; i >= 0; i-- {
+		nonZero := false
+		for _, naf := range nafs {
+			nonZero = nonZero || naf[i] != 0
+		}
+		if nonZero {
+			break
+		}
+	}
+
+	tmp1 := &projP1xP1{}
+	tmp2 := &projP2{}
+	tmp2.Zero()
+
+	for ; i >= 0; i-- {
+		tmp1.Double(tmp2)
+
+		for j, naf := range nafs {
+			// As in nafLookupTable8.SelectInto, x*Q is at index x/2.
+			if naf[i] > 0 {
+				v.fromP1xP1(tmp1)
+				tmp1.AddAffine(v, &tables[j][naf[i]/2])
+			} else if naf[i] < 0 {
+				v.fromP1xP1(tmp1)
+				tmp1.SubAffine(v, &tables[j][-naf[i]/2])
+			}
+		}
+
+		tmp2.FromP1xP1(tmp1)
+	}
+
+	v.fromP2(tmp2)
+	return v
+}
+
+// VarTimeMixedMultiScalarMult sets v = sum(fixedScalars[i] * fixedTables[i])
+// + sum(dynScalars[j] * dynPoints[j]), and returns v. The precomputed tables
+// of the fixed bases are used alongside the tables built on the fly for the
+// dynamic points, sharing the doublings between all of them.
+//
+// Execution time depends on the inputs.
+func (v *Point) VarTimeMixedMultiScalarMult(fixedScalars []*Scalar, fixedTables []*NafTablePoint,
+	dynScalars []*Scalar, dynPoints []*Point) *Point {
+	if len(fixedScalars) != len(fixedTables) || len(dynScalars) != len(dynPoints) {
+		panic("edwards25519: called VarTimeMixedMultiScalarMult with different size inputs")
+	}
+	checkInitialized(dynPoints...)
+
+	fixedNafs := make([][256]int8, len(fixedScalars))
+	for i := range fixedNafs {
+		fixedNafs[i] = fixedScalars[i].nonAdjacentForm(8)
+	}
+	dynTables := make([]nafLookupTable5, len(dynPoints))
+	for i := range dynTables {
+		dynTables[i].FromP3(dynPoints[i])
+	}
+	dynNafs := make([][256]int8, len(dynScalars))
+	for i := range dynNafs {
+		dynNafs[i] = dynScalars[i].nonAdjacentForm(5)
+	}
+
+	multFixed := &affineCached{}
+	multDyn := &projCached{}
+	tmp1 := &projP1xP1{}
+	tmp2 := &projP2{}
+	tmp2.Zero()
+
+	for i := 255; i >= 0; i-- {
+		tmp1.Double(tmp2)
+
+		for j := range fixedNafs {
+			if fixedNafs[j][i] > 0 {
+				v.fromP1xP1(tmp1)
+				fixedTables[j].table.SelectInto(multFixed, fixedNafs[j][i])
+				tmp1.AddAffine(v, multFixed)
+			} else if fixedNafs[j][i] < 0 {
+				v.fromP1xP1(tmp1)
+				fixedTables[j].table.SelectInto(multFixed, -fixedNafs[j][i])
+				tmp1.SubAffine(v, multFixed)
+			}
+		}
+
+		for j := range dynNafs {
+			if dynNafs[j][i] > 0 {
+				v.fromP1xP1(tmp1)
+				dynTables[j].SelectInto(multDyn, dynNafs[j][i])
+				tmp1.Add(v, multDyn)
+			} else if dynNafs[j][i] < 0 {
+				v.fromP1xP1(tmp1)
+				dynTables[j].SelectInto(multDyn, -dynNafs[j][i])
+				tmp1.Sub(v, multDyn)
+			}
+		}
+
+		tmp2.FromP1xP1(tmp1)
+	}
+
+	v.fromP2(tmp2)
+	return v
+}
+
+// MSMTables holds the precomputed lookup tables of a fixed set of points, for
+// repeated multiscalar multiplications over the same points.
+//
+// An MSMTables is immutable once built, and is safe for concurrent use.
+type MSMTables struct {
+	points    []Point
+	tables    []projLookupTable
+	nafTables []projCached // width-5 NAF tables, in sequence
+}
+
+// PrecomputeMSMTables returns the lookup tables of points, for use with
+// MultiScalarMultWithTables and VarTimeMultiScalarMultWithTables.
+func PrecomputeMSMTables(points []*Point) *MSMTables {
+	checkInitialized(points...)
+
+	t := &MSMTables{
+		points:    make([]Point, len(points)),
+		tables:    make([]projLookupTable, len(points)),
+		nafTables: make([]projCached, len(points)*nafTableSize(5)),
+	}
+	size := nafTableSize(5)
+	for i := range points {
+		t.points[i].Set(points[i])
+		t.tables[i].FromP3(points[i])
+		fillNafTable(t.nafTables[i*size:(i+1)*size], points[i])
+	}
+	return t
+}
+
+// Len returns the number of points t was built from.
+func (t *MSMTables) Len() int {
+	return len(t.tables)
+}
+
+// prefix returns the tables of the first n points of t, sharing its memory.
+func (t *MSMTables) prefix(n int) *MSMTables {
+	return &MSMTables{
+		points:    t.points[:n],
+		tables:    t.tables[:n],
+		nafTables: t.nafTables[:n*nafTableSize(5)],
+	}
+}
+
+// extend returns the tables of the points of t followed by points. The result
+// may share memory with t, which is left unchanged.
+func (t *MSMTables) extend(points []*Point) *MSMTables {
+	checkInitialized(points...)
+
+	n, size := len(t.points), nafTableSize(5)
+	e := &MSMTables{
+		points:    append(t.points, make([]Point, len(points))...),
+		tables:    append(t.tables, make([]projLookupTable, len(points))...),
+		nafTables: append(t.nafTables, make([]projCached, len(points)*size)...),
+	}
+	for i, p := range points {
+		e.points[n+i].Set(p)
+		e.tables[n+i].FromP3(p)
+		fillNafTable(e.nafTables[(n+i)*size:(n+i+1)*size], p)
+	}
+	return e
+}
+
+// MultiScalarMultWithTables sets v = sum(scalars[i] * points[i]), where points
+// are the ones t was built from, and returns v.
+//
+// Execution time depends only on the lengths of the two slices, which must match.
+func (v *Point) MultiScalarMultWithTables(scalars []*Scalar, t *MSMTables) *Point {
+	if len(scalars) != len(t.tables) {
+		panic("edwards25519: called MultiScalarMultWithTables with different size inputs")
+	}
+	return v.multiScalarMult(scalars, t.tables, 64)
+}
+
+// VarTimeMultiScalarMultWithTables sets v = sum(scalars[i] * points[i]), where
+// points are the ones t was built from, and returns v.
+//
+// Execution time depends on the inputs.
+func (v *Point) VarTimeMultiScalarMultWithTables(scalars []*Scalar, t *MSMTables) *Point {
+	if len(scalars) != len(t.tables) {
+		panic("edwards25519: called VarTimeMultiScalarMultWithTables with different size inputs")
+	}
+	return v.varTimeStrausWithTables(&MSMContext{}, scalars, t.nafTables, 5)
+}
@@ -0,0 +1,346 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+// The binary encoding of the precomputed table types is
+//
+//     magic (4 bytes) || version (1 byte) || kind (1 byte) ||
+//     count (4 bytes, big-endian) || entries || SHA-256 of all of the above
+//
+// where entries are canonical 32 bytes field element encodings. Tables are
+// fully validated on load: the checksum only catches accidental corruption,
+// while the validation ensures that a table can't compute wrong multiples.
+
+const (
+	tableEncodingVersion = 1
+
+	tableKindPrecomputedPoint = 1
+	tableKindNafTablePoint    = 2
+	tableKindMSMTables        = 3
+
+	tableHeaderSize = 4 + 1 + 1 + 4
+)
+
+var tableEncodingMagic = []byte("e25t")
+
+var (
+	errTableEncoding     = errors.New("edwards25519: invalid precomputed table encoding")
+	errTableChecksum     = errors.New("edwards25519: precomputed table checksum mismatch")
+	errTableInconsistent = errors.New("edwards25519: precomputed table entries are inconsistent")
+)
+
+// appendTableHeader appends the header of a table encoding to b.
+func appendTableHeader(b []byte, kind byte, count int) []byte {
+	b = append(b, tableEncodingMagic...)
+	b = append(b, tableEncodingVersion, kind)
+	var c [4]byte
+	binary.BigEndian.PutUint32(c[:], uint32(count))
+	return append(b, c[:]...)
+}
+
+// appendTableChecksum appends the checksum of b to b.
+func appendTableChecksum(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return append(b, h[:]...)
+}
+
+// parseTable checks the header and checksum of a table encoding of the given
+// kind, and returns the count and the entries.
+func parseTable(data []byte, kind byte) (count int, entries []byte, err error) {
+	if len(data) < tableHeaderSize+sha256.Size {
+		return 0, nil, errTableEncoding
+	}
+	body, sum := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if h := sha256.Sum256(body); !bytes.Equal(h[:], sum) {
+		return 0, nil, errTableChecksum
+	}
+	if !bytes.Equal(body[:4], tableEncodingMagic) ||
+		body[4] != tableEncodingVersion || body[5] != kind {
+		return 0, nil, errTableEncoding
+	}
+	count = int(binary.BigEndian.Uint32(body[6:10]))
+	return count, body[tableHeaderSize:], nil
+}
+
+// tableDecoder reads canonical field elements from a table encoding.
+type tableDecoder struct {
+	entries []byte
+	err     error
+}
+
+func (d *tableDecoder) fieldElement(v *fieldElement) {
+	if d.err != nil {
+		return
+	}
+	if len(d.entries) < 32 {
+		d.err = errTableEncoding
+		return
+	}
+	v.SetBytes(d.entries[:32])
+	if !bytes.Equal(v.Bytes(), d.entries[:32]) {
+		d.err = errTableEncoding
+	}
+	d.entries = d.entries[32:]
+}
+
+func (d *tableDecoder) affineCached(v *affineCached) {
+	d.fieldElement(&v.YplusX)
+	d.fieldElement(&v.YminusX)
+	d.fieldElement(&v.T2d)
+}
+
+func (d *tableDecoder) finish() error {
+	if d.err == nil && len(d.entries) != 0 {
+		d.err = errTableEncoding
+	}
+	return d.err
+}
+
+func appendAffineCached(b []byte, v *affineCached) []byte {
+	var buf [32]byte
+	b = append(b, v.YplusX.bytes(&buf)...)
+	buf = [32]byte{}
+	b = append(b, v.YminusX.bytes(&buf)...)
+	buf = [32]byte{}
+	return append(b, v.T2d.bytes(&buf)...)
+}
+
+// fromAffineCached sets v to the point represented by p, and returns an error
+// if p is not a valid affineCached representation of a point.
+func (v *Point) fromAffineCached(p *affineCached) error {
+	// With x2 = y+x - (y-x) = 2x and y2 = y+x + (y-x) = 2y, the point is
+	// (2*x2 : 2*y2 : 4 : x2*y2) in extended coordinates.
+	var x2, y2 fieldElement
+	x2.Subtract(&p.YplusX, &p.YminusX)
+	y2.Add(&p.YplusX, &p.YminusX)
+	v.x.Add(&x2, &x2)
+	v.y.Add(&y2, &y2)
+	v.z.Add(feTwo, feTwo)
+	v.t.Multiply(&x2, &y2)
+	if err := checkExtendedCoordinates(v); err != nil {
+		return err
+	}
+
+	// T2d = 2dxy, so 2*T2d = d*x2*y2.
+	var lhs, rhs fieldElement
+	lhs.Add(&p.T2d, &p.T2d)
+	rhs.Multiply(d, &v.t)
+	if lhs.Equal(&rhs) != 1 {
+		return errTableInconsistent
+	}
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (v *PrecomputedPoint) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, tableHeaderSize+len(v.table)*8*3*32+sha256.Size)
+	b = appendTableHeader(b, tableKindPrecomputedPoint, len(v.table))
+	for i := range v.table {
+		for j := range v.table[i].points {
+			b = appendAffineCached(b, &v.table[i].points[j])
+		}
+	}
+	return appendTableChecksum(b), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. It
+// checks that every entry of the table is consistent with the others, which
+// is still about twice as fast as NewPrecomputedPoint. On error, v is unchanged.
+func (v *PrecomputedPoint) UnmarshalBinary(data []byte) error {
+	var table [32]affineLookupTable
+	if err := decodePrecomputedTable(data, &table); err != nil {
+		return err
+	}
+	if err := checkPrecomputedTable(&table); err != nil {
+		return err
+	}
+	v.table = table
+	return nil
+}
+
+// decodePrecomputedTable decodes a PrecomputedPoint encoding into table,
+// without checking the entries against each other.
+func decodePrecomputedTable(data []byte, table *[32]affineLookupTable) error {
+	count, entries, err := parseTable(data, tableKindPrecomputedPoint)
+	if err != nil {
+		return err
+	}
+	if count != len(table) {
+		return errTableEncoding
+	}
+	dec := &tableDecoder{entries: entries}
+	for i := range table {
+		for j := range table[i].points {
+			dec.affineCached(&table[i].points[j])
+		}
+	}
+	return dec.finish()
+}
+
+// checkPrecomputedTable checks that table i holds Q_i, 2Q_i, ..., 8Q_i, and
+// that Q_(i+1) = 256*Q_i, where Q_0 is the first entry.
+func checkPrecomputedTable(table *[32]affineLookupTable) error {
+	var q, next, entry Point
+	tmp1 := &projP1xP1{}
+	tmp2 := &projP2{}
+	if err := q.fromAffineCached(&table[0].points[0]); err != nil {
+		return err
+	}
+	for i := range table {
+		if i > 0 {
+			if err := entry.fromAffineCached(&table[i].points[0]); err != nil {
+				return err
+			}
+			if entry.Equal(&next) != 1 {
+				return errTableInconsistent
+			}
+			q.Set(&entry)
+		}
+		var multiple Point
+		multiple.Set(&q)
+		for j := 1; j < len(table[i].points); j++ {
+			multiple.fromP1xP1(tmp1.AddAffine(&multiple, &table[i].points[0]))
+			if err := entry.fromAffineCached(&table[i].points[j]); err != nil {
+				return err
+			}
+			if entry.Equal(&multiple) != 1 {
+				return errTableInconsistent
+			}
+		}
+
+		tmp2.FromP3(&q)
+		for j := 0; j < 7; j++ {
+			tmp1.Double(tmp2)
+			tmp2.FromP1xP1(tmp1)
+		}
+		next.fromP1xP1(tmp1.Double(tmp2))
+	}
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (v *NafTablePoint) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, tableHeaderSize+len(v.table.points)*3*32+sha256.Size)
+	b = appendTableHeader(b, tableKindNafTablePoint, len(v.table.points))
+	for i := range v.table.points {
+		b = appendAffineCached(b, &v.table.points[i])
+	}
+	return appendTableChecksum(b), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. It
+// checks that every entry of the table is consistent with the others, which
+// is still faster than NewNafTablePoint. On error, v is unchanged.
+func (v *NafTablePoint) UnmarshalBinary(data []byte) error {
+	var table nafLookupTable8
+	if err := decodeNafTable(data, &table); err != nil {
+		return err
+	}
+	if err := checkNafTable(&table); err != nil {
+		return err
+	}
+	v.table = table
+	return nil
+}
+
+// decodeNafTable decodes a NafTablePoint encoding into table, without checking
+// the entries against each other.
+func decodeNafTable(data []byte, table *nafLookupTable8) error {
+	count, entries, err := parseTable(data, tableKindNafTablePoint)
+	if err != nil {
+		return err
+	}
+	if count != len(table.points) {
+		return errTableEncoding
+	}
+	dec := &tableDecoder{entries: entries}
+	for i := range table.points {
+		dec.affineCached(&table.points[i])
+	}
+	return dec.finish()
+}
+
+// checkNafTable checks that table holds Q, 3Q, 5Q, ..., 127Q, where Q is the
+// first entry.
+func checkNafTable(table *nafLookupTable8) error {
+	var q, q2, multiple, entry Point
+	if err := q.fromAffineCached(&table.points[0]); err != nil {
+		return err
+	}
+	q2.Add(&q, &q)
+	multiple.Set(&q)
+	for i := 1; i < len(table.points); i++ {
+		multiple.Add(&multiple, &q2)
+		if err := entry.fromAffineCached(&table.points[i]); err != nil {
+			return err
+		}
+		if entry.Equal(&multiple) != 1 {
+			return errTableInconsistent
+		}
+	}
+
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+//
+// Only the points are encoded, in extended coordinates, since building the
+// tables from them is cheap.
+func (t *MSMTables) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, tableHeaderSize+len(t.points)*4*32+sha256.Size)
+	b = appendTableHeader(b, tableKindMSMTables, len(t.points))
+	var buf [32]byte
+	for i := range t.points {
+		p := &t.points[i]
+		for _, fe := range []*fieldElement{&p.x, &p.y, &p.z, &p.t} {
+			buf = [32]byte{}
+			b = append(b, fe.bytes(&buf)...)
+		}
+	}
+	return appendTableChecksum(b), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. On
+// error, t is unchanged.
+func (t *MSMTables) UnmarshalBinary(data []byte) error {
+	count, entries, err := parseTable(data, tableKindMSMTables)
+	if err != nil {
+		return err
+	}
+	// Compare in 64 bits, since count*4*32 can overflow int on 32-bit
+	// platforms, and count comes from untrusted input.
+	if uint64(count)*4*32 != uint64(len(entries)) {
+		return errTableEncoding
+	}
+	points := make([]*Point, count)
+	dec := &tableDecoder{entries: entries}
+	for i := range points {
+		p := &Point{}
+		dec.fieldElement(&p.x)
+		dec.fieldElement(&p.y)
+		dec.fieldElement(&p.z)
+		dec.fieldElement(&p.t)
+		if dec.err != nil {
+			return dec.err
+		}
+		if err := checkExtendedCoordinates(p); err != nil {
+			return err
+		}
+		points[i] = p
+	}
+	if err := dec.finish(); err != nil {
+		return err
+	}
+
+	*t = *PrecomputeMSMTables(points)
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"crypto/sha256"
+	mathrand "math/rand"
+	"testing"
+)
+
+// resumTable recomputes the checksum of a modified table encoding.
+func resumTable(data []byte) []byte {
+	out := append([]byte{}, data...)
+	body := out[:len(out)-sha256.Size]
+	h := sha256.Sum256(body)
+	copy(out[len(body):], h[:])
+	return out
+}
+
+type tableUnmarshaler interface {
+	UnmarshalBinary([]byte) error
+}
+
+func testTableEncodingCorruption(t *testing.T, data []byte, newTable func() tableUnmarshaler) {
+	t.Helper()
+	if err := newTable().UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Error("truncated encoding was accepted")
+	}
+	if err := newTable().UnmarshalBinary(append(data[:len(data):len(data)], 0)); err == nil {
+		t.Error("extended encoding was accepted")
+	}
+
+	bad := append([]byte{}, data...)
+	bad[tableHeaderSize+5] ^= 1
+	if err := newTable().UnmarshalBinary(bad); err != errTableChecksum {
+		t.Errorf("flipped bit: got %v, expected %v", err, errTableChecksum)
+	}
+
+	bad = append([]byte{}, data...)
+	bad[4]++
+	if err := newTable().UnmarshalBinary(resumTable(bad)); err != errTableEncoding {
+		t.Errorf("wrong version: got %v, expected %v", err, errTableEncoding)
+	}
+
+	bad = append([]byte{}, data...)
+	bad[5] ^= 0xff
+	if err := newTable().UnmarshalBinary(resumTable(bad)); err != errTableEncoding {
+		t.Errorf("wrong kind: got %v, expected %v", err, errTableEncoding)
+	}
+
+	// A table with a valid checksum but a wrong entry must be rejected.
+	bad = append([]byte{}, data...)
+	bad[len(data)-sha256.Size-40] ^= 1
+	if err := newTable().UnmarshalBinary(resumTable(bad)); err == nil {
+		t.Error("inconsistent entry was accepted")
+	}
+
+	// Non-canonical field element encodings must be rejected.
+	bad = append([]byte{}, data...)
+	for i := tableHeaderSize; i < tableHeaderSize+32; i++ {
+		bad[i] = 0xff
+	}
+	if err := newTable().UnmarshalBinary(resumTable(bad)); err != errTableEncoding {
+		t.Errorf("non-canonical entry: got %v, expected %v", err, errTableEncoding)
+	}
+}
+
+func TestPrecomputedPointEncoding(t *testing.T) {
+	q := (&Point{}).ScalarBaseMult(&dalekScalar)
+	table := NewPrecomputedPoint(q)
+	data, err := table.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded PrecomputedPoint
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.table != table.table {
+		t.Error("decoded table does not match the original")
+	}
+	want := (&Point{}).ScalarMult(&dalekScalar, q)
+	if decoded.ScalarMult(&dalekScalar).Equal(want) != 1 {
+		t.Error("decoded table computes a wrong result")
+	}
+
+	// An encoding of B must decode to basepointTable.
+	data, _ = NewPrecomputedPoint(B).MarshalBinary()
+	if err := decoded.UnmarshalBinary(data); err != nil || decoded.table != basepointTable {
+		t.Errorf("basepoint table did not round-trip: %v", err)
+	}
+
+	testTableEncodingCorruption(t, data, func() tableUnmarshaler { return &PrecomputedPoint{} })
+
+	// Swapping two tables keeps every entry valid, but not the table.
+	swapped := NewPrecomputedPoint(q)
+	swapped.table[3], swapped.table[4] = swapped.table[4], swapped.table[3]
+	data, _ = swapped.MarshalBinary()
+	if err := decoded.UnmarshalBinary(data); err != errTableInconsistent {
+		t.Errorf("swapped tables: got %v, expected %v", err, errTableInconsistent)
+	}
+}
+
+func TestNafTablePointEncoding(t *testing.T) {
+	q := (&Point{}).ScalarBaseMult(&dalekScalar)
+	table := NewNafTablePoint(q)
+	data, err := table.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded NafTablePoint
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.table != table.table {
+		t.Error("decoded table does not match the original")
+	}
+
+	testTableEncodingCorruption(t, data, func() tableUnmarshaler { return &NafTablePoint{} })
+
+	swapped := NewNafTablePoint(q)
+	swapped.table.points[3], swapped.table.points[4] = swapped.table.points[4], swapped.table.points[3]
+	data, _ = swapped.MarshalBinary()
+	if err := decoded.UnmarshalBinary(data); err != errTableInconsistent {
+		t.Errorf("swapped entries: got %v, expected %v", err, errTableInconsistent)
+	}
+}
+
+func TestMSMTablesEncoding(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	scalars, points := randomMultiScalarMultInputs(rand, 8)
+	tables := PrecomputeMSMTables(points)
+	data, err := tables.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded MSMTables
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Len() != tables.Len() {
+		t.Fatalf("got Len() = %d, expected %d", decoded.Len(), tables.Len())
+	}
+	var p, check Point
+	p.MultiScalarMultWithTables(scalars, &decoded)
+	check.MultiScalarMult(scalars, points)
+	if p.Equal(&check) != 1 {
+		t.Error("decoded tables compute a wrong result")
+	}
+
+	testTableEncodingCorruption(t, data, func() tableUnmarshaler { return &MSMTables{} })
+}
+
+func TestMSMTablesEncodingOversizedCount(t *testing.T) {
+	// A count whose encoded size overflows int on 32-bit platforms, with an
+	// empty body and a valid checksum.
+	for _, count := range []uint32{1, 0x02000000, 0x7fffffff, 0x80000000, 0xffffffff} {
+		data := appendTableHeader(nil, tableKindMSMTables, int(count))
+		data = appendTableChecksum(data)
+		var decoded MSMTables
+		if err := decoded.UnmarshalBinary(data); err == nil {
+			t.Errorf("count %#x: expected an error", count)
+		}
+	}
+}
+
+func BenchmarkPrecomputedPointUnmarshalBinary(b *testing.B) {
+	data, _ := NewPrecomputedPoint(B).MarshalBinary()
+	var table PrecomputedPoint
+	for i := 0; i < b.N; i++ {
+		if err := table.UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	mathrand "math/rand"
+	"sync"
+	"testing"
+	"testing/quick"
+)
+
+func TestPrecomputedPointBasepoint(t *testing.T) {
+	table := NewPrecomputedPoint(B)
+	if table.table != basepointTable {
+		t.Error("precomputed table for B does not match basepointTable")
+	}
+}
+
+func TestPrecomputedPointMatchesScalarMult(t *testing.T) {
+	q := (&Point{}).ScalarBaseMult(&dalekScalar)
+	table := NewPrecomputedPoint(q)
+
+	scalarMultMatches := func(x Scalar) bool {
+		var check Point
+		p := table.ScalarMult(&x)
+		check.ScalarMult(&x, q)
+		checkOnCurve(t, p, &check)
+		return p.Equal(&check) == 1
+	}
+
+	if err := quick.Check(scalarMultMatches, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPrecomputedPointConcurrent(t *testing.T) {
+	q := (&Point{}).ScalarBaseMult(&dalekScalar)
+	table := NewPrecomputedPoint(q)
+	want := (&Point{}).ScalarMult(&dalekScalar, q)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 16; j++ {
+				if table.ScalarMult(&dalekScalar).Equal(want) != 1 {
+					t.Error("concurrent ScalarMult returned a wrong result")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPrecomputedPointIndependentOfSource(t *testing.T) {
+	q := (&Point{}).ScalarBaseMult(&dalekScalar)
+	want := (&Point{}).ScalarMult(&dalekScalar, q)
+	table := NewPrecomputedPoint(q)
+
+	// Changing q after building the table, in any way, must not affect it.
+	q.Add(q, B)
+	if table.ScalarMult(&dalekScalar).Equal(want) != 1 {
+		t.Error("Add into the source point changed the table")
+	}
+	q.Set(B)
+	if table.ScalarMult(&dalekScalar).Equal(want) != 1 {
+		t.Error("Set of the source point changed the table")
+	}
+	if _, err := q.SetBytes(NewIdentityPoint().Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if table.ScalarMult(&dalekScalar).Equal(want) != 1 {
+		t.Error("SetBytes of the source point changed the table")
+	}
+}
+
+func BenchmarkPrecomputedPointScalarMult(b *testing.B) {
+	table := NewPrecomputedPoint(B)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		table.ScalarMult(&dalekScalar)
+	}
+}
+
+func BenchmarkPrecomputedPointVsScalarMult(b *testing.B) {
+	q := (&Point{}).ScalarBaseMult(&dalekScalar)
+	table := NewPrecomputedPoint(q)
+	b.Run("PrecomputedPoint", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			table.ScalarMult(&dalekScalar)
+		}
+	})
+	b.Run("ScalarMult", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.ScalarMult(&dalekScalar, q)
+		}
+	})
+}
+
+func BenchmarkNewPrecomputedPoint(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewPrecomputedPoint(B)
+	}
+}
+
+func TestNafTablePointMatchesVarTime(t *testing.T) {
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+	table := NewNafTablePoint(A)
+
+	nafTablePointMatches := func(a, b Scalar) bool {
+		var p, q, check1, check2 Point
+		p.VarTimeDoubleScalarBaseMultPrecomputed(&a, table, &b)
+		check1.VarTimeDoubleScalarBaseMult(&a, A, &b)
+		q.VarTimeScalarMultPrecomputed(&a, table)
+		check2.ScalarMult(&a, A)
+		checkOnCurve(t, &p, &q)
+		return p.Equal(&check1) == 1 && q.Equal(&check2) == 1
+	}
+
+	if err := quick.Check(nafTablePointMatches, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+
+	var zero Scalar
+	if p := (&Point{}).VarTimeDoubleScalarBaseMultPrecomputed(&zero, table, &zero); p.Equal(I) != 1 {
+		t.Error("0*A + 0*B != 0")
+	}
+}
+
+func BenchmarkVarTimeDoubleScalarBaseMultPrecomputed(b *testing.B) {
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+
+	// Rebuild the table every 100 operations, as a verifier checking
+	// batches of signatures from the same signer would.
+	var p Point
+	var table *NafTablePoint
+	for i := 0; i < b.N; i++ {
+		if i%100 == 0 {
+			table = NewNafTablePoint(A)
+		}
+		p.VarTimeDoubleScalarBaseMultPrecomputed(&dalekScalar, table, &dalekScalar)
+	}
+}
+
+func BenchmarkNewNafTablePoint(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewNafTablePoint(B)
+	}
+}
+
+func TestVarTimeMixedMultiScalarMult(t *testing.T) {
+	H := (&Point{}).ScalarBaseMult(&dalekScalar)
+	P := (&Point{}).Add(H, B)
+	fixed := []*NafTablePoint{NewNafTablePoint(B), NewNafTablePoint(H)}
+
+	mixedMatches := func(x, y, z Scalar) bool {
+		var p, check Point
+
+		// Both fixed and dynamic bases.
+		p.VarTimeMixedMultiScalarMult([]*Scalar{&x, &y}, fixed, []*Scalar{&z}, []*Point{P})
+		check.VarTimeMultiScalarMult([]*Scalar{&x, &y, &z}, []*Point{B, H, P})
+		checkOnCurve(t, &p)
+		if p.Equal(&check) != 1 {
+			return false
+		}
+
+		// Zero fixed bases.
+		p.VarTimeMixedMultiScalarMult(nil, nil, []*Scalar{&x, &z}, []*Point{H, P})
+		check.VarTimeMultiScalarMult([]*Scalar{&x, &z}, []*Point{H, P})
+		if p.Equal(&check) != 1 {
+			return false
+		}
+
+		// Zero dynamic bases.
+		p.VarTimeMixedMultiScalarMult([]*Scalar{&x, &y}, fixed, nil, nil)
+		check.VarTimeDoubleScalarBaseMultPrecomputed(&y, fixed[1], &x)
+		return p.Equal(&check) == 1
+	}
+
+	if err := quick.Check(mixedMatches, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+
+	p := NewGeneratorPoint()
+	if p.VarTimeMixedMultiScalarMult(nil, nil, nil, nil).Equal(I) != 1 {
+		t.Error("empty VarTimeMixedMultiScalarMult is not the identity")
+	}
+}
+
+func TestMSMTables(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, points := randomMultiScalarMultInputs(rand, 8)
+	tables := PrecomputeMSMTables(points)
+	if tables.Len() != len(points) {
+		t.Errorf("got Len() = %d, expected %d", tables.Len(), len(points))
+	}
+
+	for i := 0; i < 16; i++ {
+		scalars := make([]*Scalar, len(points))
+		for j := range scalars {
+			s := Scalar{}.Generate(rand, 0).Interface().(Scalar)
+			scalars[j] = &s
+		}
+		var p, q, check Point
+		p.MultiScalarMultWithTables(scalars, tables)
+		q.VarTimeMultiScalarMultWithTables(scalars, tables)
+		check.MultiScalarMult(scalars, points)
+		checkOnCurve(t, &p, &q)
+		if p.Equal(&check) != 1 || q.Equal(&check) != 1 {
+			t.Fatal("result does not match MultiScalarMult")
+		}
+	}
+}
+
+func BenchmarkMultiScalarMultWithTables(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	scalars, points := randomMultiScalarMultInputs(rand, 32)
+	tables := PrecomputeMSMTables(points)
+	b.Run("constant-time", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.MultiScalarMultWithTables(scalars, tables)
+		}
+	})
+	b.Run("constant-time/no-tables", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.MultiScalarMult(scalars, points)
+		}
+	})
+	b.Run("vartime", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeMultiScalarMultWithTables(scalars, tables)
+		}
+	})
+	b.Run("vartime/no-tables", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeMultiScalarMult(scalars, points)
+		}
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"crypto/subtle"
 	"encoding/binary"
 	"errors"
+	"math/bits"
 )
 
 // A Scalar is an integer modulo
@@ -43,26 +44,51 @@ func NewScalar() *Scalar {
 
 // MultiplyAdd sets s = x * y + z mod l, and returns s.
 func (s *Scalar) MultiplyAdd(x, y, z *Scalar) *Scalar {
+	ct := newCTState()
+	ct.poisonScalars(x, y, z)
 	scMulAdd(&s.s, &x.s, &y.s, &z.s)
+	ct.resultScalar(s)
 	return s
 }
 
 // Add sets s = x + y mod l, and returns s.
 func (s *Scalar) Add(x, y *Scalar) *Scalar {
-	// s = 1 * x + y mod l
-	return s.MultiplyAdd(&scOne, x, y)
+	ct := newCTState()
+	ct.poisonScalars(x, y)
+	scAdd(&s.s, &x.s, &y.s)
+	ct.resultScalar(s)
+	return s
 }
 
 // Subtract sets s = x - y mod l, and returns s.
 func (s *Scalar) Subtract(x, y *Scalar) *Scalar {
-	// s = -1 * y + x mod l
-	return s.MultiplyAdd(&scMinusOne, y, x)
+	ct := newCTState()
+	ct.poisonScalars(x, y)
+	scSubtract(&s.s, &x.s, &y.s)
+	ct.resultScalar(s)
+	return s
 }
 
 // Negate sets s = -x mod l, and returns s.
 func (s *Scalar) Negate(x *Scalar) *Scalar {
-	// s = -1 * x + 0 mod l
-	return s.MultiplyAdd(&scMinusOne, x, &scZero)
+	return s.Subtract(&scZero, x)
+}
+
+// Halve sets s = x / 2 mod l, and returns s.
+func (s *Scalar) Halve(x *Scalar) *Scalar {
+	scHalve(&s.s, &x.s)
+	return s
+}
+
+// DivPow2 sets s = x / 2^k mod l, and returns s.
+//
+// Execution time depends only on k.
+func (s *Scalar) DivPow2(x *Scalar, k uint) *Scalar {
+	s.Set(x)
+	for i := uint(0); i < k; i++ {
+		scHalve(&s.s, &s.s)
+	}
+	return s
 }
 
 // Multiply sets s = x * y mod l, and returns s.
@@ -77,15 +103,47 @@ func (s *Scalar) Set(x *Scalar) *Scalar {
 	return s
 }
 
-// SetUniformBytes sets s to an uniformly distributed value given 64 uniformly
-// distributed random bytes.
+// SetUint64 sets s = x, and returns s.
+func (s *Scalar) SetUint64(x uint64) *Scalar {
+	*s = scZero
+	binary.LittleEndian.PutUint64(s.s[:8], x)
+	return s
+}
+
+// SetInt64 sets s = x mod l, and returns s.
+func (s *Scalar) SetInt64(x int64) *Scalar {
+	mask := uint64(x >> 63)
+	s.SetUint64((uint64(x) ^ mask) - mask)
+	neg := NewScalar().Negate(s)
+	subtle.ConstantTimeCopy(int(mask&1), s.s[:], neg.s[:])
+	return s
+}
+
+// SetUniformBytes sets s to an uniformly distributed value given at least 48
+// uniformly distributed random bytes, and returns s. x is reduced modulo l as a
+// little-endian integer, so the bias is negligible for 48 bytes and up. The
+// common size is 64 bytes, the output of SHA-512.
+//
+// Note that hash_to_field from RFC 9380 reads its input as big-endian, so its
+// bytes have to be reversed first.
+//
+// If x is shorter than 48 bytes, SetUniformBytes will panic.
 func (s *Scalar) SetUniformBytes(x []byte) *Scalar {
-	if len(x) != 64 {
+	if len(x) < 48 {
 		panic("edwards25519: invalid SetUniformBytes input length")
 	}
+
+	// Reduce the top 33 to 64 bytes, and then fold in the rest 32 bytes at a
+	// time, as acc = acc * 2^256 + x[i:i+32].
+	i := (len(x) - 33) / 32 * 32
 	var wideBytes [64]byte
-	copy(wideBytes[:], x[:])
+	copy(wideBytes[:], x[i:])
 	scReduce(&s.s, &wideBytes)
+	for i -= 32; i >= 0; i -= 32 {
+		copy(wideBytes[:32], x[i:i+32])
+		copy(wideBytes[32:], s.s[:])
+		scReduce(&s.s, &wideBytes)
+	}
 	return s
 }
 
@@ -138,13 +196,34 @@ func (s *Scalar) SetBytesWithClamping(x []byte) *Scalar {
 	}
 	var wideBytes [64]byte
 	copy(wideBytes[:], x[:])
-	wideBytes[0] &= 248
-	wideBytes[31] &= 63
-	wideBytes[31] |= 64
+	ClampBytes(wideBytes[:32])
 	scReduce(&s.s, &wideBytes)
 	return s
 }
 
+// ClampBytes applies in place the clamping of RFC 7748, Section 5, and RFC 8032,
+// Section 5.1.5, to the 32 bytes little-endian scalar b: it clears the three
+// lowest bits and the highest bit, and sets the second highest bit.
+//
+// If b is not 32 bytes long, ClampBytes will panic.
+func ClampBytes(b []byte) {
+	if len(b) != 32 {
+		panic("edwards25519: invalid ClampBytes input length")
+	}
+	b[0] &= 248
+	b[31] &= 127
+	b[31] |= 64
+}
+
+// IsClamped returns whether b is a 32 bytes little-endian scalar that is left
+// unchanged by ClampBytes.
+func IsClamped(b []byte) bool {
+	if len(b) != 32 {
+		return false
+	}
+	return b[0]&7|(b[31]&0xc0^0x40) == 0
+}
+
 // Bytes returns the canonical 32 bytes little-endian encoding of s.
 func (s *Scalar) Bytes() []byte {
 	buf := make([]byte, 32)
@@ -157,782 +236,329 @@ func (s *Scalar) Equal(t *Scalar) int {
 	return subtle.ConstantTimeCompare(s.s[:], t.s[:])
 }
 
-func load3(in []byte) int64 {
-	r := int64(in[0])
-	r |= int64(in[1]) << 8
-	r |= int64(in[2]) << 16
-	return r
+// Cmp compares the numeric values of s and t, and returns -1 if s < t, 0 if
+// s == t, and +1 if s > t.
+//
+// Execution time depends on the inputs. Use Equal for secret values.
+func (s *Scalar) Cmp(t *Scalar) int {
+	for i := len(s.s) - 1; i >= 0; i-- {
+		switch {
+		case s.s[i] < t.s[i]:
+			return -1
+		case s.s[i] > t.s[i]:
+			return +1
+		}
+	}
+	return 0
 }
 
-func load4(in []byte) int64 {
-	r := int64(in[0])
-	r |= int64(in[1]) << 8
-	r |= int64(in[2]) << 16
-	r |= int64(in[3]) << 24
-	return r
+// fitsInBits returns 1 if s < 2^n, and 0 otherwise, in time that depends only
+// on n.
+func (s *Scalar) fitsInBits(n int) int {
+	var high byte
+	for i := range s.s {
+		switch {
+		case 8*i >= n:
+			high |= s.s[i]
+		case 8*i+8 > n:
+			high |= s.s[i] >> uint(n-8*i)
+		}
+	}
+	return subtle.ConstantTimeByteEq(high, 0)
 }
 
-// Input:
-//   a[0]+256*a[1]+...+256^31*a[31] = a
-//   b[0]+256*b[1]+...+256^31*b[31] = b
-//   c[0]+256*c[1]+...+256^31*c[31] = c
+// The arithmetic modulo l is done on four 64-bit limbs, loaded from and stored
+// to the canonical encoding at the boundary of each operation. A 512-bit value
+// lo + hi * 2^256 is reduced by folding the top four bits of lo using
+// 2^252 = -c mod l, where l = 2^252 + c, and by computing hi * 2^256 mod l as
+// the Montgomery reduction of hi * (2^512 mod l).
+//
+// Since the third limb of l is zero and the fourth is 2^60, each Montgomery
+// reduction step only needs two multiplications.
 //
-// Output:
-//   s[0]+256*s[1]+...+256^31*s[31] = (ab+c) mod l
-//   where l = 2^252 + 27742317777372353535851937790883648493.
+// Limbs are passed around as separate values rather than arrays, so that they
+// can stay in registers. All operations are constant-time.
+
+const (
+	scL0 = 0x5812631a5cf5d3ed
+	scL1 = 0x14def9dea2f79cd6
+	scL3 = 0x1000000000000000
+
+	// scLInv is -l^-1 mod 2^64.
+	scLInv = 0xd2b51da312547e1b
+
+	// scR2 is 2^512 mod l.
+	scR2x0 = 0xa40611e3449c0f01
+	scR2x1 = 0xd00e1ba768859347
+	scR2x2 = 0xceec73d217f5be65
+	scR2x3 = 0x0399411b7c309a3d
+)
+
+func scLoad(in []byte) (x0, x1, x2, x3 uint64) {
+	_ = in[31] // bounds check hint to compiler; see golang.org/issue/14808
+	return binary.LittleEndian.Uint64(in[0:8]),
+		binary.LittleEndian.Uint64(in[8:16]),
+		binary.LittleEndian.Uint64(in[16:24]),
+		binary.LittleEndian.Uint64(in[24:32])
+}
+
+func scStore(out *[32]byte, x0, x1, x2, x3 uint64) {
+	binary.LittleEndian.PutUint64(out[0:8], x0)
+	binary.LittleEndian.PutUint64(out[8:16], x1)
+	binary.LittleEndian.PutUint64(out[16:24], x2)
+	binary.LittleEndian.PutUint64(out[24:32], x3)
+}
+
+// scAddMaskedL returns x + (l & mask), discarding the carry.
+func scAddMaskedL(x0, x1, x2, x3, mask uint64) (uint64, uint64, uint64, uint64) {
+	var c uint64
+	x0, c = bits.Add64(x0, scL0&mask, 0)
+	x1, c = bits.Add64(x1, scL1&mask, c)
+	x2, c = bits.Add64(x2, 0, c)
+	x3, _ = bits.Add64(x3, scL3&mask, c)
+	return x0, x1, x2, x3
+}
+
+// scSubtract sets s = a - b mod l, for a, b < l.
+func scSubtract(s, a, b *[32]byte) {
+	a0, a1, a2, a3 := scLoad(a[:])
+	b0, b1, b2, b3 := scLoad(b[:])
+	a0, a1, a2, a3 = scSubtract256(a0, a1, a2, a3, b0, b1, b2, b3)
+	scStore(s, a0, a1, a2, a3)
+}
+
+// scSubtract256 returns a - b mod l, for a, b < l.
+func scSubtract256(a0, a1, a2, a3, b0, b1, b2, b3 uint64) (uint64, uint64, uint64, uint64) {
+	var bw uint64
+	a0, bw = bits.Sub64(a0, b0, 0)
+	a1, bw = bits.Sub64(a1, b1, bw)
+	a2, bw = bits.Sub64(a2, b2, bw)
+	a3, bw = bits.Sub64(a3, b3, bw)
+	// If there was a borrow, add l back.
+	return scAddMaskedL(a0, a1, a2, a3, -bw)
+}
+
+// scAdd sets s = a + b mod l, for a, b < l.
+func scAdd(s, a, b *[32]byte) {
+	a0, a1, a2, a3 := scLoad(a[:])
+	b0, b1, b2, b3 := scLoad(b[:])
+	a0, a1, a2, a3 = scAdd256(a0, a1, a2, a3, b0, b1, b2, b3)
+	scStore(s, a0, a1, a2, a3)
+}
+
+// scHalve sets s = a / 2 mod l, for a < l.
+func scHalve(s, a *[32]byte) {
+	a0, a1, a2, a3 := scLoad(a[:])
+	// If a is odd, a + l is even, and (a + l) / 2 < l. Since a + l < 2^254,
+	// there is no carry out.
+	a0, a1, a2, a3 = scAddMaskedL(a0, a1, a2, a3, -(a0 & 1))
+	a0 = a0>>1 | a1<<63
+	a1 = a1>>1 | a2<<63
+	a2 = a2>>1 | a3<<63
+	a3 = a3 >> 1
+	scStore(s, a0, a1, a2, a3)
+}
+
+// scAdd256 returns a + b mod l, for a, b < 2^255.
+func scAdd256(a0, a1, a2, a3, b0, b1, b2, b3 uint64) (uint64, uint64, uint64, uint64) {
+	// a + b < 2^256, so there is no carry out.
+	var c uint64
+	a0, c = bits.Add64(a0, b0, 0)
+	a1, c = bits.Add64(a1, b1, c)
+	a2, c = bits.Add64(a2, b2, c)
+	a3, _ = bits.Add64(a3, b3, c)
+	return scReduce256(a0, a1, a2, a3)
+}
+
+// scReduce256 returns x mod l, for any x < 2^256.
+func scReduce256(x0, x1, x2, x3 uint64) (uint64, uint64, uint64, uint64) {
+	// x = x' + q * 2^252 with q < 16, and x' - q * c is in (-l, 2^252).
+	q := x3 >> 60
+	x3 &= 1<<60 - 1
+	h0, l0 := bits.Mul64(q, scL0)
+	h1, l1 := bits.Mul64(q, scL1)
+	var bw uint64
+	x0, bw = bits.Sub64(x0, l0, 0)
+	h0, _ = bits.Add64(h0, l1, bw) // q * c < 2^129, so this can't overflow
+	x1, bw = bits.Sub64(x1, h0, 0)
+	x2, bw = bits.Sub64(x2, h1, bw)
+	x3, bw = bits.Sub64(x3, 0, bw)
+	return scAddMaskedL(x0, x1, x2, x3, -bw)
+}
+
+// scMac returns a * b + c + d, which can't overflow 128 bits.
+func scMac(a, b, c, d uint64) (hi, lo uint64) {
+	hi, lo = bits.Mul64(a, b)
+	var carry uint64
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return hi, lo
+}
+
+// scMul512 returns the 512-bit product a * b.
+func scMul512(a0, a1, a2, a3, b0, b1, b2, b3 uint64) (t0, t1, t2, t3, t4, t5, t6, t7 uint64) {
+	var c uint64
+	c, t0 = scMac(a0, b0, 0, 0)
+	c, t1 = scMac(a0, b1, 0, c)
+	c, t2 = scMac(a0, b2, 0, c)
+	t4, t3 = scMac(a0, b3, 0, c)
+
+	c, t1 = scMac(a1, b0, t1, 0)
+	c, t2 = scMac(a1, b1, t2, c)
+	c, t3 = scMac(a1, b2, t3, c)
+	t5, t4 = scMac(a1, b3, t4, c)
+
+	c, t2 = scMac(a2, b0, t2, 0)
+	c, t3 = scMac(a2, b1, t3, c)
+	c, t4 = scMac(a2, b2, t4, c)
+	t6, t5 = scMac(a2, b3, t5, c)
+
+	c, t3 = scMac(a3, b0, t3, 0)
+	c, t4 = scMac(a3, b1, t4, c)
+	c, t5 = scMac(a3, b2, t5, c)
+	t7, t6 = scMac(a3, b3, t6, c)
+	return
+}
+
+// scMontgomeryStep adds m * l to the four words w, where m is chosen to make
+// the bottom word zero, and returns the top three words and the carry into the
+// fifth word, which is at most 2^60.
+func scMontgomeryStep(w0, w1, w2, w3 uint64) (r1, r2, r3, carry uint64) {
+	m := w0 * scLInv
+	h0, _ := scMac(m, scL0, w0, 0)
+	h1, r1 := scMac(m, scL1, w1, h0)
+	var c uint64
+	r2, c = bits.Add64(w2, h1, 0)
+	r3, c = bits.Add64(w3, m<<60, c)
+	return r1, r2, r3, m>>4 + c
+}
+
+// scMontgomeryReduce returns t * 2^-256 mod l, for t < l * 2^256.
+func scMontgomeryReduce(t0, t1, t2, t3, t4, t5, t6, t7 uint64) (uint64, uint64, uint64, uint64) {
+	var c, cc uint64
+	t1, t2, t3, c = scMontgomeryStep(t0, t1, t2, t3)
+	t4, cc = bits.Add64(t4, c, 0)
+	t5, cc = bits.Add64(t5, 0, cc)
+	t6, cc = bits.Add64(t6, 0, cc)
+	t7, cc = bits.Add64(t7, 0, cc)
+	t8 := cc
+
+	t2, t3, t4, c = scMontgomeryStep(t1, t2, t3, t4)
+	t5, cc = bits.Add64(t5, c, 0)
+	t6, cc = bits.Add64(t6, 0, cc)
+	t7, cc = bits.Add64(t7, 0, cc)
+	t8 += cc
+
+	t3, t4, t5, c = scMontgomeryStep(t2, t3, t4, t5)
+	t6, cc = bits.Add64(t6, c, 0)
+	t7, cc = bits.Add64(t7, 0, cc)
+	t8 += cc
+
+	t4, t5, t6, c = scMontgomeryStep(t3, t4, t5, t6)
+	t7, cc = bits.Add64(t7, c, 0)
+	t8 += cc
+
+	// (t + m * l) / 2^256 < 2l < 2^254, so t8 is zero, and a conditional
+	// subtraction of l fully reduces the result.
+	var bw uint64
+	t4, bw = bits.Sub64(t4, scL0, 0)
+	t5, bw = bits.Sub64(t5, scL1, bw)
+	t6, bw = bits.Sub64(t6, 0, bw)
+	t7, bw = bits.Sub64(t7, scL3, bw)
+	return scAddMaskedL(t4, t5, t6, t7, -bw)
+}
+
+// scReduce512 returns t mod l.
+func scReduce512(t0, t1, t2, t3, t4, t5, t6, t7 uint64) (uint64, uint64, uint64, uint64) {
+	l0, l1, l2, l3 := scReduce256(t0, t1, t2, t3)
+
+	// hi * 2^256 = (hi * 2^512) * 2^-256 mod l, and hi * (2^512 mod l) is lower
+	// than l * 2^256, as required by the Montgomery reduction.
+	h0, h1, h2, h3 := scMontgomeryReduce(scMul512(t4, t5, t6, t7, scR2x0, scR2x1, scR2x2, scR2x3))
+	return scAdd256(l0, l1, l2, l3, h0, h1, h2, h3)
+}
+
+// scMulAdd sets s = a * b + c mod l, for a, b, c < 2^256.
 func scMulAdd(s, a, b, c *[32]byte) {
-	a0 := 2097151 & load3(a[:])
-	a1 := 2097151 & (load4(a[2:]) >> 5)
-	a2 := 2097151 & (load3(a[5:]) >> 2)
-	a3 := 2097151 & (load4(a[7:]) >> 7)
-	a4 := 2097151 & (load4(a[10:]) >> 4)
-	a5 := 2097151 & (load3(a[13:]) >> 1)
-	a6 := 2097151 & (load4(a[15:]) >> 6)
-	a7 := 2097151 & (load3(a[18:]) >> 3)
-	a8 := 2097151 & load3(a[21:])
-	a9 := 2097151 & (load4(a[23:]) >> 5)
-	a10 := 2097151 & (load3(a[26:]) >> 2)
-	a11 := (load4(a[28:]) >> 7)
-	b0 := 2097151 & load3(b[:])
-	b1 := 2097151 & (load4(b[2:]) >> 5)
-	b2 := 2097151 & (load3(b[5:]) >> 2)
-	b3 := 2097151 & (load4(b[7:]) >> 7)
-	b4 := 2097151 & (load4(b[10:]) >> 4)
-	b5 := 2097151 & (load3(b[13:]) >> 1)
-	b6 := 2097151 & (load4(b[15:]) >> 6)
-	b7 := 2097151 & (load3(b[18:]) >> 3)
-	b8 := 2097151 & load3(b[21:])
-	b9 := 2097151 & (load4(b[23:]) >> 5)
-	b10 := 2097151 & (load3(b[26:]) >> 2)
-	b11 := (load4(b[28:]) >> 7)
-	c0 := 2097151 & load3(c[:])
-	c1 := 2097151 & (load4(c[2:]) >> 5)
-	c2 := 2097151 & (load3(c[5:]) >> 2)
-	c3 := 2097151 & (load4(c[7:]) >> 7)
-	c4 := 2097151 & (load4(c[10:]) >> 4)
-	c5 := 2097151 & (load3(c[13:]) >> 1)
-	c6 := 2097151 & (load4(c[15:]) >> 6)
-	c7 := 2097151 & (load3(c[18:]) >> 3)
-	c8 := 2097151 & load3(c[21:])
-	c9 := 2097151 & (load4(c[23:]) >> 5)
-	c10 := 2097151 & (load3(c[26:]) >> 2)
-	c11 := (load4(c[28:]) >> 7)
-	var carry [23]int64
-
-	s0 := c0 + a0*b0
-	s1 := c1 + a0*b1 + a1*b0
-	s2 := c2 + a0*b2 + a1*b1 + a2*b0
-	s3 := c3 + a0*b3 + a1*b2 + a2*b1 + a3*b0
-	s4 := c4 + a0*b4 + a1*b3 + a2*b2 + a3*b1 + a4*b0
-	s5 := c5 + a0*b5 + a1*b4 + a2*b3 + a3*b2 + a4*b1 + a5*b0
-	s6 := c6 + a0*b6 + a1*b5 + a2*b4 + a3*b3 + a4*b2 + a5*b1 + a6*b0
-	s7 := c7 + a0*b7 + a1*b6 + a2*b5 + a3*b4 + a4*b3 + a5*b2 + a6*b1 + a7*b0
-	s8 := c8 + a0*b8 + a1*b7 + a2*b6 + a3*b5 + a4*b4 + a5*b3 + a6*b2 + a7*b1 + a8*b0
-	s9 := c9 + a0*b9 + a1*b8 + a2*b7 + a3*b6 + a4*b5 + a5*b4 + a6*b3 + a7*b2 + a8*b1 + a9*b0
-	s10 := c10 + a0*b10 + a1*b9 + a2*b8 + a3*b7 + a4*b6 + a5*b5 + a6*b4 + a7*b3 + a8*b2 + a9*b1 + a10*b0
-	s11 := c11 + a0*b11 + a1*b10 + a2*b9 + a3*b8 + a4*b7 + a5*b6 + a6*b5 + a7*b4 + a8*b3 + a9*b2 + a10*b1 + a11*b0
-	s12 := a1*b11 + a2*b10 + a3*b9 + a4*b8 + a5*b7 + a6*b6 + a7*b5 + a8*b4 + a9*b3 + a10*b2 + a11*b1
-	s13 := a2*b11 + a3*b10 + a4*b9 + a5*b8 + a6*b7 + a7*b6 + a8*b5 + a9*b4 + a10*b3 + a11*b2
-	s14 := a3*b11 + a4*b10 + a5*b9 + a6*b8 + a7*b7 + a8*b6 + a9*b5 + a10*b4 + a11*b3
-	s15 := a4*b11 + a5*b10 + a6*b9 + a7*b8 + a8*b7 + a9*b6 + a10*b5 + a11*b4
-	s16 := a5*b11 + a6*b10 + a7*b9 + a8*b8 + a9*b7 + a10*b6 + a11*b5
-	s17 := a6*b11 + a7*b10 + a8*b9 + a9*b8 + a10*b7 + a11*b6
-	s18 := a7*b11 + a8*b10 + a9*b9 + a10*b8 + a11*b7
-	s19 := a8*b11 + a9*b10 + a10*b9 + a11*b8
-	s20 := a9*b11 + a10*b10 + a11*b9
-	s21 := a10*b11 + a11*b10
-	s22 := a11 * b11
-	s23 := int64(0)
-
-	carry[0] = (s0 + (1 << 20)) >> 21
-	s1 += carry[0]
-	s0 -= carry[0] << 21
-	carry[2] = (s2 + (1 << 20)) >> 21
-	s3 += carry[2]
-	s2 -= carry[2] << 21
-	carry[4] = (s4 + (1 << 20)) >> 21
-	s5 += carry[4]
-	s4 -= carry[4] << 21
-	carry[6] = (s6 + (1 << 20)) >> 21
-	s7 += carry[6]
-	s6 -= carry[6] << 21
-	carry[8] = (s8 + (1 << 20)) >> 21
-	s9 += carry[8]
-	s8 -= carry[8] << 21
-	carry[10] = (s10 + (1 << 20)) >> 21
-	s11 += carry[10]
-	s10 -= carry[10] << 21
-	carry[12] = (s12 + (1 << 20)) >> 21
-	s13 += carry[12]
-	s12 -= carry[12] << 21
-	carry[14] = (s14 + (1 << 20)) >> 21
-	s15 += carry[14]
-	s14 -= carry[14] << 21
-	carry[16] = (s16 + (1 << 20)) >> 21
-	s17 += carry[16]
-	s16 -= carry[16] << 21
-	carry[18] = (s18 + (1 << 20)) >> 21
-	s19 += carry[18]
-	s18 -= carry[18] << 21
-	carry[20] = (s20 + (1 << 20)) >> 21
-	s21 += carry[20]
-	s20 -= carry[20] << 21
-	carry[22] = (s22 + (1 << 20)) >> 21
-	s23 += carry[22]
-	s22 -= carry[22] << 21
-
-	carry[1] = (s1 + (1 << 20)) >> 21
-	s2 += carry[1]
-	s1 -= carry[1] << 21
-	carry[3] = (s3 + (1 << 20)) >> 21
-	s4 += carry[3]
-	s3 -= carry[3] << 21
-	carry[5] = (s5 + (1 << 20)) >> 21
-	s6 += carry[5]
-	s5 -= carry[5] << 21
-	carry[7] = (s7 + (1 << 20)) >> 21
-	s8 += carry[7]
-	s7 -= carry[7] << 21
-	carry[9] = (s9 + (1 << 20)) >> 21
-	s10 += carry[9]
-	s9 -= carry[9] << 21
-	carry[11] = (s11 + (1 << 20)) >> 21
-	s12 += carry[11]
-	s11 -= carry[11] << 21
-	carry[13] = (s13 + (1 << 20)) >> 21
-	s14 += carry[13]
-	s13 -= carry[13] << 21
-	carry[15] = (s15 + (1 << 20)) >> 21
-	s16 += carry[15]
-	s15 -= carry[15] << 21
-	carry[17] = (s17 + (1 << 20)) >> 21
-	s18 += carry[17]
-	s17 -= carry[17] << 21
-	carry[19] = (s19 + (1 << 20)) >> 21
-	s20 += carry[19]
-	s19 -= carry[19] << 21
-	carry[21] = (s21 + (1 << 20)) >> 21
-	s22 += carry[21]
-	s21 -= carry[21] << 21
-
-	s11 += s23 * 666643
-	s12 += s23 * 470296
-	s13 += s23 * 654183
-	s14 -= s23 * 997805
-	s15 += s23 * 136657
-	s16 -= s23 * 683901
-	s23 = 0
-
-	s10 += s22 * 666643
-	s11 += s22 * 470296
-	s12 += s22 * 654183
-	s13 -= s22 * 997805
-	s14 += s22 * 136657
-	s15 -= s22 * 683901
-	s22 = 0
-
-	s9 += s21 * 666643
-	s10 += s21 * 470296
-	s11 += s21 * 654183
-	s12 -= s21 * 997805
-	s13 += s21 * 136657
-	s14 -= s21 * 683901
-	s21 = 0
-
-	s8 += s20 * 666643
-	s9 += s20 * 470296
-	s10 += s20 * 654183
-	s11 -= s20 * 997805
-	s12 += s20 * 136657
-	s13 -= s20 * 683901
-	s20 = 0
-
-	s7 += s19 * 666643
-	s8 += s19 * 470296
-	s9 += s19 * 654183
-	s10 -= s19 * 997805
-	s11 += s19 * 136657
-	s12 -= s19 * 683901
-	s19 = 0
-
-	s6 += s18 * 666643
-	s7 += s18 * 470296
-	s8 += s18 * 654183
-	s9 -= s18 * 997805
-	s10 += s18 * 136657
-	s11 -= s18 * 683901
-	s18 = 0
-
-	carry[6] = (s6 + (1 << 20)) >> 21
-	s7 += carry[6]
-	s6 -= carry[6] << 21
-	carry[8] = (s8 + (1 << 20)) >> 21
-	s9 += carry[8]
-	s8 -= carry[8] << 21
-	carry[10] = (s10 + (1 << 20)) >> 21
-	s11 += carry[10]
-	s10 -= carry[10] << 21
-	carry[12] = (s12 + (1 << 20)) >> 21
-	s13 += carry[12]
-	s12 -= carry[12] << 21
-	carry[14] = (s14 + (1 << 20)) >> 21
-	s15 += carry[14]
-	s14 -= carry[14] << 21
-	carry[16] = (s16 + (1 << 20)) >> 21
-	s17 += carry[16]
-	s16 -= carry[16] << 21
-
-	carry[7] = (s7 + (1 << 20)) >> 21
-	s8 += carry[7]
-	s7 -= carry[7] << 21
-	carry[9] = (s9 + (1 << 20)) >> 21
-	s10 += carry[9]
-	s9 -= carry[9] << 21
-	carry[11] = (s11 + (1 << 20)) >> 21
-	s12 += carry[11]
-	s11 -= carry[11] << 21
-	carry[13] = (s13 + (1 << 20)) >> 21
-	s14 += carry[13]
-	s13 -= carry[13] << 21
-	carry[15] = (s15 + (1 << 20)) >> 21
-	s16 += carry[15]
-	s15 -= carry[15] << 21
-
-	s5 += s17 * 666643
-	s6 += s17 * 470296
-	s7 += s17 * 654183
-	s8 -= s17 * 997805
-	s9 += s17 * 136657
-	s10 -= s17 * 683901
-	s17 = 0
-
-	s4 += s16 * 666643
-	s5 += s16 * 470296
-	s6 += s16 * 654183
-	s7 -= s16 * 997805
-	s8 += s16 * 136657
-	s9 -= s16 * 683901
-	s16 = 0
-
-	s3 += s15 * 666643
-	s4 += s15 * 470296
-	s5 += s15 * 654183
-	s6 -= s15 * 997805
-	s7 += s15 * 136657
-	s8 -= s15 * 683901
-	s15 = 0
-
-	s2 += s14 * 666643
-	s3 += s14 * 470296
-	s4 += s14 * 654183
-	s5 -= s14 * 997805
-	s6 += s14 * 136657
-	s7 -= s14 * 683901
-	s14 = 0
-
-	s1 += s13 * 666643
-	s2 += s13 * 470296
-	s3 += s13 * 654183
-	s4 -= s13 * 997805
-	s5 += s13 * 136657
-	s6 -= s13 * 683901
-	s13 = 0
-
-	s0 += s12 * 666643
-	s1 += s12 * 470296
-	s2 += s12 * 654183
-	s3 -= s12 * 997805
-	s4 += s12 * 136657
-	s5 -= s12 * 683901
-	s12 = 0
-
-	carry[0] = (s0 + (1 << 20)) >> 21
-	s1 += carry[0]
-	s0 -= carry[0] << 21
-	carry[2] = (s2 + (1 << 20)) >> 21
-	s3 += carry[2]
-	s2 -= carry[2] << 21
-	carry[4] = (s4 + (1 << 20)) >> 21
-	s5 += carry[4]
-	s4 -= carry[4] << 21
-	carry[6] = (s6 + (1 << 20)) >> 21
-	s7 += carry[6]
-	s6 -= carry[6] << 21
-	carry[8] = (s8 + (1 << 20)) >> 21
-	s9 += carry[8]
-	s8 -= carry[8] << 21
-	carry[10] = (s10 + (1 << 20)) >> 21
-	s11 += carry[10]
-	s10 -= carry[10] << 21
-
-	carry[1] = (s1 + (1 << 20)) >> 21
-	s2 += carry[1]
-	s1 -= carry[1] << 21
-	carry[3] = (s3 + (1 << 20)) >> 21
-	s4 += carry[3]
-	s3 -= carry[3] << 21
-	carry[5] = (s5 + (1 << 20)) >> 21
-	s6 += carry[5]
-	s5 -= carry[5] << 21
-	carry[7] = (s7 + (1 << 20)) >> 21
-	s8 += carry[7]
-	s7 -= carry[7] << 21
-	carry[9] = (s9 + (1 << 20)) >> 21
-	s10 += carry[9]
-	s9 -= carry[9] << 21
-	carry[11] = (s11 + (1 << 20)) >> 21
-	s12 += carry[11]
-	s11 -= carry[11] << 21
-
-	s0 += s12 * 666643
-	s1 += s12 * 470296
-	s2 += s12 * 654183
-	s3 -= s12 * 997805
-	s4 += s12 * 136657
-	s5 -= s12 * 683901
-	s12 = 0
-
-	carry[0] = s0 >> 21
-	s1 += carry[0]
-	s0 -= carry[0] << 21
-	carry[1] = s1 >> 21
-	s2 += carry[1]
-	s1 -= carry[1] << 21
-	carry[2] = s2 >> 21
-	s3 += carry[2]
-	s2 -= carry[2] << 21
-	carry[3] = s3 >> 21
-	s4 += carry[3]
-	s3 -= carry[3] << 21
-	carry[4] = s4 >> 21
-	s5 += carry[4]
-	s4 -= carry[4] << 21
-	carry[5] = s5 >> 21
-	s6 += carry[5]
-	s5 -= carry[5] << 21
-	carry[6] = s6 >> 21
-	s7 += carry[6]
-	s6 -= carry[6] << 21
-	carry[7] = s7 >> 21
-	s8 += carry[7]
-	s7 -= carry[7] << 21
-	carry[8] = s8 >> 21
-	s9 += carry[8]
-	s8 -= carry[8] << 21
-	carry[9] = s9 >> 21
-	s10 += carry[9]
-	s9 -= carry[9] << 21
-	carry[10] = s10 >> 21
-	s11 += carry[10]
-	s10 -= carry[10] << 21
-	carry[11] = s11 >> 21
-	s12 += carry[11]
-	s11 -= carry[11] << 21
-
-	s0 += s12 * 666643
-	s1 += s12 * 470296
-	s2 += s12 * 654183
-	s3 -= s12 * 997805
-	s4 += s12 * 136657
-	s5 -= s12 * 683901
-	s12 = 0
-
-	carry[0] = s0 >> 21
-	s1 += carry[0]
-	s0 -= carry[0] << 21
-	carry[1] = s1 >> 21
-	s2 += carry[1]
-	s1 -= carry[1] << 21
-	carry[2] = s2 >> 21
-	s3 += carry[2]
-	s2 -= carry[2] << 21
-	carry[3] = s3 >> 21
-	s4 += carry[3]
-	s3 -= carry[3] << 21
-	carry[4] = s4 >> 21
-	s5 += carry[4]
-	s4 -= carry[4] << 21
-	carry[5] = s5 >> 21
-	s6 += carry[5]
-	s5 -= carry[5] << 21
-	carry[6] = s6 >> 21
-	s7 += carry[6]
-	s6 -= carry[6] << 21
-	carry[7] = s7 >> 21
-	s8 += carry[7]
-	s7 -= carry[7] << 21
-	carry[8] = s8 >> 21
-	s9 += carry[8]
-	s8 -= carry[8] << 21
-	carry[9] = s9 >> 21
-	s10 += carry[9]
-	s9 -= carry[9] << 21
-	carry[10] = s10 >> 21
-	s11 += carry[10]
-	s10 -= carry[10] << 21
-
-	s[0] = byte(s0 >> 0)
-	s[1] = byte(s0 >> 8)
-	s[2] = byte((s0 >> 16) | (s1 << 5))
-	s[3] = byte(s1 >> 3)
-	s[4] = byte(s1 >> 11)
-	s[5] = byte((s1 >> 19) | (s2 << 2))
-	s[6] = byte(s2 >> 6)
-	s[7] = byte((s2 >> 14) | (s3 << 7))
-	s[8] = byte(s3 >> 1)
-	s[9] = byte(s3 >> 9)
-	s[10] = byte((s3 >> 17) | (s4 << 4))
-	s[11] = byte(s4 >> 4)
-	s[12] = byte(s4 >> 12)
-	s[13] = byte((s4 >> 20) | (s5 << 1))
-	s[14] = byte(s5 >> 7)
-	s[15] = byte((s5 >> 15) | (s6 << 6))
-	s[16] = byte(s6 >> 2)
-	s[17] = byte(s6 >> 10)
-	s[18] = byte((s6 >> 18) | (s7 << 3))
-	s[19] = byte(s7 >> 5)
-	s[20] = byte(s7 >> 13)
-	s[21] = byte(s8 >> 0)
-	s[22] = byte(s8 >> 8)
-	s[23] = byte((s8 >> 16) | (s9 << 5))
-	s[24] = byte(s9 >> 3)
-	s[25] = byte(s9 >> 11)
-	s[26] = byte((s9 >> 19) | (s10 << 2))
-	s[27] = byte(s10 >> 6)
-	s[28] = byte((s10 >> 14) | (s11 << 7))
-	s[29] = byte(s11 >> 1)
-	s[30] = byte(s11 >> 9)
-	s[31] = byte(s11 >> 17)
+	a0, a1, a2, a3 := scLoad(a[:])
+	b0, b1, b2, b3 := scLoad(b[:])
+	c0, c1, c2, c3 := scLoad(c[:])
+	t0, t1, t2, t3, t4, t5, t6, t7 := scMul512(a0, a1, a2, a3, b0, b1, b2, b3)
+	// a * b + c < 2^512, so there is no carry out.
+	var carry uint64
+	t0, carry = bits.Add64(t0, c0, 0)
+	t1, carry = bits.Add64(t1, c1, carry)
+	t2, carry = bits.Add64(t2, c2, carry)
+	t3, carry = bits.Add64(t3, c3, carry)
+	t4, carry = bits.Add64(t4, 0, carry)
+	t5, carry = bits.Add64(t5, 0, carry)
+	t6, carry = bits.Add64(t6, 0, carry)
+	t7, _ = bits.Add64(t7, 0, carry)
+	t0, t1, t2, t3 = scReduce512(t0, t1, t2, t3, t4, t5, t6, t7)
+	scStore(s, t0, t1, t2, t3)
 }
 
-// Input:
-//   s[0]+256*s[1]+...+256^63*s[63] = s
-//
-// Output:
-//   s[0]+256*s[1]+...+256^31*s[31] = s mod l
-//   where l = 2^252 + 27742317777372353535851937790883648493.
+// scReduce sets out = s mod l, where s is a 64 bytes little-endian encoding.
 func scReduce(out *[32]byte, s *[64]byte) {
-	s0 := 2097151 & load3(s[:])
-	s1 := 2097151 & (load4(s[2:]) >> 5)
-	s2 := 2097151 & (load3(s[5:]) >> 2)
-	s3 := 2097151 & (load4(s[7:]) >> 7)
-	s4 := 2097151 & (load4(s[10:]) >> 4)
-	s5 := 2097151 & (load3(s[13:]) >> 1)
-	s6 := 2097151 & (load4(s[15:]) >> 6)
-	s7 := 2097151 & (load3(s[18:]) >> 3)
-	s8 := 2097151 & load3(s[21:])
-	s9 := 2097151 & (load4(s[23:]) >> 5)
-	s10 := 2097151 & (load3(s[26:]) >> 2)
-	s11 := 2097151 & (load4(s[28:]) >> 7)
-	s12 := 2097151 & (load4(s[31:]) >> 4)
-	s13 := 2097151 & (load3(s[34:]) >> 1)
-	s14 := 2097151 & (load4(s[36:]) >> 6)
-	s15 := 2097151 & (load3(s[39:]) >> 3)
-	s16 := 2097151 & load3(s[42:])
-	s17 := 2097151 & (load4(s[44:]) >> 5)
-	s18 := 2097151 & (load3(s[47:]) >> 2)
-	s19 := 2097151 & (load4(s[49:]) >> 7)
-	s20 := 2097151 & (load4(s[52:]) >> 4)
-	s21 := 2097151 & (load3(s[55:]) >> 1)
-	s22 := 2097151 & (load4(s[57:]) >> 6)
-	s23 := (load4(s[60:]) >> 3)
-
-	s11 += s23 * 666643
-	s12 += s23 * 470296
-	s13 += s23 * 654183
-	s14 -= s23 * 997805
-	s15 += s23 * 136657
-	s16 -= s23 * 683901
-	s23 = 0
-
-	s10 += s22 * 666643
-	s11 += s22 * 470296
-	s12 += s22 * 654183
-	s13 -= s22 * 997805
-	s14 += s22 * 136657
-	s15 -= s22 * 683901
-	s22 = 0
-
-	s9 += s21 * 666643
-	s10 += s21 * 470296
-	s11 += s21 * 654183
-	s12 -= s21 * 997805
-	s13 += s21 * 136657
-	s14 -= s21 * 683901
-	s21 = 0
-
-	s8 += s20 * 666643
-	s9 += s20 * 470296
-	s10 += s20 * 654183
-	s11 -= s20 * 997805
-	s12 += s20 * 136657
-	s13 -= s20 * 683901
-	s20 = 0
-
-	s7 += s19 * 666643
-	s8 += s19 * 470296
-	s9 += s19 * 654183
-	s10 -= s19 * 997805
-	s11 += s19 * 136657
-	s12 -= s19 * 683901
-	s19 = 0
-
-	s6 += s18 * 666643
-	s7 += s18 * 470296
-	s8 += s18 * 654183
-	s9 -= s18 * 997805
-	s10 += s18 * 136657
-	s11 -= s18 * 683901
-	s18 = 0
-
-	var carry [17]int64
-
-	carry[6] = (s6 + (1 << 20)) >> 21
-	s7 += carry[6]
-	s6 -= carry[6] << 21
-	carry[8] = (s8 + (1 << 20)) >> 21
-	s9 += carry[8]
-	s8 -= carry[8] << 21
-	carry[10] = (s10 + (1 << 20)) >> 21
-	s11 += carry[10]
-	s10 -= carry[10] << 21
-	carry[12] = (s12 + (1 << 20)) >> 21
-	s13 += carry[12]
-	s12 -= carry[12] << 21
-	carry[14] = (s14 + (1 << 20)) >> 21
-	s15 += carry[14]
-	s14 -= carry[14] << 21
-	carry[16] = (s16 + (1 << 20)) >> 21
-	s17 += carry[16]
-	s16 -= carry[16] << 21
-
-	carry[7] = (s7 + (1 << 20)) >> 21
-	s8 += carry[7]
-	s7 -= carry[7] << 21
-	carry[9] = (s9 + (1 << 20)) >> 21
-	s10 += carry[9]
-	s9 -= carry[9] << 21
-	carry[11] = (s11 + (1 << 20)) >> 21
-	s12 += carry[11]
-	s11 -= carry[11] << 21
-	carry[13] = (s13 + (1 << 20)) >> 21
-	s14 += carry[13]
-	s13 -= carry[13] << 21
-	carry[15] = (s15 + (1 << 20)) >> 21
-	s16 += carry[15]
-	s15 -= carry[15] << 21
-
-	s5 += s17 * 666643
-	s6 += s17 * 470296
-	s7 += s17 * 654183
-	s8 -= s17 * 997805
-	s9 += s17 * 136657
-	s10 -= s17 * 683901
-	s17 = 0
-
-	s4 += s16 * 666643
-	s5 += s16 * 470296
-	s6 += s16 * 654183
-	s7 -= s16 * 997805
-	s8 += s16 * 136657
-	s9 -= s16 * 683901
-	s16 = 0
-
-	s3 += s15 * 666643
-	s4 += s15 * 470296
-	s5 += s15 * 654183
-	s6 -= s15 * 997805
-	s7 += s15 * 136657
-	s8 -= s15 * 683901
-	s15 = 0
-
-	s2 += s14 * 666643
-	s3 += s14 * 470296
-	s4 += s14 * 654183
-	s5 -= s14 * 997805
-	s6 += s14 * 136657
-	s7 -= s14 * 683901
-	s14 = 0
-
-	s1 += s13 * 666643
-	s2 += s13 * 470296
-	s3 += s13 * 654183
-	s4 -= s13 * 997805
-	s5 += s13 * 136657
-	s6 -= s13 * 683901
-	s13 = 0
-
-	s0 += s12 * 666643
-	s1 += s12 * 470296
-	s2 += s12 * 654183
-	s3 -= s12 * 997805
-	s4 += s12 * 136657
-	s5 -= s12 * 683901
-	s12 = 0
-
-	carry[0] = (s0 + (1 << 20)) >> 21
-	s1 += carry[0]
-	s0 -= carry[0] << 21
-	carry[2] = (s2 + (1 << 20)) >> 21
-	s3 += carry[2]
-	s2 -= carry[2] << 21
-	carry[4] = (s4 + (1 << 20)) >> 21
-	s5 += carry[4]
-	s4 -= carry[4] << 21
-	carry[6] = (s6 + (1 << 20)) >> 21
-	s7 += carry[6]
-	s6 -= carry[6] << 21
-	carry[8] = (s8 + (1 << 20)) >> 21
-	s9 += carry[8]
-	s8 -= carry[8] << 21
-	carry[10] = (s10 + (1 << 20)) >> 21
-	s11 += carry[10]
-	s10 -= carry[10] << 21
-
-	carry[1] = (s1 + (1 << 20)) >> 21
-	s2 += carry[1]
-	s1 -= carry[1] << 21
-	carry[3] = (s3 + (1 << 20)) >> 21
-	s4 += carry[3]
-	s3 -= carry[3] << 21
-	carry[5] = (s5 + (1 << 20)) >> 21
-	s6 += carry[5]
-	s5 -= carry[5] << 21
-	carry[7] = (s7 + (1 << 20)) >> 21
-	s8 += carry[7]
-	s7 -= carry[7] << 21
-	carry[9] = (s9 + (1 << 20)) >> 21
-	s10 += carry[9]
-	s9 -= carry[9] << 21
-	carry[11] = (s11 + (1 << 20)) >> 21
-	s12 += carry[11]
-	s11 -= carry[11] << 21
-
-	s0 += s12 * 666643
-	s1 += s12 * 470296
-	s2 += s12 * 654183
-	s3 -= s12 * 997805
-	s4 += s12 * 136657
-	s5 -= s12 * 683901
-	s12 = 0
-
-	carry[0] = s0 >> 21
-	s1 += carry[0]
-	s0 -= carry[0] << 21
-	carry[1] = s1 >> 21
-	s2 += carry[1]
-	s1 -= carry[1] << 21
-	carry[2] = s2 >> 21
-	s3 += carry[2]
-	s2 -= carry[2] << 21
-	carry[3] = s3 >> 21
-	s4 += carry[3]
-	s3 -= carry[3] << 21
-	carry[4] = s4 >> 21
-	s5 += carry[4]
-	s4 -= carry[4] << 21
-	carry[5] = s5 >> 21
-	s6 += carry[5]
-	s5 -= carry[5] << 21
-	carry[6] = s6 >> 21
-	s7 += carry[6]
-	s6 -= carry[6] << 21
-	carry[7] = s7 >> 21
-	s8 += carry[7]
-	s7 -= carry[7] << 21
-	carry[8] = s8 >> 21
-	s9 += carry[8]
-	s8 -= carry[8] << 21
-	carry[9] = s9 >> 21
-	s10 += carry[9]
-	s9 -= carry[9] << 21
-	carry[10] = s10 >> 21
-	s11 += carry[10]
-	s10 -= carry[10] << 21
-	carry[11] = s11 >> 21
-	s12 += carry[11]
-	s11 -= carry[11] << 21
-
-	s0 += s12 * 666643
-	s1 += s12 * 470296
-	s2 += s12 * 654183
-	s3 -= s12 * 997805
-	s4 += s12 * 136657
-	s5 -= s12 * 683901
-	s12 = 0
-
-	carry[0] = s0 >> 21
-	s1 += carry[0]
-	s0 -= carry[0] << 21
-	carry[1] = s1 >> 21
-	s2 += carry[1]
-	s1 -= carry[1] << 21
-	carry[2] = s2 >> 21
-	s3 += carry[2]
-	s2 -= carry[2] << 21
-	carry[3] = s3 >> 21
-	s4 += carry[3]
-	s3 -= carry[3] << 21
-	carry[4] = s4 >> 21
-	s5 += carry[4]
-	s4 -= carry[4] << 21
-	carry[5] = s5 >> 21
-	s6 += carry[5]
-	s5 -= carry[5] << 21
-	carry[6] = s6 >> 21
-	s7 += carry[6]
-	s6 -= carry[6] << 21
-	carry[7] = s7 >> 21
-	s8 += carry[7]
-	s7 -= carry[7] << 21
-	carry[8] = s8 >> 21
-	s9 += carry[8]
-	s8 -= carry[8] << 21
-	carry[9] = s9 >> 21
-	s10 += carry[9]
-	s9 -= carry[9] << 21
-	carry[10] = s10 >> 21
-	s11 += carry[10]
-	s10 -= carry[10] << 21
-
-	out[0] = byte(s0 >> 0)
-	out[1] = byte(s0 >> 8)
-	out[2] = byte((s0 >> 16) | (s1 << 5))
-	out[3] = byte(s1 >> 3)
-	out[4] = byte(s1 >> 11)
-	out[5] = byte((s1 >> 19) | (s2 << 2))
-	out[6] = byte(s2 >> 6)
-	out[7] = byte((s2 >> 14) | (s3 << 7))
-	out[8] = byte(s3 >> 1)
-	out[9] = byte(s3 >> 9)
-	out[10] = byte((s3 >> 17) | (s4 << 4))
-	out[11] = byte(s4 >> 4)
-	out[12] = byte(s4 >> 12)
-	out[13] = byte((s4 >> 20) | (s5 << 1))
-	out[14] = byte(s5 >> 7)
-	out[15] = byte((s5 >> 15) | (s6 << 6))
-	out[16] = byte(s6 >> 2)
-	out[17] = byte(s6 >> 10)
-	out[18] = byte((s6 >> 18) | (s7 << 3))
-	out[19] = byte(s7 >> 5)
-	out[20] = byte(s7 >> 13)
-	out[21] = byte(s8 >> 0)
-	out[22] = byte(s8 >> 8)
-	out[23] = byte((s8 >> 16) | (s9 << 5))
-	out[24] = byte(s9 >> 3)
-	out[25] = byte(s9 >> 11)
-	out[26] = byte((s9 >> 19) | (s10 << 2))
-	out[27] = byte(s10 >> 6)
-	out[28] = byte((s10 >> 14) | (s11 << 7))
-	out[29] = byte(s11 >> 1)
-	out[30] = byte(s11 >> 9)
-	out[31] = byte(s11 >> 17)
+	t0, t1, t2, t3 := scLoad(s[:32])
+	t4, t5, t6, t7 := scLoad(s[32:])
+	t0, t1, t2, t3 = scReduce512(t0, t1, t2, t3, t4, t5, t6, t7)
+	scStore(out, t0, t1, t2, t3)
+}
+
+// NonAdjacentForm returns the width-w non-adjacent form of s, 256 digits d[i]
+// with the least significant first, such that s = sum(d[i] * 2^i). Each
+// nonzero digit is odd and less than 2^(w-1) in absolute value, and there is at
+// most one nonzero digit in any w consecutive ones.
+//
+// w must be between 2 and 12, or NonAdjacentForm will panic. A lookup table of
+// the odd multiples of a point up to 2^(w-1) - 1 is enough to multiply it by s.
+//
+// Execution time depends on s.
+func (s *Scalar) NonAdjacentForm(w int) [256]int16 {
+	if w < 2 || w > 12 {
+		panic("edwards25519: invalid NonAdjacentForm width")
+	}
+	return s.nonAdjacentFormWide(uint(w))
+}
+
+// SignedRadix2w returns the signed radix-2^w digits of s, d[i] with the least
+// significant first, such that s = sum(d[i] * 2^(w*i)). There are (256+w-1)/w
+// digits, each between -2^(w-1) and 2^(w-1) - 1, so that a table of the
+// multiples of a point from 1 to 2^(w-1) is enough to multiply it by s.
+//
+// w must be between 2 and 8, or SignedRadix2w will panic.
+//
+// Execution time depends only on w.
+func (s *Scalar) SignedRadix2w(w int) []int8 {
+	if w < 2 || w > 8 {
+		panic("edwards25519: invalid SignedRadix2w width")
+	}
+	digits := make([]int8, (256+w-1)/w)
+	s.signedRadix2w(uint(w), digits)
+	return digits
 }
 
 // nonAdjacentForm computes a width-w non-adjacent form for this scalar.
 //
 // w must be between 2 and 8, or nonAdjacentForm will panic.
 func (s *Scalar) nonAdjacentForm(w uint) [256]int8 {
+	if w > 8 {
+		panic("NAF digits must fit in int8")
+	}
+	wide := s.nonAdjacentFormWide(w)
+	var naf [256]int8
+	for i, d := range wide {
+		naf[i] = int8(d)
+	}
+	return naf
+}
+
+// nonAdjacentFormWide is like nonAdjacentForm, but supports widths up to 12
+// thanks to its wider digits.
+func (s *Scalar) nonAdjacentFormWide(w uint) [256]int16 {
 	// This implementation is adapted from the one
 	// in curve25519-dalek and is documented there:
 	// https://github.com/dalek-cryptography/curve25519-dalek/blob/f630041af28e9a405255f98a8a93adca18e4315b/src/scalar.rs#L800-L871
@@ -941,11 +567,11 @@ func (s *Scalar) nonAdjacentForm(w uint) [256]int8 {
 	}
 	if w < 2 {
 		panic("w must be at least 2 by the definition of NAF")
-	} else if w > 8 {
-		panic("NAF digits must fit in int8")
+	} else if w > 12 {
+		panic("NAF width is too large")
 	}
 
-	var naf [256]int8
+	var naf [256]int16
 	var digits [5]uint64
 
 	for i := 0; i < 4; i++ {
@@ -985,10 +611,10 @@ func (s *Scalar) nonAdjacentForm(w uint) [256]int8 {
 
 		if window < width/2 {
 			carry = 0
-			naf[pos] = int8(window)
+			naf[pos] = int16(window)
 		} else {
 			carry = 1
-			naf[pos] = int8(window) - int8(width)
+			naf[pos] = int16(window) - int16(width)
 		}
 
 		pos += w
@@ -1135,3 +761,73 @@ func (s *Scalar) Invert(t *Scalar) *Scalar {
 
 	return s
 }
+
+// InvertScalars sets each dst[i] to the inverse of src[i], using a single
+// inversion and 3(n-1) multiplications with Montgomery's trick. dst and src
+// must have the same length, and dst[i] may alias src[i].
+//
+// If any element of src is zero, InvertScalars will panic.
+func InvertScalars(dst, src []*Scalar) {
+	if len(dst) != len(src) {
+		panic("edwards25519: called InvertScalars with different size inputs")
+	}
+	if len(src) == 0 {
+		return
+	}
+
+	// products[i] is the product of src[0] through src[i].
+	products := make([]Scalar, len(src))
+	products[0].Set(src[0])
+	for i := 1; i < len(src); i++ {
+		products[i].Multiply(&products[i-1], src[i])
+	}
+	if products[len(src)-1].Equal(&scZero) == 1 {
+		panic("edwards25519: zero Scalar passed to InvertScalars")
+	}
+
+	// acc is the inverse of products[i], and multiplying it by
+	// products[i-1] yields the inverse of src[i].
+	var acc, inv Scalar
+	acc.Invert(&products[len(src)-1])
+	for i := len(src) - 1; i > 0; i-- {
+		inv.Multiply(&acc, &products[i-1])
+		acc.Multiply(&acc, src[i])
+		dst[i].Set(&inv)
+	}
+	dst[0].Set(&acc)
+}
+
+// Exp sets s = x^e, where e is the little-endian encoding of a non-negative
+// exponent of any size, and returns s.
+//
+// Execution time depends only on len(e).
+func (s *Scalar) Exp(x *Scalar, e []byte) *Scalar {
+	// table[i] = x^i, for a fixed window of 4 bits.
+	var table [16]Scalar
+	table[0].Set(&scOne)
+	table[1].Set(x)
+	for i := 2; i < len(table); i++ {
+		table[i].Multiply(&table[i-1], x)
+	}
+
+	var acc, factor Scalar
+	acc.Set(&scOne)
+	for i := len(e) - 1; i >= 0; i-- {
+		acc.pow2k(4)
+		factor.selectFrom(&table, e[i]>>4)
+		acc.Multiply(&acc, &factor)
+		acc.pow2k(4)
+		factor.selectFrom(&table, e[i]&15)
+		acc.Multiply(&acc, &factor)
+	}
+	return s.Set(&acc)
+}
+
+// selectFrom sets s = table[i], in constant time, and returns s.
+func (s *Scalar) selectFrom(table *[16]Scalar, i byte) *Scalar {
+	*s = scZero
+	for j := range table {
+		subtle.ConstantTimeCopy(subtle.ConstantTimeByteEq(uint8(j), i), s.s[:], table[j].s[:])
+	}
+	return s
+}
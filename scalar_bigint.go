@@ -0,0 +1,81 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// SetBigInt sets s = x, and returns s. If x is negative or not less than l,
+// SetBigInt returns nil and an error, and the receiver is unchanged. Use
+// big.Int.Mod to reduce other values first.
+//
+// SetBigInt, like all math/big operations, is not constant time.
+func (s *Scalar) SetBigInt(x *big.Int) (*Scalar, error) {
+	if x.Sign() < 0 || x.BitLen() > 253 {
+		return nil, errors.New("edwards25519: big.Int out of scalar range")
+	}
+	b := x.Bytes()
+	ss := &Scalar{}
+	for i := range b {
+		ss.s[i] = b[len(b)-1-i]
+	}
+	if !isReduced(ss) {
+		return nil, errors.New("edwards25519: big.Int out of scalar range")
+	}
+	s.s = ss.s
+	return s, nil
+}
+
+// BigInt returns the value of s as a new big.Int.
+//
+// BigInt, like all math/big operations, is not constant time.
+func (s *Scalar) BigInt() *big.Int {
+	var b [32]byte
+	for i := range b {
+		b[i] = s.s[len(b)-1-i]
+	}
+	return new(big.Int).SetBytes(b[:])
+}
+
+// SetString sets s to the value of str in the given base, and returns s. The
+// base and the syntax are interpreted as by big.Int.SetString, so base 0
+// accepts the 0x, 0o and 0b prefixes. If str is not a valid number in base, or
+// its value is negative or not less than l, SetString returns nil and an
+// error, and the receiver is unchanged.
+//
+// Note that str is the numeric value of the scalar, and not the hex encoding
+// of its little-endian representation, as used by Bytes and most test vectors.
+// Use encoding/hex and SetCanonicalBytes for the latter.
+//
+// SetString is not constant time.
+func (s *Scalar) SetString(str string, base int) (*Scalar, error) {
+	x, ok := new(big.Int).SetString(str, base)
+	if !ok {
+		return nil, errors.New("edwards25519: invalid scalar string")
+	}
+	return s.SetBigInt(x)
+}
+
+// String returns the decimal value of s.
+//
+// String is not constant time.
+func (s *Scalar) String() string {
+	return s.BigInt().String()
+}
+
+// Format implements fmt.Formatter, formatting the numeric value of s like a
+// big.Int, for example in hexadecimal with the %x verb.
+//
+// Note that %x prints the number most significant digit first, without leading
+// zeroes, while MarshalText hex encodes the 32 bytes little-endian encoding of
+// Bytes.
+//
+// Format is not constant time.
+func (s *Scalar) Format(f fmt.State, verb rune) {
+	s.BigInt().Format(f, verb)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestScalarBigInt(t *testing.T) {
+	roundTrip := func(x Scalar) bool {
+		b := x.BigInt()
+		if b.Cmp(bigIntFromLittleEndianBytes(x.s[:])) != 0 {
+			return false
+		}
+		var y Scalar
+		if _, err := y.SetBigInt(b); err != nil {
+			return false
+		}
+		return y == x
+	}
+	if err := quick.Check(roundTrip, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+
+	l := new(big.Int).Add(scMinusOne.BigInt(), big.NewInt(1))
+	for _, x := range []*big.Int{
+		big.NewInt(-1),
+		l,
+		new(big.Int).Lsh(big.NewInt(1), 253),
+		new(big.Int).Lsh(big.NewInt(1), 256),
+	} {
+		s := NewScalar().Set(&dalekScalar)
+		if out, err := s.SetBigInt(x); err == nil || out != nil {
+			t.Errorf("SetBigInt(%v): expected an error", x)
+		}
+		if *s != dalekScalar {
+			t.Errorf("SetBigInt(%v): receiver was modified", x)
+		}
+	}
+	if s, err := NewScalar().SetBigInt(big.NewInt(0)); err != nil || *s != scZero {
+		t.Errorf("SetBigInt(0) = %v, %v", s, err)
+	}
+}
+
+func TestScalarString(t *testing.T) {
+	roundTrip := func(x Scalar) bool {
+		for _, tt := range []struct {
+			str  string
+			base int
+		}{
+			{x.String(), 10},
+			{fmt.Sprintf("%x", &x), 16},
+			{fmt.Sprintf("%#x", &x), 0},
+		} {
+			var y Scalar
+			if _, err := y.SetString(tt.str, tt.base); err != nil || y != x {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(roundTrip, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+
+	const lMinusOne = "7237005577332262213973186563042994240857116359379907606001950938285454250988"
+	if got := scMinusOne.String(); got != lMinusOne {
+		t.Errorf("String() = %s, want %s", got, lMinusOne)
+	}
+	if got := fmt.Sprintf("%x|%X|%v|%s", &scMinusOne, &scOne, &scOne, &scZero); got != "1000000000000000000000000000000014def9dea2f79cd65812631a5cf5d3ec|1|1|0" {
+		t.Errorf("unexpected formatting: %s", got)
+	}
+	// %x is the numeric value, MarshalText is the little-endian encoding.
+	x := NewScalar().SetUint64(0x0102)
+	if got := fmt.Sprintf("%x", x); got != "102" {
+		t.Errorf("%%x of 0x0102 = %s, want 102", got)
+	}
+	if got, _ := x.MarshalText(); string(got) != "0201"+strings.Repeat("00", 30) {
+		t.Errorf("MarshalText of 0x0102 = %s, want 0201 followed by zeroes", got)
+	}
+	if s, err := NewScalar().SetString("0x10", 0); err != nil || s.Equal(NewScalar().SetUint64(16)) != 1 {
+		t.Errorf("SetString(0x10, 0) = %v, %v", s, err)
+	}
+	for _, tt := range []struct {
+		str  string
+		base int
+	}{
+		{"", 10},
+		{"12g", 16},
+		{"-1", 10},
+		{"7237005577332262213973186563042994240857116359379907606001950938285454250989", 10},
+	} {
+		s := NewScalar().Set(&dalekScalar)
+		if out, err := s.SetString(tt.str, tt.base); err == nil || out != nil || *s != dalekScalar {
+			t.Errorf("SetString(%q, %d): expected an error and an unchanged receiver", tt.str, tt.base)
+		}
+	}
+}
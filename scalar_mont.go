@@ -0,0 +1,91 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import "crypto/subtle"
+
+// A ScalarMont is an integer modulo l, like a Scalar, kept in Montgomery form
+// x * 2^256 mod l in four 64-bit limbs. A multiplication then needs a single
+// Montgomery reduction, instead of the full 512-bit reduction of a Scalar, and
+// no encoding and decoding of the limbs, which makes long chains of
+// multiplications almost twice as fast.
+//
+// Values are converted from and to Scalar with SetScalar and Scalar, which
+// cost about as much as a multiplication, so ScalarMont pays off only for
+// several operations on the same values.
+//
+// Like Scalar, all arguments and receivers are allowed to alias, all
+// operations are constant time, and the zero value is a valid zero element.
+type ScalarMont struct {
+	// l holds x * 2^256 mod l, with the least significant limb first. The
+	// value is always reduced between operations.
+	l [4]uint64
+}
+
+// SetScalar sets m = x, and returns m.
+func (m *ScalarMont) SetScalar(x *Scalar) *ScalarMont {
+	x0, x1, x2, x3 := scLoad(x.s[:])
+	// x * 2^512 * 2^-256 = x * 2^256 mod l.
+	m.l[0], m.l[1], m.l[2], m.l[3] = scMontgomeryReduce(
+		scMul512(x0, x1, x2, x3, scR2x0, scR2x1, scR2x2, scR2x3))
+	return m
+}
+
+// Scalar returns the value of m as a new Scalar.
+func (m *ScalarMont) Scalar() *Scalar {
+	x0, x1, x2, x3 := scMontgomeryReduce(m.l[0], m.l[1], m.l[2], m.l[3], 0, 0, 0, 0)
+	s := &Scalar{}
+	scStore(&s.s, x0, x1, x2, x3)
+	return s
+}
+
+// Set sets m = x, and returns m.
+func (m *ScalarMont) Set(x *ScalarMont) *ScalarMont {
+	*m = *x
+	return m
+}
+
+// Add sets m = x + y mod l, and returns m.
+func (m *ScalarMont) Add(x, y *ScalarMont) *ScalarMont {
+	m.l[0], m.l[1], m.l[2], m.l[3] = scAdd256(x.l[0], x.l[1], x.l[2], x.l[3],
+		y.l[0], y.l[1], y.l[2], y.l[3])
+	return m
+}
+
+// Subtract sets m = x - y mod l, and returns m.
+func (m *ScalarMont) Subtract(x, y *ScalarMont) *ScalarMont {
+	m.l[0], m.l[1], m.l[2], m.l[3] = scSubtract256(x.l[0], x.l[1], x.l[2], x.l[3],
+		y.l[0], y.l[1], y.l[2], y.l[3])
+	return m
+}
+
+// Negate sets m = -x mod l, and returns m.
+func (m *ScalarMont) Negate(x *ScalarMont) *ScalarMont {
+	return m.Subtract(&ScalarMont{}, x)
+}
+
+// Multiply sets m = x * y mod l, and returns m.
+func (m *ScalarMont) Multiply(x, y *ScalarMont) *ScalarMont {
+	// (x * 2^256) * (y * 2^256) * 2^-256 = x * y * 2^256 mod l, and the
+	// product is less than l^2 < l * 2^256, as Montgomery reduction requires.
+	m.l[0], m.l[1], m.l[2], m.l[3] = scMontgomeryReduce(
+		scMul512(x.l[0], x.l[1], x.l[2], x.l[3], y.l[0], y.l[1], y.l[2], y.l[3]))
+	return m
+}
+
+// MultiplyAdd sets m = x * y + z mod l, and returns m.
+func (m *ScalarMont) MultiplyAdd(x, y, z *ScalarMont) *ScalarMont {
+	// Save z, which might alias m.
+	z0, z1, z2, z3 := z.l[0], z.l[1], z.l[2], z.l[3]
+	m.Multiply(x, y)
+	m.l[0], m.l[1], m.l[2], m.l[3] = scAdd256(m.l[0], m.l[1], m.l[2], m.l[3], z0, z1, z2, z3)
+	return m
+}
+
+// Equal returns 1 if m and x are equal, and 0 otherwise.
+func (m *ScalarMont) Equal(x *ScalarMont) int {
+	diff := (m.l[0] ^ x.l[0]) | (m.l[1] ^ x.l[1]) | (m.l[2] ^ x.l[2]) | (m.l[3] ^ x.l[3])
+	return subtle.ConstantTimeEq(int32(uint32(diff)|uint32(diff>>32)), 0)
+}
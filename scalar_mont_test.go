@@ -0,0 +1,84 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	mathrand "math/rand"
+	"testing"
+	"testing/quick"
+)
+
+func TestScalarMontLikeScalar(t *testing.T) {
+	f := func(x, y, z Scalar) bool {
+		var xm, ym, zm, m ScalarMont
+		xm.SetScalar(&x)
+		ym.SetScalar(&y)
+		zm.SetScalar(&z)
+		check := func(m *ScalarMont, want *Scalar) bool {
+			got := m.Scalar()
+			return got.Equal(want) == 1 && isReduced(got)
+		}
+		return check(&xm, &x) &&
+			check(m.Add(&xm, &ym), NewScalar().Add(&x, &y)) &&
+			check(m.Subtract(&xm, &ym), NewScalar().Subtract(&x, &y)) &&
+			check(m.Negate(&xm), NewScalar().Negate(&x)) &&
+			check(m.Multiply(&xm, &ym), NewScalar().Multiply(&x, &y)) &&
+			check(m.MultiplyAdd(&xm, &ym, &zm), NewScalar().MultiplyAdd(&x, &y, &z)) &&
+			check(m.Set(&zm), &z) &&
+			xm.Equal(m.SetScalar(&x)) == 1 &&
+			xm.Equal(&ym) == x.Equal(&y)
+	}
+	if err := quick.Check(f, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+
+	var zero ScalarMont
+	if zero.Scalar().Equal(NewScalar()) != 1 {
+		t.Error("the zero value is not zero")
+	}
+	if m := new(ScalarMont).SetScalar(&scMinusOne); m.Add(m, new(ScalarMont).SetScalar(&scOne)).Equal(&zero) != 1 {
+		t.Error("-1 + 1 != 0")
+	}
+}
+
+func TestScalarMontAliasing(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	xs := randomScalars(rand, 3)
+	var x, y, z ScalarMont
+	x.SetScalar(xs[0])
+	y.SetScalar(xs[1])
+	z.SetScalar(xs[2])
+	want := NewScalar().MultiplyAdd(xs[0], xs[1], xs[2])
+	if z.MultiplyAdd(&x, &y, &z).Scalar().Equal(want) != 1 {
+		t.Error("MultiplyAdd with z aliasing the receiver is wrong")
+	}
+	want = NewScalar().Multiply(xs[0], xs[0])
+	if x.Multiply(&x, &x).Scalar().Equal(want) != 1 {
+		t.Error("Multiply with all arguments aliasing the receiver is wrong")
+	}
+}
+
+func BenchmarkScalarMultiplyChain(b *testing.B) {
+	const n = 1000
+	b.Run("Scalar", func(b *testing.B) {
+		x := dalekScalar
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < n; j++ {
+				x.Multiply(&x, &dalekScalar)
+			}
+		}
+	})
+	b.Run("ScalarMont", func(b *testing.B) {
+		var x, y ScalarMont
+		x.SetScalar(&dalekScalar)
+		y.SetScalar(&dalekScalar)
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < n; j++ {
+				x.Multiply(&x, &y)
+			}
+		}
+		x.Scalar()
+	})
+}
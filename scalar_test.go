@@ -7,6 +7,7 @@ package edwards25519
 import (
 	"bytes"
 	"encoding/hex"
+	"math"
 	"math/big"
 	mathrand "math/rand"
 	"reflect"
@@ -88,9 +89,13 @@ func TestScalarSetCanonicalBytes(t *testing.T) {
 	}
 }
 
+// scalarOrder is l, the order of the prime order subgroup.
+var scalarOrder = func() *big.Int {
+	l, _ := new(big.Int).SetString("27742317777372353535851937790883648493", 10)
+	return l.Add(l, new(big.Int).Lsh(big.NewInt(1), 252))
+}()
+
 func TestScalarSetUniformBytes(t *testing.T) {
-	mod, _ := new(big.Int).SetString("27742317777372353535851937790883648493", 10)
-	mod.Add(mod, new(big.Int).Lsh(big.NewInt(1), 252))
 	f := func(in [64]byte, sc Scalar) bool {
 		sc.SetUniformBytes(in[:])
 		if !isReduced(&sc) {
@@ -98,11 +103,41 @@ func TestScalarSetUniformBytes(t *testing.T) {
 		}
 		scBig := bigIntFromLittleEndianBytes(sc.s[:])
 		inBig := bigIntFromLittleEndianBytes(in[:])
-		return inBig.Mod(inBig, mod).Cmp(scBig) == 0
+		return inBig.Mod(inBig, scalarOrder).Cmp(scBig) == 0
 	}
 	if err := quick.Check(f, quickCheckConfig1024); err != nil {
 		t.Error(err)
 	}
+
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{48, 63, 65, 95, 96, 97, 114, 128, 200} {
+		in := make([]byte, n)
+		for i := 0; i < 20; i++ {
+			rand.Read(in)
+			if i == 0 {
+				for j := range in {
+					in[j] = 0xff
+				}
+			}
+			var sc Scalar
+			sc.SetUniformBytes(in)
+			inBig := bigIntFromLittleEndianBytes(in)
+			if !isReduced(&sc) || inBig.Mod(inBig, scalarOrder).Cmp(bigIntFromLittleEndianBytes(sc.s[:])) != 0 {
+				t.Errorf("wrong reduction of %d bytes: %x", n, in)
+			}
+		}
+	}
+
+	for _, n := range []int{0, 32, 47} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for %d bytes", n)
+				}
+			}()
+			NewScalar().SetUniformBytes(make([]byte, n))
+		}()
+	}
 }
 
 func TestScalarSetBytesWithClamping(t *testing.T) {
@@ -184,6 +219,50 @@ func TestScalarAddLikeSubNeg(t *testing.T) {
 	}
 }
 
+func TestScalarArithmeticLikeBigInt(t *testing.T) {
+	l := bigIntFromLittleEndianBytes(scMinusOne.s[:])
+	l.Add(l, big.NewInt(1))
+	check := func(got *Scalar, want *big.Int) bool {
+		want.Mod(want, l)
+		return isReduced(got) && bigIntFromLittleEndianBytes(got.s[:]).Cmp(want) == 0
+	}
+
+	f := func(x, y, z Scalar) bool {
+		xb := bigIntFromLittleEndianBytes(x.s[:])
+		yb := bigIntFromLittleEndianBytes(y.s[:])
+		zb := bigIntFromLittleEndianBytes(z.s[:])
+
+		var s Scalar
+		if !check(s.Add(&x, &y), new(big.Int).Add(xb, yb)) {
+			return false
+		}
+		if !check(s.Subtract(&x, &y), new(big.Int).Sub(xb, yb)) {
+			return false
+		}
+		if !check(s.Negate(&x), new(big.Int).Neg(xb)) {
+			return false
+		}
+		if !check(s.Multiply(&x, &y), new(big.Int).Mul(xb, yb)) {
+			return false
+		}
+		want := new(big.Int).Mul(xb, yb)
+		return check(s.MultiplyAdd(&x, &y, &z), want.Add(want, zb))
+	}
+	if err := quick.Check(f, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+
+	// Exercise the extremes of the 512-bit reduction.
+	for _, fill := range []byte{0x00, 0x01, 0x7f, 0x80, 0xff} {
+		in := bytes.Repeat([]byte{fill}, 64)
+		var s Scalar
+		s.SetUniformBytes(in)
+		if !check(&s, bigIntFromLittleEndianBytes(in)) {
+			t.Errorf("SetUniformBytes(%x...) = %x", fill, s.s)
+		}
+	}
+}
+
 func TestScalarNonAdjacentForm(t *testing.T) {
 	s := Scalar{[32]byte{
 		0x1a, 0x0e, 0x97, 0x8a, 0x90, 0xf6, 0x62, 0x2d,
@@ -234,6 +313,277 @@ func TestScalarInvert(t *testing.T) {
 	}
 }
 
+func TestInvertScalars(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{0, 1, 2, 3, 17} {
+		src := make([]*Scalar, n)
+		dst := make([]*Scalar, n)
+		want := make([]Scalar, n)
+		for i := range src {
+			x := notZeroScalar{}.Generate(rand, 0).Interface().(notZeroScalar)
+			src[i] = (*Scalar)(&x)
+			dst[i] = NewScalar()
+			want[i].Invert(src[i])
+		}
+		InvertScalars(dst, src)
+		for i := range dst {
+			if dst[i].Equal(&want[i]) != 1 {
+				t.Errorf("n = %d: element %d is not the inverse", n, i)
+			}
+		}
+
+		// Inverting in place yields the original values back.
+		InvertScalars(dst, dst)
+		for i := range dst {
+			if dst[i].Equal(src[i]) != 1 {
+				t.Errorf("n = %d: element %d is wrong after inverting in place", n, i)
+			}
+		}
+	}
+}
+
+func TestInvertScalarsPanics(t *testing.T) {
+	expectPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: expected a panic", name)
+			}
+		}()
+		f()
+	}
+	one, zero := NewScalar().Set(&scOne), NewScalar()
+	expectPanic("zero", func() {
+		InvertScalars([]*Scalar{NewScalar(), NewScalar()}, []*Scalar{one, zero})
+	})
+	expectPanic("different lengths", func() {
+		InvertScalars([]*Scalar{NewScalar()}, []*Scalar{one, one})
+	})
+}
+
+func TestScalarExp(t *testing.T) {
+	expWorks := func(x Scalar, e []byte) bool {
+		var s Scalar
+		s.Exp(&x, e)
+		want := new(big.Int).Exp(bigIntFromLittleEndianBytes(x.s[:]), bigIntFromLittleEndianBytes(e), scalarOrder)
+		return bigIntFromLittleEndianBytes(s.s[:]).Cmp(want) == 0 && isReduced(&s)
+	}
+	if err := quick.Check(expWorks, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+
+	x := dalekScalar
+	if s := NewScalar().Exp(&x, nil); s.Equal(&scOne) != 1 {
+		t.Error("x^0 != 1")
+	}
+	lMinusTwo := NewScalar().Subtract(&scMinusOne, &scOne)
+	if s := NewScalar().Exp(&x, lMinusTwo.Bytes()); s.Equal(NewScalar().Invert(&x)) != 1 {
+		t.Error("x^(l-2) != 1/x")
+	}
+	want := NewScalar().Multiply(&x, &x)
+	if x.Exp(&x, []byte{2, 0, 0}); x.Equal(want) != 1 {
+		t.Error("aliased x^2 != x * x")
+	}
+}
+
+func TestScalarSetUint64(t *testing.T) {
+	f := func(x uint64) bool {
+		var s Scalar
+		s.s[31] = 0xff // check that the whole value is overwritten
+		s.SetUint64(x)
+		return bigIntFromLittleEndianBytes(s.s[:]).Cmp(new(big.Int).SetUint64(x)) == 0 && isReduced(&s)
+	}
+	if err := quick.Check(f, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+	if NewScalar().SetUint64(1).Equal(&scOne) != 1 {
+		t.Error("SetUint64(1) != 1")
+	}
+}
+
+func TestScalarSetInt64(t *testing.T) {
+	f := func(x int64) bool {
+		var s Scalar
+		s.SetInt64(x)
+		want := new(big.Int).Mod(big.NewInt(x), scalarOrder)
+		return bigIntFromLittleEndianBytes(s.s[:]).Cmp(want) == 0 && isReduced(&s)
+	}
+	if err := quick.Check(f, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+	for _, x := range []int64{0, 1, -1, math.MaxInt64, math.MinInt64} {
+		if !f(x) {
+			t.Errorf("SetInt64(%d) is wrong", x)
+		}
+	}
+	if NewScalar().SetInt64(-1).Equal(&scMinusOne) != 1 {
+		t.Error("SetInt64(-1) != -1")
+	}
+}
+
+func TestScalarCmp(t *testing.T) {
+	cmpWorks := func(x, y Scalar) bool {
+		want := bigIntFromLittleEndianBytes(x.s[:]).Cmp(bigIntFromLittleEndianBytes(y.s[:]))
+		return x.Cmp(&y) == want && y.Cmp(&x) == -want && x.Cmp(&x) == 0
+	}
+	if err := quick.Check(cmpWorks, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+	// The least significant byte must not take precedence.
+	if NewScalar().SetUint64(0x100).Cmp(NewScalar().SetUint64(0xff)) != 1 {
+		t.Error("0x100 is not greater than 0xff")
+	}
+}
+
+func TestScalarHalve(t *testing.T) {
+	halveWorks := func(x Scalar, k uint8) bool {
+		var h, check Scalar
+		h.Halve(&x)
+		check.Add(&h, &h)
+		if check != x || !isReduced(&h) {
+			return false
+		}
+
+		k %= 64
+		h.DivPow2(&x, uint(k))
+		check.Set(&h)
+		for i := uint8(0); i < k; i++ {
+			check.Add(&check, &check)
+		}
+		return check == x && isReduced(&h)
+	}
+	if err := quick.Check(halveWorks, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+
+	// sage: l(1/8).lift().digits(256)
+	invEight := Scalar{[32]byte{121, 47, 220, 226, 41, 229, 6, 97, 208, 218, 28, 125, 179, 157, 211, 7, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 6}}
+	if NewScalar().DivPow2(&scOne, 3).Equal(&invEight) != 1 {
+		t.Error("1 / 2^3 != 1/8")
+	}
+	x := dalekScalar
+	if x.DivPow2(&x, 3).Multiply(&x, NewScalar().SetUint64(8)).Equal(&dalekScalar) != 1 {
+		t.Error("aliased DivPow2 is wrong")
+	}
+}
+
+func TestScalarNonAdjacentFormProperties(t *testing.T) {
+	nafWorks := func(x Scalar, w8 uint8) bool {
+		w := 2 + int(w8%11)
+		naf := x.NonAdjacentForm(w)
+		sum := new(big.Int)
+		lastNonZero := len(naf) + w // no nonzero digit yet
+		for i := len(naf) - 1; i >= 0; i-- {
+			sum.Lsh(sum, 1)
+			d := naf[i]
+			if d == 0 {
+				continue
+			}
+			if d%2 == 0 || d >= 1<<(w-1) || d <= -1<<(w-1) || lastNonZero-i < w {
+				return false
+			}
+			lastNonZero = i
+			sum.Add(sum, big.NewInt(int64(d)))
+		}
+		return sum.Cmp(bigIntFromLittleEndianBytes(x.s[:])) == 0
+	}
+	if err := quick.Check(nafWorks, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+
+	for _, w := range []int{-1, 0, 1, 13} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for width %d", w)
+				}
+			}()
+			dalekScalar.NonAdjacentForm(w)
+		}()
+	}
+}
+
+func TestScalarSignedRadix2wPublic(t *testing.T) {
+	radixWorks := func(x Scalar, w8 uint8) bool {
+		w := 2 + int(w8%7)
+		digits := x.SignedRadix2w(w)
+		if len(digits) != (256+w-1)/w {
+			return false
+		}
+		sum := new(big.Int)
+		for i := len(digits) - 1; i >= 0; i-- {
+			// Unlike the internal function, which accepts any s < 2^255, the
+			// top digit is in range too, because s is reduced.
+			if digits[i] < -(1<<(w-1)) || int(digits[i]) >= 1<<(w-1) {
+				return false
+			}
+			sum.Lsh(sum, uint(w))
+			sum.Add(sum, big.NewInt(int64(digits[i])))
+		}
+		return sum.Cmp(bigIntFromLittleEndianBytes(x.s[:])) == 0
+	}
+	if err := quick.Check(radixWorks, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+
+	if got, want := dalekScalar.SignedRadix2w(4), dalekScalar.signedRadix16(); !bytes.Equal(int8sToBytes(got), int8sToBytes(want[:])) {
+		t.Errorf("SignedRadix2w(4) = %v, want %v", got, want)
+	}
+
+	for _, w := range []int{-1, 0, 1, 9} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic for width %d", w)
+				}
+			}()
+			dalekScalar.SignedRadix2w(w)
+		}()
+	}
+}
+
+func int8sToBytes(d []int8) []byte {
+	b := make([]byte, len(d))
+	for i := range d {
+		b[i] = byte(d[i])
+	}
+	return b
+}
+
+func TestClampBytes(t *testing.T) {
+	clampWorks := func(b [32]byte) bool {
+		wasClamped := IsClamped(b[:])
+		orig := b
+		ClampBytes(b[:])
+		if !IsClamped(b[:]) || wasClamped != (b == orig) {
+			return false
+		}
+		// Only the five clamping bits may change.
+		for i := range b {
+			diff := b[i] ^ orig[i]
+			if i == 0 && diff&^7 != 0 || i == 31 && diff&^0xc0 != 0 || i != 0 && i != 31 && diff != 0 {
+				return false
+			}
+		}
+		var s, want Scalar
+		s.SetBytesWithClamping(orig[:])
+		want.SetBytesWithClamping(b[:])
+		return s == want
+	}
+	if err := quick.Check(clampWorks, quickCheckConfig1024); err != nil {
+		t.Error(err)
+	}
+
+	ones := bytes.Repeat([]byte{0xff}, 32)
+	ClampBytes(ones)
+	if ones[0] != 0xf8 || ones[31] != 0x7f {
+		t.Errorf("ClampBytes(ff...) = %x", ones)
+	}
+	if IsClamped(ones[:31]) || IsClamped(nil) {
+		t.Error("IsClamped accepted a short input")
+	}
+}
+
 func TestScalarEqual(t *testing.T) {
 	if scOne.Equal(&scMinusOne) == 1 {
 		t.Errorf("scOne.Equal(&scMinusOne) is true")
@@ -242,3 +592,61 @@ func TestScalarEqual(t *testing.T) {
 		t.Errorf("scMinusOne.Equal(&scMinusOne) is false")
 	}
 }
+
+func BenchmarkScalarMultiply(b *testing.B) {
+	x := dalekScalar
+	for i := 0; i < b.N; i++ {
+		x.Multiply(&x, &dalekScalar)
+	}
+}
+
+func BenchmarkScalarMultiplyAdd(b *testing.B) {
+	x := dalekScalar
+	for i := 0; i < b.N; i++ {
+		x.MultiplyAdd(&x, &dalekScalar, &dalekScalar)
+	}
+}
+
+func BenchmarkScalarAdd(b *testing.B) {
+	x := dalekScalar
+	for i := 0; i < b.N; i++ {
+		x.Add(&x, &dalekScalar)
+	}
+}
+
+func BenchmarkScalarSetUniformBytes(b *testing.B) {
+	buf := bytes.Repeat([]byte{0xaa}, 64)
+	var x Scalar
+	for i := 0; i < b.N; i++ {
+		x.SetUniformBytes(buf)
+	}
+}
+
+func BenchmarkInvertScalars(b *testing.B) {
+	const n = 256
+	src := make([]*Scalar, n)
+	dst := make([]*Scalar, n)
+	for i := range src {
+		src[i] = NewScalar().Set(&dalekScalar)
+		dst[i] = NewScalar()
+	}
+	b.Run("Invert", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range src {
+				dst[j].Invert(src[j])
+			}
+		}
+	})
+	b.Run("InvertScalars", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			InvertScalars(dst, src)
+		}
+	})
+}
+
+func BenchmarkScalarExp(b *testing.B) {
+	x, e := dalekScalar, dalekScalar.Bytes()
+	for i := 0; i < b.N; i++ {
+		x.Exp(&x, e)
+	}
+}
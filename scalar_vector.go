@@ -0,0 +1,80 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import "math/bits"
+
+// SumScalars returns the sum of xs, or zero if xs is empty.
+//
+// The sum is accumulated without intermediate reductions, so it is faster
+// than a chain of Add calls.
+func SumScalars(xs []*Scalar) *Scalar {
+	// Each scalar is less than 2^253, so the sum fits in five words for any
+	// length that fits in memory.
+	var t0, t1, t2, t3, t4 uint64
+	for _, x := range xs {
+		x0, x1, x2, x3 := scLoad(x.s[:])
+		var c uint64
+		t0, c = bits.Add64(t0, x0, 0)
+		t1, c = bits.Add64(t1, x1, c)
+		t2, c = bits.Add64(t2, x2, c)
+		t3, c = bits.Add64(t3, x3, c)
+		t4 += c
+	}
+	s := &Scalar{}
+	t0, t1, t2, t3 = scReduce512(t0, t1, t2, t3, t4, 0, 0, 0)
+	scStore(&s.s, t0, t1, t2, t3)
+	return s
+}
+
+// ProductScalars returns the product of xs, or one if xs is empty.
+func ProductScalars(xs []*Scalar) *Scalar {
+	s := NewScalar().Set(&scOne)
+	for _, x := range xs {
+		s.Multiply(s, x)
+	}
+	return s
+}
+
+// innerProductBatch is the number of products InnerProduct accumulates
+// between reductions. Each product is less than l^2 < 2^506, so the sum of
+// fewer than 2^6 of them, plus a reduced value, fits in 512 bits.
+const innerProductBatch = 32
+
+// InnerProduct returns sum(a[i] * b[i]), or zero if a and b are empty. a and b
+// must have the same length.
+//
+// The products are accumulated without reducing each of them, so it is about
+// twice as fast as a chain of MultiplyAdd calls.
+func InnerProduct(a, b []*Scalar) *Scalar {
+	if len(a) != len(b) {
+		panic("edwards25519: called InnerProduct with different size inputs")
+	}
+
+	var t0, t1, t2, t3, t4, t5, t6, t7 uint64
+	for i := range a {
+		a0, a1, a2, a3 := scLoad(a[i].s[:])
+		b0, b1, b2, b3 := scLoad(b[i].s[:])
+		p0, p1, p2, p3, p4, p5, p6, p7 := scMul512(a0, a1, a2, a3, b0, b1, b2, b3)
+		var c uint64
+		t0, c = bits.Add64(t0, p0, 0)
+		t1, c = bits.Add64(t1, p1, c)
+		t2, c = bits.Add64(t2, p2, c)
+		t3, c = bits.Add64(t3, p3, c)
+		t4, c = bits.Add64(t4, p4, c)
+		t5, c = bits.Add64(t5, p5, c)
+		t6, c = bits.Add64(t6, p6, c)
+		t7, _ = bits.Add64(t7, p7, c)
+
+		if i%innerProductBatch == innerProductBatch-1 {
+			t0, t1, t2, t3 = scReduce512(t0, t1, t2, t3, t4, t5, t6, t7)
+			t4, t5, t6, t7 = 0, 0, 0, 0
+		}
+	}
+	s := &Scalar{}
+	t0, t1, t2, t3 = scReduce512(t0, t1, t2, t3, t4, t5, t6, t7)
+	scStore(&s.s, t0, t1, t2, t3)
+	return s
+}
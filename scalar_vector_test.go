@@ -0,0 +1,74 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	mathrand "math/rand"
+	"testing"
+)
+
+func randomScalars(rand *mathrand.Rand, n int) []*Scalar {
+	xs := make([]*Scalar, n)
+	for i := range xs {
+		x := Scalar{}.Generate(rand, 0).Interface().(Scalar)
+		xs[i] = &x
+	}
+	return xs
+}
+
+func TestScalarVectorOperations(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{0, 1, 2, 31, 32, 33, 64, 100, 1000} {
+		a, b := randomScalars(rand, n), randomScalars(rand, n)
+		if n == 1000 {
+			// Maximize the partial sums to exercise the carries.
+			for i := range a {
+				a[i], b[i] = &scMinusOne, &scMinusOne
+			}
+		}
+
+		sum, product, inner := NewScalar(), NewScalar().Set(&scOne), NewScalar()
+		for i := range a {
+			sum.Add(sum, a[i])
+			product.Multiply(product, a[i])
+			inner.MultiplyAdd(a[i], b[i], inner)
+		}
+
+		if got := SumScalars(a); got.Equal(sum) != 1 || !isReduced(got) {
+			t.Errorf("n = %d: SumScalars is wrong", n)
+		}
+		if got := ProductScalars(a); got.Equal(product) != 1 || !isReduced(got) {
+			t.Errorf("n = %d: ProductScalars is wrong", n)
+		}
+		if got := InnerProduct(a, b); got.Equal(inner) != 1 || !isReduced(got) {
+			t.Errorf("n = %d: InnerProduct is wrong", n)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for different size inputs")
+		}
+	}()
+	InnerProduct([]*Scalar{&scOne}, nil)
+}
+
+func BenchmarkInnerProduct(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	x, y := randomScalars(rand, 64), randomScalars(rand, 64)
+	b.Run("MultiplyAdd", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s := NewScalar()
+			for j := range x {
+				s.MultiplyAdd(x[j], y[j], s)
+			}
+		}
+	})
+	b.Run("InnerProduct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			InnerProduct(x, y)
+		}
+	})
+}
@@ -4,6 +4,13 @@
 
 package edwards25519
 
+import (
+	cryptorand "crypto/rand"
+	"errors"
+	"io"
+	"runtime"
+)
+
 // ScalarBaseMult sets v = x * B, where B is the canonical generator, and
 // returns v.
 //
@@ -18,18 +25,53 @@ func (v *Point) ScalarBaseMult(x *Scalar) *Point {
 	// x*B     = x_0*16^0*B + x_2*16^2*B + ... + x_62*16^62*B
 	//    + 16*( x_1*16^0*B + x_3*16^2*B + ... + x_63*16^62*B)
 	//
-	// We use a lookup table for each i to get x_i*16^(2*i)*B
-	// and do four doublings to multiply by 16.
+	// Table i holds the multiples of 16^(2*i)*B = 256^i*B, so both x_(2*i)
+	// and x_(2*i+1) are looked up in table i, and the odd terms get their
+	// extra factor of 16 from four doublings.
+	return v.basepointMultAdd(x, nil, NewIdentityPoint())
+}
+
+// ScalarBaseMultAdd sets v = x * B + p, where B is the canonical generator,
+// and returns v. It costs about as much as ScalarBaseMult followed by Add, but
+// never materializes x * B, which is useful when x is secret.
+//
+// The scalar multiplication is done in constant time.
+func (v *Point) ScalarBaseMultAdd(x *Scalar, p *Point) *Point {
+	checkInitialized(p)
+	// Add p right before the even pass of the ScalarBaseMult loop. Adding it
+	// to the odd pass would instead add 16 * p, as it gets multiplied by 16.
+	// As p is projective, it takes a full addition either way, and saves only
+	// the cached conversion of x * B that Add would do.
+	var pCached projCached
+	pCached.FromP3(p)
+	return v.basepointMultAdd(x, &pCached, NewIdentityPoint())
+}
+
+// fixedBaseMult sets v = x * Q, where table holds the precomputed multiples
+// of Q in the same layout as basepointTable, and returns v.
+func (v *Point) fixedBaseMult(table *[32]affineLookupTable, x *Scalar) *Point {
+	return v.fixedBaseMultAdd(table, x, nil, NewIdentityPoint())
+}
+
+// fixedBaseMultAdd is like fixedBaseMult, but if p is not nil it sets
+// v = x * Q + p instead. The accumulator starts from identity, which must be a
+// representation of the identity point.
+func (v *Point) fixedBaseMultAdd(table *[32]affineLookupTable, x *Scalar, p *projCached, identity *Point) *Point {
 	digits := x.signedRadix16()
+	ct := newCTState()
+	ct.poisonDigits(digits[:])
+	ct.checkAffineTables(table)
+	ct.checkCached(p)
+	ct.checkPoint(identity)
 
 	multiple := &affineCached{}
 	tmp1 := &projP1xP1{}
 	tmp2 := &projP2{}
 
 	// Accumulate the odd components first
-	v.Set(NewIdentityPoint())
+	v.Set(identity)
 	for i := 1; i < 64; i += 2 {
-		basepointTable[i/2].SelectInto(multiple, digits[i])
+		table[i/2].SelectInto(multiple, digits[i])
 		tmp1.AddAffine(v, multiple)
 		v.fromP1xP1(tmp1)
 	}
@@ -45,18 +87,31 @@ func (v *Point) ScalarBaseMult(x *Scalar) *Point {
 	tmp1.Double(tmp2)    // tmp1 = 16*v in P1xP1 coords
 	v.fromP1xP1(tmp1)    // now v = 16*(odd components)
 
+	if p != nil {
+		tmp1.Add(v, p)
+		v.fromP1xP1(tmp1) // now v = 16*(odd components) + p
+	}
+
 	// Accumulate the even components
 	for i := 0; i < 64; i += 2 {
-		basepointTable[i/2].SelectInto(multiple, digits[i])
+		table[i/2].SelectInto(multiple, digits[i])
 		tmp1.AddAffine(v, multiple)
 		v.fromP1xP1(tmp1)
 	}
 
+	wipeDigits(digits[:])
+	multiple.Zero()
+	runtime.KeepAlive(multiple)
+	ct.resultPoint(v)
 	return v
 }
 
 // ScalarMult sets v = x * q, and returns v.
 //
+// The lookup table of q is built anew at every call, as a Point carries no
+// hidden state. To multiply the same point many times, build its table once
+// with NewPrecomputedPoint and use PrecomputedPoint.ScalarMult instead.
+//
 // The scalar multiplication is done in constant time.
 func (v *Point) ScalarMult(x *Scalar, q *Point) *Point {
 	checkInitialized(q)
@@ -72,14 +127,196 @@ func (v *Point) ScalarMult(x *Scalar, q *Point) *Point {
 	// We use the lookup table to get the x_i*Q values
 	// and do four doublings to compute 16*Q
 	digits := x.signedRadix16()
+	v.scalarMultDigits(&digits, &table, NewIdentityPoint())
+	wipeDigits(digits[:])
+	return v
+}
+
+// ScalarMultChecked sets v = x * q, and returns v, like ScalarMult. If q has
+// small order, or if the result is the identity, ScalarMultChecked returns
+// nil and an error and the receiver is unchanged. This ensures a
+// Diffie-Hellman shared secret is contributory, that is, that it depends on
+// both parties' secrets. In particular, x = 0 is always rejected.
+//
+// The check on q is done in variable time, as q is assumed to be public. The
+// scalar multiplication and the check on the result are done in constant
+// time, and only whether the result is the identity is revealed.
+func (v *Point) ScalarMultChecked(x *Scalar, q *Point) (*Point, error) {
+	checkInitialized(q)
+	if (&Point{}).MultByCofactor(q).varTimeIsIdentity() {
+		return nil, errors.New("edwards25519: small order point")
+	}
+
+	var p Point
+	p.ScalarMult(x, q)
+	if p.Equal(NewIdentityPoint()) == 1 {
+		return nil, errors.New("edwards25519: scalar multiplication produced the identity")
+	}
+	return v.Set(&p), nil
+}
+
+// ScalarMultBlinded sets v = x * q, and returns v, like ScalarMult. Before the
+// multiplication, the projective coordinates of q and of the initial
+// accumulator are multiplied by a random nonzero λ read from rand, so that
+// intermediate values differ across executions even for identical inputs. If
+// rand is nil, crypto/rand.Reader is used. If reading from rand fails, or if
+// it keeps returning zero or out of range values, ScalarMultBlinded returns
+// nil and an error, and the receiver is unchanged.
+//
+// The result is in randomized projective coordinates too, but encodes like the
+// result of ScalarMult.
+//
+// The scalar multiplication is done in constant time.
+func (v *Point) ScalarMultBlinded(rand io.Reader, x *Scalar, q *Point) (*Point, error) {
+	checkInitialized(q)
+
+	lambda, err := randomNonZeroFieldElement(rand)
+	if err != nil {
+		return nil, err
+	}
+	var p Point
+	p.x.Multiply(&q.x, lambda)
+	p.y.Multiply(&q.y, lambda)
+	p.z.Multiply(&q.z, lambda)
+	p.t.Multiply(&q.t, lambda)
+
+	var table projLookupTable
+	table.FromP3(&p)
+	digits := x.signedRadix16()
+	v.scalarMultDigits(&digits, &table, blindedIdentity(lambda))
+	wipeDigits(digits[:])
+	return v, nil
+}
+
+// ScalarBaseMultBlinded sets v = x * B, where B is the canonical generator, and
+// returns v, like ScalarBaseMult. As the table of multiples of B is fixed, the
+// accumulator is instead started from the identity in random projective
+// coordinates (0 : λ : λ : 0), with λ read from rand as in ScalarMultBlinded.
+//
+// The scalar multiplication is done in constant time.
+func (v *Point) ScalarBaseMultBlinded(rand io.Reader, x *Scalar) (*Point, error) {
+	lambda, err := randomNonZeroFieldElement(rand)
+	if err != nil {
+		return nil, err
+	}
+	return v.basepointMultAdd(x, nil, blindedIdentity(lambda)), nil
+}
+
+// ScalarMultSplit sets v = x * q, and returns v, like ScalarMult. The scalar
+// is split as x = x1 + x2 mod l, with x1 uniformly random read from rand, and
+// x1 * q + x2 * q is computed with shared doublings, so that the digits of x
+// never drive the table lookups directly. If rand is nil, crypto/rand.Reader
+// is used. If reading from rand fails, ScalarMultSplit returns nil and the
+// error, and the receiver is unchanged.
+//
+// It costs about 30% more than ScalarMult, for the extra 64 additions.
+//
+// The scalar multiplication is done in constant time.
+func (v *Point) ScalarMultSplit(rand io.Reader, x *Scalar, q *Point) (*Point, error) {
+	checkInitialized(q)
+
+	x1, x2, err := splitScalar(rand, x)
+	if err != nil {
+		return nil, err
+	}
+	var tables [2]projLookupTable
+	tables[0].FromP3(q)
+	tables[1] = tables[0]
+	v.multiScalarMult([]*Scalar{x1, x2}, tables[:], 64)
+	*x1, *x2 = Scalar{}, Scalar{}
+	runtime.KeepAlive(x1)
+	runtime.KeepAlive(x2)
+	return v, nil
+}
+
+// splitScalar returns x1 and x2 such that x = x1 + x2 mod l, where x1 is
+// uniformly random read from rand, or from crypto/rand.Reader if rand is nil.
+func splitScalar(rand io.Reader, x *Scalar) (x1, x2 *Scalar, err error) {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	var buf [64]byte
+	if _, err := io.ReadFull(rand, buf[:]); err != nil {
+		return nil, nil, err
+	}
+	x1 = NewScalar().SetUniformBytes(buf[:])
+	x2 = NewScalar().Subtract(x, x1)
+	return x1, x2, nil
+}
+
+// blindedIdentity returns the identity point in projective coordinates
+// (0 : λ : λ : 0).
+func blindedIdentity(lambda *fieldElement) *Point {
+	p := &Point{}
+	p.x.Zero()
+	p.y.Set(lambda)
+	p.z.Set(lambda)
+	p.t.Zero()
+	return p
+}
+
+// errRandomRetries is returned when a random source keeps producing values
+// that are rejected, which can only happen if it is broken.
+var errRandomRetries = errors.New("edwards25519: random source returned too many rejected values")
 
+// randomNonZeroFieldElement returns a uniformly random nonzero field element
+// read from rand, or from crypto/rand.Reader if rand is nil.
+func randomNonZeroFieldElement(rand io.Reader) (*fieldElement, error) {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	v := &fieldElement{}
+	var buf [32]byte
+	// Rejecting non-canonical encodings keeps the distribution uniform, and
+	// both retries happen with negligible probability, so a reader that needs
+	// more than a handful is stuck, and would otherwise make us loop forever.
+	for i := 0; i < 128; i++ {
+		if _, err := io.ReadFull(rand, buf[:]); err != nil {
+			return nil, err
+		}
+		buf[31] &= 0x7f
+		if _, err := v.setCanonicalBytes(buf[:]); err == nil && v.Equal(feZero) == 0 {
+			return v, nil
+		}
+	}
+	return nil, errRandomRetries
+}
+
+// BatchScalarMult returns x * points[i] for each of the points. It computes
+// the digits of x only once, and is otherwise equivalent to calling
+// ScalarMult for each point.
+//
+// The scalar multiplications are done in constant time.
+func BatchScalarMult(x *Scalar, points []*Point) []*Point {
+	checkInitialized(points...)
+
+	digits := x.signedRadix16()
+	out := make([]Point, len(points))
+	results := make([]*Point, len(points))
+	var table projLookupTable
+	for i := range points {
+		table.FromP3(points[i])
+		results[i] = out[i].scalarMultDigits(&digits, &table, NewIdentityPoint())
+	}
+	wipeDigits(digits[:])
+	return results
+}
+
+// scalarMultDigits sets v = x * Q, where digits is x.signedRadix16() and table
+// holds the multiples of Q, and returns v. The accumulator starts from
+// identity, which must be a representation of the identity point.
+func (v *Point) scalarMultDigits(digits *[64]int8, table *projLookupTable, identity *Point) *Point {
 	// Unwrap first loop iteration to save computing 16*identity
 	multiple := &projCached{}
 	tmp1 := &projP1xP1{}
 	tmp2 := &projP2{}
+	ct := newCTState()
+	ct.poisonDigits(digits[:])
+	ct.checkTable(table)
+	ct.checkPoint(identity)
 	table.SelectInto(multiple, digits[63])
 
-	v.Set(NewIdentityPoint())
+	v.Set(identity)
 	tmp1.Add(v, multiple) // tmp1 = x_63*Q in P1xP1 coords
 	for i := 62; i >= 0; i-- {
 		tmp2.FromP1xP1(tmp1) // tmp2 =    (prev) in P2 coords
@@ -95,9 +332,24 @@ func (v *Point) ScalarMult(x *Scalar, q *Point) *Point {
 		tmp1.Add(v, multiple) // tmp1 = x_i*Q + 16*(prev) in P1xP1 coords
 	}
 	v.fromP1xP1(tmp1)
+	multiple.Zero()
+	runtime.KeepAlive(multiple)
+	ct.resultPoint(v)
 	return v
 }
 
+// wipeDigits zeroes the digits of a secret scalar, which the constant-time
+// functions do before returning, as they would otherwise linger in memory.
+// It's not inlined, so the compiler can't elide the stores even though the
+// digits are not read afterwards.
+//
+//go:noinline
+func wipeDigits(d []int8) {
+	for i := range d {
+		d[i] = 0
+	}
+}
+
 // MultiScalarMult sets v = sum(scalars[i] * points[i]), and returns v.
 //
 // Execution time depends only on the lengths of the two slices, which must match.
@@ -110,15 +362,88 @@ func (v *Point) MultiScalarMult(scalars []*Scalar, points []*Point) *Point {
 	// Proceed as in the single-base case, but share doublings
 	// between each point in the multiscalar equation.
 
-	// Build lookup tables for each point
-	tables := make([]projLookupTable, len(points))
+	// Build lookup tables for each point, on the stack for small inputs.
+	var tablesBuf [msmStackSize]projLookupTable
+	tables := projLookupTables(&tablesBuf, points)
+	return v.multiScalarMult(scalars, tables, 64)
+}
+
+// msmStackSize is the number of inputs up to which MultiScalarMult and
+// MultiScalarMultBounded keep their scratch buffers on the stack, rather than
+// allocating them. Their size is about 1.3KiB per input.
+const msmStackSize = 8
+
+// projLookupTables returns the lookup tables of points, stored in buf if they
+// fit, or in a new slice otherwise.
+func projLookupTables(buf *[msmStackSize]projLookupTable, points []*Point) []projLookupTable {
+	var tables []projLookupTable
+	if len(points) <= len(buf) {
+		tables = buf[:len(points)]
+	} else {
+		tables = make([]projLookupTable, len(points))
+	}
 	for i := range tables {
 		tables[i].FromP3(points[i])
 	}
+	return tables
+}
+
+// radix16Digits returns a slice of n digit arrays, which is buf if n fits, or
+// a new slice otherwise.
+func radix16Digits(buf *[msmStackSize][64]int8, n int) [][64]int8 {
+	if n <= len(buf) {
+		return buf[:n]
+	}
+	return make([][64]int8, n)
+}
+
+// MultiScalarMultBounded sets v = sum(scalars[i] * points[i]), and returns v.
+// All scalars must be lower than 2^bits, or MultiScalarMultBounded will panic,
+// and only the corresponding radix-16 digits are processed, making it faster
+// than MultiScalarMult for short scalars. bits must be positive.
+//
+// Execution time depends only on the lengths of the two slices, which must
+// match, and on bits, which is treated as public.
+func (v *Point) MultiScalarMultBounded(scalars []*Scalar, points []*Point, bits int) *Point {
+	if len(scalars) != len(points) {
+		panic("edwards25519: called MultiScalarMultBounded with different size inputs")
+	}
+	if bits <= 0 {
+		panic("edwards25519: invalid MultiScalarMultBounded bit bound")
+	}
+	checkInitialized(points...)
+	fits := 1
+	for i := range scalars {
+		fits &= scalars[i].fitsInBits(bits)
+	}
+	if fits != 1 {
+		panic("edwards25519: scalar exceeds MultiScalarMultBounded bit bound")
+	}
+
+	// A scalar lower than 2^(4k) has k radix-16 digits, plus possibly a
+	// carry into the next one after recentering.
+	numDigits := (bits+3)/4 + 1
+	if numDigits > 64 {
+		numDigits = 64
+	}
+
+	var tablesBuf [msmStackSize]projLookupTable
+	tables := projLookupTables(&tablesBuf, points)
+	return v.multiScalarMult(scalars, tables, numDigits)
+}
+
+// multiScalarMult sets v = sum(scalars[i] * Q_i), where tables[i] is the
+// lookup table of Q_i, and returns v. Only the lowest numDigits radix-16
+// digits of the scalars are processed, the others must be zero.
+func (v *Point) multiScalarMult(scalars []*Scalar, tables []projLookupTable, numDigits int) *Point {
 	// Compute signed radix-16 digits for each scalar
-	digits := make([][64]int8, len(scalars))
+	var digitsBuf [msmStackSize][64]int8
+	digits := radix16Digits(&digitsBuf, len(scalars))
+	ct := newCTState()
 	for i := range digits {
 		digits[i] = scalars[i].signedRadix16()
+		ct.poisonDigits(digits[i][:])
+		ct.checkTable(&tables[i])
 	}
 
 	// Unwrap first loop iteration to save computing 16*identity
@@ -126,13 +451,14 @@ func (v *Point) MultiScalarMult(scalars []*Scalar, points []*Point) *Point {
 	tmp1 := &projP1xP1{}
 	tmp2 := &projP2{}
 	// Lookup-and-add the appropriate multiple of each input point
+	v.Set(NewIdentityPoint())
 	for j := range tables {
-		tables[j].SelectInto(multiple, digits[j][63])
-		tmp1.Add(v, multiple) // tmp1 = v + x_(j,63)*Q in P1xP1 coords
+		tables[j].SelectInto(multiple, digits[j][numDigits-1])
+		tmp1.Add(v, multiple) // tmp1 = v + x_(j,top)*Q in P1xP1 coords
 		v.fromP1xP1(tmp1)     // update v
 	}
 	tmp2.FromP3(v) // set up tmp2 = v in P2 coords for next iteration
-	for i := 62; i >= 0; i-- {
+	for i := numDigits - 2; i >= 0; i-- {
 		tmp1.Double(tmp2)    // tmp1 =  2*(prev) in P1xP1 coords
 		tmp2.FromP1xP1(tmp1) // tmp2 =  2*(prev) in P2 coords
 		tmp1.Double(tmp2)    // tmp1 =  4*(prev) in P1xP1 coords
@@ -149,9 +475,55 @@ func (v *Point) MultiScalarMult(scalars []*Scalar, points []*Point) *Point {
 		}
 		tmp2.FromP3(v) // set up tmp2 = v in P2 coords for next iteration
 	}
+
+	// Wipe the digits before a heap allocated buffer is released to the GC.
+	for i := range digits {
+		wipeDigits(digits[i][:])
+	}
+	multiple.Zero()
+	runtime.KeepAlive(multiple)
+	ct.resultPoint(v)
 	return v
 }
 
+var (
+	errMultiScalarMultLength        = errors.New("edwards25519: multiscalar multiplication inputs have different sizes")
+	errMultiScalarMultNil           = errors.New("edwards25519: nil Scalar or Point in multiscalar multiplication inputs")
+	errMultiScalarMultUninitialized = errors.New("edwards25519: uninitialized Point in multiscalar multiplication inputs")
+)
+
+// checkMultiScalarMultInputs returns an error if scalars and points are not
+// valid inputs to a multiscalar multiplication.
+func checkMultiScalarMultInputs(scalars []*Scalar, points []*Point) error {
+	if len(scalars) != len(points) {
+		return errMultiScalarMultLength
+	}
+	for i := range scalars {
+		if scalars[i] == nil || points[i] == nil {
+			return errMultiScalarMultNil
+		}
+	}
+	for _, p := range points {
+		if p.x == (fieldElement{}) && p.y == (fieldElement{}) {
+			return errMultiScalarMultUninitialized
+		}
+	}
+	return nil
+}
+
+// MultiScalarMultChecked is like MultiScalarMult, but returns nil and an error
+// instead of panicking if the slices have different lengths, if they contain
+// nil entries, or if any point is uninitialized. The inputs are checked before
+// any work is done, and the receiver is unchanged on error.
+//
+// Execution time depends only on the lengths of the two slices.
+func (v *Point) MultiScalarMultChecked(scalars []*Scalar, points []*Point) (*Point, error) {
+	if err := checkMultiScalarMultInputs(scalars, points); err != nil {
+		return nil, err
+	}
+	return v.MultiScalarMult(scalars, points), nil
+}
+
 // VarTimeDoubleScalarBaseMult sets v = a * A + b * B, where B is the canonical
 // generator, and returns v.
 //
@@ -175,21 +547,27 @@ func (v *Point) VarTimeDoubleScalarBaseMult(a *Scalar, A *Point, b *Scalar) *Poi
 
 	var aTable nafLookupTable5
 	aTable.FromP3(A)
+	return v.varTimeDoubleScalarBaseMult(a, aTable.points[:], 5, b)
+}
+
+// varTimeDoubleScalarBaseMult sets v = a * A + b * B, where aTable holds the
+// width-aWidth NAF lookup table of A, and returns v.
+func (v *Point) varTimeDoubleScalarBaseMult(a *Scalar, aTable []projCached, aWidth uint, b *Scalar) *Point {
 	// Because the basepoint is fixed, we can use a wider NAF
 	// corresponding to a bigger table.
-	aNaf := a.nonAdjacentForm(5)
-	bNaf := b.nonAdjacentForm(8)
+	aNaf := a.nonAdjacentForm(aWidth)
+	bWidth, bTable := basepointNafTableWide()
+	bNaf := b.nonAdjacentFormWide(bWidth)
 
-	// Find the first nonzero coefficient.
+	// Find the first nonzero coefficient, so that short scalars such as
+	// 128-bit batch verification coefficients skip the leading doublings.
 	i := 255
-	for j := i; j >= 0; j-- {
-		if aNaf[j] != 0 || bNaf[j] != 0 {
+	for ; i >= 0; i-- {
+		if aNaf[i] != 0 || bNaf[i] != 0 {
 			break
 		}
 	}
 
-	multA := &projCached{}
-	multB := &affineCached{}
 	tmp1 := &projP1xP1{}
 	tmp2 := &projP2{}
 	tmp2.Zero()
@@ -201,24 +579,79 @@ func (v *Point) VarTimeDoubleScalarBaseMult(a *Scalar, A *Point, b *Scalar) *Poi
 		tmp1.Double(tmp2)
 
 		// Only update v if we have a nonzero coeff to add in.
+		// As in nafLookupTable5.SelectInto, x*A is at index x/2.
+		if aNaf[i] > 0 {
+			v.fromP1xP1(tmp1)
+			tmp1.Add(v, &aTable[aNaf[i]/2])
+		} else if aNaf[i] < 0 {
+			v.fromP1xP1(tmp1)
+			tmp1.Sub(v, &aTable[-aNaf[i]/2])
+		}
+
+		// As in nafLookupTable8.SelectInto, x*B is at index x/2.
+		if bNaf[i] > 0 {
+			v.fromP1xP1(tmp1)
+			tmp1.AddAffine(v, &bTable[bNaf[i]/2])
+		} else if bNaf[i] < 0 {
+			v.fromP1xP1(tmp1)
+			tmp1.SubAffine(v, &bTable[-bNaf[i]/2])
+		}
+
+		tmp2.FromP1xP1(tmp1)
+	}
+
+	v.fromP2(tmp2)
+	return v
+}
+
+// VarTimeDoubleScalarMult sets v = a * A + b * B, and returns v.
+//
+// Execution time depends on the inputs.
+func (v *Point) VarTimeDoubleScalarMult(a *Scalar, A *Point, b *Scalar, B *Point) *Point {
+	checkInitialized(A, B)
+
+	// This is the same as VarTimeDoubleScalarBaseMult, except that B is
+	// not fixed, so both points use a dynamic width-5 table.
+	var aTable, bTable nafLookupTable5
+	aTable.FromP3(A)
+	bTable.FromP3(B)
+	aNaf := a.nonAdjacentForm(5)
+	bNaf := b.nonAdjacentForm(5)
+
+	// Find the first nonzero coefficient.
+	i := 255
+	for ; i >= 0; i-- {
+		if aNaf[i] != 0 || bNaf[i] != 0 {
+			break
+		}
+	}
+
+	multiple := &projCached{}
+	tmp1 := &projP1xP1{}
+	tmp2 := &projP2{}
+	tmp2.Zero()
+
+	for ; i >= 0; i-- {
+		tmp1.Double(tmp2)
+
 		if aNaf[i] > 0 {
 			v.fromP1xP1(tmp1)
-			aTable.SelectInto(multA, aNaf[i])
-			tmp1.Add(v, multA)
+			aTable.SelectInto(multiple, aNaf[i])
+			tmp1.Add(v, multiple)
 		} else if aNaf[i] < 0 {
 			v.fromP1xP1(tmp1)
-			aTable.SelectInto(multA, -aNaf[i])
-			tmp1.Sub(v, multA)
+			aTable.SelectInto(multiple, -aNaf[i])
+			tmp1.Sub(v, multiple)
 		}
 
 		if bNaf[i] > 0 {
 			v.fromP1xP1(tmp1)
-			basepointNafTable.SelectInto(multB, bNaf[i])
-			tmp1.AddAffine(v, multB)
+			bTable.SelectInto(multiple, bNaf[i])
+			tmp1.Add(v, multiple)
 		} else if bNaf[i] < 0 {
 			v.fromP1xP1(tmp1)
-			basepointNafTable.SelectInto(multB, -bNaf[i])
-			tmp1.SubAffine(v, multB)
+			bTable.SelectInto(multiple, -bNaf[i])
+			tmp1.Sub(v, multiple)
 		}
 
 		tmp2.FromP1xP1(tmp1)
@@ -228,7 +661,21 @@ func (v *Point) VarTimeDoubleScalarBaseMult(a *Scalar, A *Point, b *Scalar) *Poi
 	return v
 }
 
+// VarTimeMultiScalarMultChecked is like VarTimeMultiScalarMult, but returns
+// nil and an error instead of panicking if the slices have different lengths,
+// if they contain nil entries, or if any point is uninitialized. The inputs are
+// checked before any work is done, and the receiver is unchanged on error.
+//
+// Execution time depends on the inputs.
+func (v *Point) VarTimeMultiScalarMultChecked(scalars []*Scalar, points []*Point) (*Point, error) {
+	if err := checkMultiScalarMultInputs(scalars, points); err != nil {
+		return nil, err
+	}
+	return v.VarTimeMultiScalarMult(scalars, points), nil
+}
+
 // VarTimeMultiScalarMult sets v = sum(scalars[i] * points[i]), and returns v.
+// Terms with a zero scalar or an identity point are skipped.
 //
 // Execution time depends on the inputs.
 func (v *Point) VarTimeMultiScalarMult(scalars []*Scalar, points []*Point) *Point {
@@ -237,22 +684,195 @@ func (v *Point) VarTimeMultiScalarMult(scalars []*Scalar, points []*Point) *Poin
 	}
 	checkInitialized(points...)
 
+	return v.varTimeMultiScalarMult(&MSMContext{}, scalars, points)
+}
+
+// VarTimeMultiScalarMultDedup is like VarTimeMultiScalarMult, but first
+// coalesces the terms of equal points by adding up their scalars, so that each
+// distinct point is processed only once. This is faster when points repeat
+// often, such as in the batch verification of many signatures by few keys.
+//
+// Execution time depends on the inputs.
+func (v *Point) VarTimeMultiScalarMultDedup(scalars []*Scalar, points []*Point) *Point {
+	if len(scalars) != len(points) {
+		panic("edwards25519: called VarTimeMultiScalarMultDedup with different size inputs")
+	}
+	checkInitialized(points...)
+
+	byPointer := make(map[*Point]int, len(points))
+	byEncoding := make(map[[32]byte]int, len(points))
+	var dedupScalars []*Scalar
+	var dedupPoints []*Point
+	for i, p := range points {
+		j, ok := byPointer[p]
+		if !ok {
+			var enc [32]byte
+			p.bytes(&enc)
+			j, ok = byEncoding[enc]
+			if !ok {
+				j = len(dedupPoints)
+				dedupScalars = append(dedupScalars, (&Scalar{}).Set(scalars[i]))
+				dedupPoints = append(dedupPoints, p)
+				byEncoding[enc] = j
+				byPointer[p] = j
+				continue
+			}
+			byPointer[p] = j
+		}
+		dedupScalars[j].Add(dedupScalars[j], scalars[i])
+	}
+
+	return v.varTimeMultiScalarMult(&MSMContext{}, dedupScalars, dedupPoints)
+}
+
+// VarTimeMultiLinearCombination returns sum(coeffs[j][i] * points[i]) for each
+// row j of coeffs. It builds the lookup table of each point once and reuses it
+// for all rows, so it is faster than calling VarTimeMultiScalarMult for each.
+// Every row must have len(points) coefficients, or it will panic.
+//
+// Execution time depends on the inputs.
+func VarTimeMultiLinearCombination(coeffs [][]*Scalar, points []*Point) []*Point {
+	for _, row := range coeffs {
+		if len(row) != len(points) {
+			panic("edwards25519: called VarTimeMultiLinearCombination with different size inputs")
+		}
+	}
+	checkInitialized(points...)
+
+	c := &MSMContext{}
+	size := nafTableSize(5)
+	tables := make([]projCached, len(points)*size)
+	for i := range points {
+		fillNafTable(tables[i*size:(i+1)*size], points[i])
+	}
+	out := make([]Point, len(coeffs))
+	results := make([]*Point, len(coeffs))
+	for j := range coeffs {
+		results[j] = out[j].varTimeStrausWithTables(c, coeffs[j], tables, 5)
+	}
+	return results
+}
+
+// VarTimeCheckLinearCombination returns whether sum(scalars[i] * points[i]) is
+// the identity. The check is exact, not cofactored: a combination that is a
+// nonzero low order point returns false. For a cofactored check, multiply all
+// scalars by 8. An empty combination returns true.
+//
+// Execution time depends on the inputs.
+func VarTimeCheckLinearCombination(scalars []*Scalar, points []*Point) bool {
+	if len(scalars) != len(points) {
+		panic("edwards25519: called VarTimeCheckLinearCombination with different size inputs")
+	}
+	checkInitialized(points...)
+
+	// The identity check needs no inversion, unlike comparing encodings.
+	var v Point
+	return v.varTimeMultiScalarMult(&MSMContext{}, scalars, points).varTimeIsIdentity()
+}
+
+// VarTimeCheckLinearCombinationEqual returns whether sum(scalars[i] *
+// points[i]) is equal to expected, like VarTimeCheckLinearCombination with
+// an extra term of expected with coefficient -1.
+//
+// Execution time depends on the inputs.
+func VarTimeCheckLinearCombinationEqual(scalars []*Scalar, points []*Point, expected *Point) bool {
+	if len(scalars) != len(points) {
+		panic("edwards25519: called VarTimeCheckLinearCombinationEqual with different size inputs")
+	}
+	allScalars := make([]*Scalar, 0, len(scalars)+1)
+	allScalars = append(allScalars, scalars...)
+	allScalars = append(allScalars, &scMinusOne)
+	allPoints := make([]*Point, 0, len(points)+1)
+	allPoints = append(allPoints, points...)
+	allPoints = append(allPoints, expected)
+	return VarTimeCheckLinearCombination(allScalars, allPoints)
+}
+
+// varTimeMultiScalarMult sets v = sum(scalars[i] * points[i]) using the
+// scratch buffers in c, and returns v.
+func (v *Point) varTimeMultiScalarMult(c *MSMContext, scalars []*Scalar, points []*Point) *Point {
+	if c.maxScalarBits > 0 {
+		for i := range scalars {
+			if scalars[i].fitsInBits(c.maxScalarBits) != 1 {
+				panic("edwards25519: scalar exceeds the MSMContext bit bound")
+			}
+		}
+	}
+	// Count the terms that contribute first, so that both the choice of
+	// method and the Pippenger window follow the number of nonzero terms.
+	n := countNonZeroTerms(scalars, points)
+	if n == 0 {
+		return v.Set(NewIdentityPoint())
+	}
+	if n > pippengerThreshold {
+		// The bucket method skips zero digits by itself, and processes the
+		// inputs in chunks to bound its memory use, so don't copy them.
+		return v.varTimePippenger(c, scalars, points, n)
+	}
+	scalars, points = c.nonZeroTerms(scalars, points)
+	defer c.clearTerms()
+	w := c.nafWidth
+	if w == 0 {
+		w = strausNafWidth
+	}
+	return v.varTimeStraus(c, scalars, points, w)
+}
+
+// strausNafWidth is the NAF width for Straus multiscalar multiplications, as
+// measured with BenchmarkVarTimeStrausWidths.
+//
+// A wider NAF saves additions in the main loop, but each point's table doubles
+// in size with each extra bit. The doublings are shared by all points and are
+// the same at every width, so they don't shift the balance, and no width beats
+// 5 consistently at any size the Straus method is used for.
+const strausNafWidth = 5
+
+// varTimeStraus sets v = sum(scalars[i] * points[i]) using the Straus method
+// with width-w NAFs, and returns v.
+func (v *Point) varTimeStraus(c *MSMContext, scalars []*Scalar, points []*Point, w uint) *Point {
 	// Generalize double-base NAF computation to arbitrary sizes.
-	// Here all the points are dynamic, so we only use the smaller
-	// tables.
+	// Here all the points are dynamic, so we build tables for each.
 
 	// Build lookup tables for each point
-	tables := make([]nafLookupTable5, len(points))
-	for i := range tables {
-		tables[i].FromP3(points[i])
+	size := nafTableSize(w)
+	tables := c.nafTables(len(points) * size)
+	for i := range points {
+		fillNafTable(tables[i*size:(i+1)*size], points[i])
 	}
+	return v.varTimeStrausWithTables(c, scalars, tables, w)
+}
+
+// varTimeStrausWithTables sets v = sum(scalars[i] * Q_i), where tables holds
+// the width-w NAF lookup tables of each Q_i in sequence, and returns v.
+func (v *Point) varTimeStrausWithTables(c *MSMContext, scalars []*Scalar, tables []projCached, w uint) *Point {
 	// Compute a NAF for each scalar
-	nafs := make([][256]int8, len(scalars))
+	nafs := c.nafDigits(len(scalars))
 	for i := range nafs {
-		nafs[i] = scalars[i].nonAdjacentForm(5)
+		nafs[i] = scalars[i].nonAdjacentForm(w)
+	}
+	size := nafTableSize(w)
+
+	// Find the first nonzero coefficient, unless c bounds the scalars. The
+	// search is cheap compared to the doublings it can save, for example
+	// for 128-bit batch verification coefficients.
+	i := 255
+	if c.maxScalarBits > 0 {
+		// The NAF of a b-bit scalar can have a nonzero digit at position b.
+		if c.maxScalarBits < i {
+			i = c.maxScalarBits
+		}
+	} else {
+		for ; i >= 0; i-- {
+			nonZero := false
+			for j := range nafs {
+				nonZero = nonZero || nafs[j][i] != 0
+			}
+			if nonZero {
+				break
+			}
+		}
 	}
 
-	multiple := &projCached{}
 	tmp1 := &projP1xP1{}
 	tmp2 := &projP2{}
 	tmp2.Zero()
@@ -260,21 +880,17 @@ func (v *Point) VarTimeMultiScalarMult(scalars []*Scalar, points []*Point) *Poin
 	// Move from high to low bits, doubling the accumulator
 	// at each iteration and checking whether there is a nonzero
 	// coefficient to look up a multiple of.
-	//
-	// Skip trying to find the first nonzero coefficent, because
-	// searching might be more work than a few extra doublings.
-	for i := 255; i >= 0; i-- {
+	for ; i >= 0; i-- {
 		tmp1.Double(tmp2)
 
 		for j := range nafs {
+			// As in nafLookupTable5.SelectInto, x*Q is at index x/2.
 			if nafs[j][i] > 0 {
 				v.fromP1xP1(tmp1)
-				tables[j].SelectInto(multiple, nafs[j][i])
-				tmp1.Add(v, multiple)
+				tmp1.Add(v, &tables[j*size+int(nafs[j][i]/2)])
 			} else if nafs[j][i] < 0 {
 				v.fromP1xP1(tmp1)
-				tables[j].SelectInto(multiple, -nafs[j][i])
-				tmp1.Sub(v, multiple)
+				tmp1.Sub(v, &tables[j*size+int(-nafs[j][i]/2)])
 			}
 		}
 
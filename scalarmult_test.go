@@ -5,6 +5,19 @@
 package edwards25519
 
 import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math/big"
+	mathrand "math/rand"
+	"os"
+	"runtime"
+	"strings"
 	"testing"
 	"testing/quick"
 )
@@ -226,6 +239,815 @@ func TestVartimeMultiScalarMulMatchesBasepointMul(t *testing.T) {
 	}
 }
 
+func TestVarTimeDoubleScalarMultMatchesMultiScalarMult(t *testing.T) {
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+	vartimeDoubleScalarMultMatches := func(x, y Scalar) bool {
+		var p, check Point
+		p.VarTimeDoubleScalarMult(&x, A, &y, B)
+		check.VarTimeMultiScalarMult([]*Scalar{&x, &y}, []*Point{A, B})
+		checkOnCurve(t, &p, &check)
+		if p.Equal(&check) != 1 {
+			return false
+		}
+
+		// A and B may alias, and v may alias either.
+		p.Set(A)
+		p.VarTimeDoubleScalarMult(&x, &p, &y, &p)
+		check.VarTimeMultiScalarMult([]*Scalar{&x, &y}, []*Point{A, A})
+		checkOnCurve(t, &p, &check)
+		return p.Equal(&check) == 1
+	}
+
+	if err := quick.Check(vartimeDoubleScalarMultMatches, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMultiScalarMultChecked(t *testing.T) {
+	x, y := dalekScalar, scOne
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+
+	tests := []struct {
+		name    string
+		scalars []*Scalar
+		points  []*Point
+		err     error
+	}{
+		{"length mismatch", []*Scalar{&x, &y}, []*Point{A}, errMultiScalarMultLength},
+		{"nil scalar", []*Scalar{&x, nil}, []*Point{A, B}, errMultiScalarMultNil},
+		{"nil point", []*Scalar{&x, &y}, []*Point{nil, B}, errMultiScalarMultNil},
+		{"uninitialized point", []*Scalar{&x, &y}, []*Point{A, &Point{}}, errMultiScalarMultUninitialized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewGeneratorPoint()
+			if out, err := p.MultiScalarMultChecked(tt.scalars, tt.points); err != tt.err || out != nil {
+				t.Errorf("MultiScalarMultChecked: got %v, %v, expected nil, %v", out, err, tt.err)
+			}
+			if out, err := p.VarTimeMultiScalarMultChecked(tt.scalars, tt.points); err != tt.err || out != nil {
+				t.Errorf("VarTimeMultiScalarMultChecked: got %v, %v, expected nil, %v", out, err, tt.err)
+			}
+			if p.Equal(B) != 1 {
+				t.Error("the receiver was modified on error")
+			}
+		})
+	}
+
+	scalars, points := []*Scalar{&x, &y}, []*Point{A, B}
+	want := NewIdentityPoint().MultiScalarMult(scalars, points).Bytes()
+	if p, err := NewIdentityPoint().MultiScalarMultChecked(scalars, points); err != nil {
+		t.Errorf("MultiScalarMultChecked: unexpected error %v", err)
+	} else if !bytes.Equal(p.Bytes(), want) {
+		t.Error("MultiScalarMultChecked does not match MultiScalarMult")
+	}
+	want = NewIdentityPoint().VarTimeMultiScalarMult(scalars, points).Bytes()
+	if p, err := NewIdentityPoint().VarTimeMultiScalarMultChecked(scalars, points); err != nil {
+		t.Errorf("VarTimeMultiScalarMultChecked: unexpected error %v", err)
+	} else if !bytes.Equal(p.Bytes(), want) {
+		t.Error("VarTimeMultiScalarMultChecked does not match VarTimeMultiScalarMult")
+	}
+}
+
+func TestMultiScalarMultSmallSizes(t *testing.T) {
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+
+	for _, receiver := range []*Point{{}, NewGeneratorPoint()} {
+		if p := (&Point{}).Set(receiver).MultiScalarMult(nil, nil); p.Equal(I) != 1 {
+			t.Error("MultiScalarMult with empty inputs is not the identity")
+		}
+		if p := (&Point{}).Set(receiver).VarTimeMultiScalarMult(nil, nil); p.Equal(I) != 1 {
+			t.Error("VarTimeMultiScalarMult with empty inputs is not the identity")
+		}
+
+		p := (&Point{}).Set(receiver).MultiScalarMult([]*Scalar{&dalekScalar}, []*Point{B})
+		if p.Equal(A) != 1 {
+			t.Error("MultiScalarMult with one input does not match ScalarBaseMult")
+		}
+		p = (&Point{}).Set(receiver).VarTimeMultiScalarMult([]*Scalar{&dalekScalar}, []*Point{B})
+		if p.Equal(A) != 1 {
+			t.Error("VarTimeMultiScalarMult with one input does not match ScalarBaseMult")
+		}
+	}
+
+	// The receiver may alias one of the input points.
+	x, y := dalekScalar, scMinusOne
+	check := (&Point{}).Subtract(A, B)
+	p := NewGeneratorPoint()
+	if p.MultiScalarMult([]*Scalar{&x, &y}, []*Point{B, p}); p.Equal(check) != 1 {
+		t.Error("MultiScalarMult with aliased receiver is wrong")
+	}
+	p = NewGeneratorPoint()
+	if p.VarTimeMultiScalarMult([]*Scalar{&x, &y}, []*Point{B, p}); p.Equal(check) != 1 {
+		t.Error("VarTimeMultiScalarMult with aliased receiver is wrong")
+	}
+}
+
+func TestScalarMultAllocations(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	// Around msmStackSize, MultiScalarMult switches from stack to heap buffers.
+	for _, n := range []int{msmStackSize - 1, msmStackSize, msmStackSize + 1} {
+		scalars, points := randomMultiScalarMultInputs(rand, n)
+		check := (&Point{}).VarTimeMultiScalarMult(scalars, points)
+		if p := (&Point{}).MultiScalarMult(scalars, points); p.Equal(check) != 1 {
+			t.Errorf("n = %d: MultiScalarMult does not match VarTimeMultiScalarMult", n)
+		}
+	}
+
+	if ctcheckEnabled {
+		t.Skip("the ctcheck hooks make constant-time functions allocate")
+	}
+	scalars, points := randomMultiScalarMultInputs(rand, msmStackSize)
+	var p Point
+	for _, tt := range []struct {
+		name string
+		f    func()
+	}{
+		{"ScalarMult", func() { p.ScalarMult(&dalekScalar, B) }},
+		{"ScalarBaseMult", func() { p.ScalarBaseMult(&dalekScalar) }},
+		{"VarTimeDoubleScalarBaseMult", func() { p.VarTimeDoubleScalarBaseMult(&dalekScalar, B, &dalekScalar) }},
+		{"MultiScalarMult", func() { p.MultiScalarMult(scalars, points) }},
+		{"MultiScalarMultBounded", func() { p.MultiScalarMultBounded(scalars, points, 256) }},
+	} {
+		if smallTables && tt.name == "VarTimeDoubleScalarBaseMult" {
+			// The small table of B is built on the heap at every call.
+			continue
+		}
+		if allocs := testing.AllocsPerRun(10, tt.f); allocs != 0 {
+			t.Errorf("%s: expected zero allocations, got %v", tt.name, allocs)
+		}
+	}
+}
+
+func TestVarTimeStrausWidths(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{1, 2, 7, 32} {
+		scalars, points := randomMultiScalarMultInputs(rand, n)
+		var check Point
+		check.MultiScalarMult(scalars, points)
+		for w := 2; w <= 8; w++ {
+			var p Point
+			p.VarTimeMultiScalarMultWithContext((&MSMContext{}).SetNAFWidth(w), scalars, points)
+			checkOnCurve(t, &p)
+			if p.Equal(&check) != 1 {
+				t.Errorf("n = %d, w = %d: result does not match MultiScalarMult", n, w)
+			}
+		}
+	}
+}
+
+func TestSetNAFWidthInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("SetNAFWidth(9) did not panic")
+		}
+	}()
+	(&MSMContext{}).SetNAFWidth(9)
+}
+
+// varTimeDoubleScalarBaseMultWide is like VarTimeDoubleScalarBaseMult, but uses
+// a width-8 NAF and table for A. Building the table costs more than it saves
+// in a one-shot multiplication, see BenchmarkVarTimeDoubleScalarBaseMultWidths.
+func (v *Point) varTimeDoubleScalarBaseMultWide(a *Scalar, A *Point, b *Scalar) *Point {
+	checkInitialized(A)
+
+	var aTable projNafLookupTable8
+	aTable.FromP3(A)
+	return v.varTimeDoubleScalarBaseMult(a, aTable.points[:], 8, b)
+}
+
+func TestVarTimeDoubleScalarBaseMultWide(t *testing.T) {
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+	wideMatches := func(x, y Scalar) bool {
+		var p, check Point
+		p.varTimeDoubleScalarBaseMultWide(&x, A, &y)
+		check.VarTimeDoubleScalarBaseMult(&x, A, &y)
+		checkOnCurve(t, &p)
+		return p.Equal(&check) == 1
+	}
+
+	if err := quick.Check(wideMatches, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScalarBaseMultAdd(t *testing.T) {
+	P := (&Point{}).ScalarBaseMult(&dalekScalar)
+	scalarBaseMultAddMatches := func(x Scalar) bool {
+		var p, check Point
+		p.ScalarBaseMultAdd(&x, P)
+		check.ScalarBaseMult(&x)
+		check.Add(&check, P)
+		checkOnCurve(t, &p)
+		if p.Equal(&check) != 1 {
+			return false
+		}
+
+		// p may alias v.
+		p.Set(P)
+		p.ScalarBaseMultAdd(&x, &p)
+		return p.Equal(&check) == 1
+	}
+
+	if err := quick.Check(scalarBaseMultAddMatches, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScalarBaseMultKeyGeneration(t *testing.T) {
+	// Test vectors from RFC 8032, Section 7.1.
+	vectors := []struct{ secret, public string }{
+		{
+			"9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+			"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		},
+		{
+			"4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+			"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		},
+		{
+			"c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+			"fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+		},
+	}
+	for _, v := range vectors {
+		h := sha512.Sum512(decodeHex(v.secret))
+		s := NewScalar().SetBytesWithClamping(h[:32])
+		p := (&Point{}).ScalarBaseMult(s)
+		if got := hex.EncodeToString(p.Bytes()); got != v.public {
+			t.Errorf("got %s, expected %s", got, v.public)
+		}
+	}
+
+	keyGenerationMatches := func(seed [32]byte) bool {
+		h := sha512.Sum512(seed[:])
+		s := NewScalar().SetBytesWithClamping(h[:32])
+		p := (&Point{}).ScalarBaseMult(s)
+		priv := ed25519.NewKeyFromSeed(seed[:])
+		return bytes.Equal(p.Bytes(), priv.Public().(ed25519.PublicKey))
+	}
+	if err := quick.Check(keyGenerationMatches, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVarTimeMultiScalarMultZeroTerms(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{1, 16, 300} {
+		scalars, points := randomMultiScalarMultInputs(rand, n)
+		zeros := make([]*Scalar, n)
+		identities := make([]*Point, n)
+		for i := range zeros {
+			zeros[i] = NewScalar()
+			identities[i] = NewIdentityPoint()
+		}
+
+		p := (&Point{}).Set(B)
+		if p.VarTimeMultiScalarMult(zeros, points).Equal(I) != 1 {
+			t.Errorf("n = %d: all zero scalars did not produce the identity", n)
+		}
+		p.Set(B)
+		if p.VarTimeMultiScalarMult(scalars, identities).Equal(I) != 1 {
+			t.Errorf("n = %d: all identity points did not produce the identity", n)
+		}
+
+		// Zero out most scalars and replace some points with the identity.
+		for i := range scalars {
+			switch i % 10 {
+			case 0:
+			case 1:
+				points[i] = identities[i]
+			default:
+				scalars[i] = zeros[i]
+			}
+		}
+		var check Point
+		check.MultiScalarMult(scalars, points)
+		p.VarTimeMultiScalarMult(scalars, points)
+		if p.Equal(&check) != 1 {
+			t.Errorf("n = %d: sparse inputs do not match MultiScalarMult", n)
+		}
+	}
+}
+
+func TestVarTimeMultiScalarMultDedup(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, distinct := randomMultiScalarMultInputs(rand, 10)
+	for _, n := range []int{0, 1, 10, 300} {
+		scalars, _ := randomMultiScalarMultInputs(rand, n)
+		points := make([]*Point, n)
+		for i := range points {
+			// Mix repeated pointers with equal but distinct points.
+			points[i] = distinct[rand.Intn(len(distinct))]
+			if i%3 == 0 {
+				points[i] = (&Point{}).Add(points[i], I)
+			}
+		}
+		var p, check Point
+		p.VarTimeMultiScalarMultDedup(scalars, points)
+		check.VarTimeMultiScalarMult(scalars, points)
+		if p.Equal(&check) != 1 {
+			t.Errorf("n = %d: result does not match VarTimeMultiScalarMult", n)
+		}
+	}
+
+	// Scalars that cancel out must produce the identity.
+	var minusOne Scalar
+	minusOne.Negate(&scOne)
+	p := (&Point{}).VarTimeMultiScalarMultDedup([]*Scalar{&scOne, &scMinusOne},
+		[]*Point{B, NewGeneratorPoint()})
+	if p.Equal(I) != 1 {
+		t.Error("cancelling terms did not produce the identity")
+	}
+}
+
+func TestMultiScalarMultBounded(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, points := randomMultiScalarMultInputs(rand, 8)
+	for _, bits := range []int{1, 3, 4, 8, 63, 64, 128, 252, 253, 256} {
+		scalars := make([]*Scalar, len(points))
+		for i := range scalars {
+			var b [32]byte
+			rand.Read(b[:])
+			for j := range b {
+				switch {
+				case 8*j >= bits:
+					b[j] = 0
+				case 8*j+8 > bits:
+					b[j] &= 1<<uint(bits-8*j) - 1
+				}
+			}
+			if i == 0 && bits < 253 {
+				// The largest scalar in the bound.
+				for j := 0; j < bits; j++ {
+					b[j/8] |= 1 << uint(j%8)
+				}
+			}
+			b[31] &= 0x0f
+			scalars[i] = NewScalar()
+			if _, err := scalars[i].SetCanonicalBytes(b[:]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var p, check Point
+		p.MultiScalarMultBounded(scalars, points, bits)
+		check.MultiScalarMult(scalars, points)
+		checkOnCurve(t, &p)
+		if p.Equal(&check) != 1 {
+			t.Errorf("bits = %d: result does not match MultiScalarMult", bits)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MultiScalarMultBounded did not panic with a scalar out of bound")
+		}
+	}()
+	scalars := []*Scalar{scalarFromInt16(255), scalarFromInt16(256)}
+	(&Point{}).MultiScalarMultBounded(scalars, points[:2], 8)
+}
+
+func TestScalarFitsInBits(t *testing.T) {
+	s := scalarFromInt16(0x1234)
+	for n := 1; n <= 256; n++ {
+		want := 0
+		if n >= 13 {
+			want = 1
+		}
+		if got := s.fitsInBits(n); got != want {
+			t.Errorf("fitsInBits(%d) = %d, expected %d", n, got, want)
+		}
+	}
+	if scMinusOne.fitsInBits(252) != 0 || scMinusOne.fitsInBits(253) != 1 {
+		t.Error("fitsInBits is wrong for -1")
+	}
+}
+
+func TestVarTimeDoubleScalarBaseMultShortScalars(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+	for i := 0; i < 16; i++ {
+		s := random128BitScalars(rand, 2)
+		a, b := s[0], s[1]
+		if i == 0 {
+			a = NewScalar()
+		}
+		var p, check Point
+		p.VarTimeDoubleScalarBaseMult(a, A, b)
+		check.MultiScalarMult([]*Scalar{a, b}, []*Point{A, B})
+		if p.Equal(&check) != 1 {
+			t.Error("result does not match MultiScalarMult")
+		}
+	}
+	if (&Point{}).VarTimeDoubleScalarBaseMult(NewScalar(), A, NewScalar()).Equal(I) != 1 {
+		t.Error("zero scalars did not produce the identity")
+	}
+}
+
+func TestVarTimeMultiLinearCombination(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, points := randomMultiScalarMultInputs(rand, 12)
+	for _, m := range []int{0, 1, 5} {
+		coeffs := make([][]*Scalar, m)
+		for j := range coeffs {
+			coeffs[j], _ = randomMultiScalarMultInputs(rand, len(points))
+		}
+		results := VarTimeMultiLinearCombination(coeffs, points)
+		if len(results) != m {
+			t.Fatalf("got %d results, expected %d", len(results), m)
+		}
+		for j := range coeffs {
+			var check Point
+			check.VarTimeMultiScalarMult(coeffs[j], points)
+			if results[j].Equal(&check) != 1 {
+				t.Errorf("m = %d: row %d does not match VarTimeMultiScalarMult", m, j)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("VarTimeMultiLinearCombination did not panic with a short row")
+		}
+	}()
+	coeffs, _ := randomMultiScalarMultInputs(rand, len(points))
+	VarTimeMultiLinearCombination([][]*Scalar{coeffs, coeffs[1:]}, points)
+}
+
+// scalarMultRadix32 is like ScalarMult, but with radix-32 digits and a
+// 16-entry table. It trades 8 more additions to build the table and twice as
+// many constant-time selections for 12 fewer additions in the main loop.
+//
+// It is kept for BenchmarkScalarMultRadix: on amd64 it is a few percent
+// slower than ScalarMult, as the selections cost more than they save.
+func (v *Point) scalarMultRadix32(x *Scalar, q *Point) *Point {
+	checkInitialized(q)
+
+	var table projLookupTable16
+	table.FromP3(q)
+
+	// The top digit only absorbs the final carry, as x < 2^255.
+	var digits [52]int8
+	x.signedRadix2w(5, digits[:])
+	ct := newCTState()
+	ct.poisonDigits(digits[:])
+	ct.checkPoint(q)
+
+	multiple := &projCached{}
+	tmp1 := &projP1xP1{}
+	tmp2 := &projP2{}
+	table.SelectInto(multiple, digits[51])
+
+	v.Set(NewIdentityPoint())
+	tmp1.Add(v, multiple) // tmp1 = x_51*Q in P1xP1 coords
+	for i := 50; i >= 0; i-- {
+		tmp2.FromP1xP1(tmp1) // tmp2 =    (prev) in P2 coords
+		tmp1.Double(tmp2)    // tmp1 =  2*(prev) in P1xP1 coords
+		tmp2.FromP1xP1(tmp1) // tmp2 =  2*(prev) in P2 coords
+		tmp1.Double(tmp2)    // tmp1 =  4*(prev) in P1xP1 coords
+		tmp2.FromP1xP1(tmp1) // tmp2 =  4*(prev) in P2 coords
+		tmp1.Double(tmp2)    // tmp1 =  8*(prev) in P1xP1 coords
+		tmp2.FromP1xP1(tmp1) // tmp2 =  8*(prev) in P2 coords
+		tmp1.Double(tmp2)    // tmp1 = 16*(prev) in P1xP1 coords
+		tmp2.FromP1xP1(tmp1) // tmp2 = 16*(prev) in P2 coords
+		tmp1.Double(tmp2)    // tmp1 = 32*(prev) in P1xP1 coords
+		v.fromP1xP1(tmp1)    //    v = 32*(prev) in P3 coords
+		table.SelectInto(multiple, digits[i])
+		tmp1.Add(v, multiple) // tmp1 = x_i*Q + 32*(prev) in P1xP1 coords
+	}
+	v.fromP1xP1(tmp1)
+	wipeDigits(digits[:])
+	multiple.Zero()
+	runtime.KeepAlive(multiple)
+	ct.resultPoint(v)
+	return v
+}
+
+func TestScalarMultRadix32(t *testing.T) {
+	// Scalars whose radix-32 digits are all 15, and all -16 after
+	// recentering, which exercise the largest table entries.
+	maxDigits, minDigits := new(big.Int), new(big.Int)
+	for i := 49; i >= 0; i-- {
+		maxDigits.Lsh(maxDigits, 5).Add(maxDigits, big.NewInt(15))
+		minDigits.Lsh(minDigits, 5).Add(minDigits, big.NewInt(16))
+	}
+	edge := []Scalar{scZero, scOne, scMinusOne}
+	for _, x := range []*big.Int{maxDigits, minDigits} {
+		var s Scalar
+		b := x.Bytes()
+		for i := range b {
+			s.s[i] = b[len(b)-1-i]
+		}
+		edge = append(edge, s)
+	}
+	for _, x := range edge {
+		var p, check Point
+		p.scalarMultRadix32(&x, B)
+		check.ScalarMult(&x, B)
+		if p.Equal(&check) != 1 {
+			t.Errorf("radix-32 result does not match ScalarMult for %x", x.s)
+		}
+	}
+
+	matches := func(x Scalar) bool {
+		var p, check Point
+		p.scalarMultRadix32(&x, &dalekScalarBasepoint)
+		check.ScalarMult(&x, &dalekScalarBasepoint)
+		return p.Equal(&check) == 1
+	}
+	if err := quick.Check(matches, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVarTimeCheckLinearCombination(t *testing.T) {
+	if !VarTimeCheckLinearCombination(nil, nil) {
+		t.Error("empty combination is not the identity")
+	}
+
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{1, 8, 300} {
+		scalars, points := randomMultiScalarMultInputs(rand, n)
+		sum := (&Point{}).MultiScalarMult(scalars, points)
+		if !VarTimeCheckLinearCombinationEqual(scalars, points, sum) {
+			t.Errorf("n = %d: combination does not equal its sum", n)
+		}
+		if VarTimeCheckLinearCombinationEqual(scalars, points, (&Point{}).Add(sum, B)) {
+			t.Errorf("n = %d: combination equals a different point", n)
+		}
+
+		if !VarTimeCheckLinearCombination(append(scalars, &scMinusOne), append(points, sum)) {
+			t.Errorf("n = %d: combination minus its sum is not the identity", n)
+		}
+	}
+
+	// The check is not cofactored: a low order remainder makes it fail,
+	// unless the scalars are multiplied by the cofactor.
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var eight, minusEight Scalar
+	eight.s[0] = 8
+	minusEight.Negate(&eight)
+	P := (&Point{}).Add(B, lowOrder)
+	if VarTimeCheckLinearCombination([]*Scalar{&scOne, &scMinusOne}, []*Point{P, B}) {
+		t.Error("combination with a low order remainder is the identity")
+	}
+	if !VarTimeCheckLinearCombination([]*Scalar{&eight, &minusEight}, []*Point{P, B}) {
+		t.Error("cofactored combination with a low order remainder is not the identity")
+	}
+}
+
+func TestScalarMultChecked(t *testing.T) {
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := dalekScalar
+	small := NewIdentityPoint()
+	for i := 0; i < 8; i++ {
+		p := (&Point{}).Set(B)
+		if _, err := p.ScalarMultChecked(&x, small); err == nil {
+			t.Errorf("%d times a point of order 8 was accepted", i)
+		}
+		if p.Equal(B) != 1 {
+			t.Errorf("receiver was modified on error")
+		}
+		small.Add(small, lowOrder)
+	}
+
+	// x = 0 produces the identity, and is rejected.
+	if _, err := (&Point{}).ScalarMultChecked(NewScalar(), B); err == nil {
+		t.Error("x = 0 was accepted")
+	}
+
+	// A point with a small order component is accepted.
+	mixed := (&Point{}).Add(B, lowOrder)
+	p, err := (&Point{}).ScalarMultChecked(&x, mixed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Equal((&Point{}).ScalarMult(&x, mixed)) != 1 {
+		t.Error("ScalarMultChecked does not match ScalarMult")
+	}
+
+	// Both parties of a Diffie-Hellman exchange agree.
+	dhMatches := func(a, b Scalar) bool {
+		if a.Equal(NewScalar()) == 1 || b.Equal(NewScalar()) == 1 {
+			return true
+		}
+		A := (&Point{}).ScalarBaseMult(&a)
+		B := (&Point{}).ScalarBaseMult(&b)
+		s1, err1 := (&Point{}).ScalarMultChecked(&a, B)
+		s2, err2 := (&Point{}).ScalarMultChecked(&b, A)
+		return err1 == nil && err2 == nil && s1.Equal(s2) == 1
+	}
+	if err := quick.Check(dhMatches, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBatchScalarMult(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{0, 1, 5} {
+		_, points := randomMultiScalarMultInputs(rand, n)
+		x := Scalar{}.Generate(rand, 0).Interface().(Scalar)
+		results := BatchScalarMult(&x, points)
+		if len(results) != n {
+			t.Fatalf("got %d results, expected %d", len(results), n)
+		}
+		for i := range points {
+			var check Point
+			check.ScalarMult(&x, points[i])
+			checkOnCurve(t, results[i])
+			if results[i].Equal(&check) != 1 {
+				t.Errorf("n = %d: result %d does not match ScalarMult", n, i)
+			}
+		}
+	}
+}
+
+func TestScalarMultBlinded(t *testing.T) {
+	f := func(x, y Scalar) bool {
+		q := (&Point{}).ScalarBaseMult(&y)
+		p1, err1 := (&Point{}).ScalarMultBlinded(nil, &x, q)
+		p2, err2 := (&Point{}).ScalarMultBlinded(nil, &x, q)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		checkOnCurve(t, p1, p2)
+		// The results are the same point, in different projective coordinates.
+		return p1.Equal((&Point{}).ScalarMult(&x, q)) == 1 &&
+			p1.Equal(p2) == 1 && p1.z.Equal(&p2.z) == 0
+	}
+	if err := quick.Check(f, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+
+	g := func(x Scalar) bool {
+		p1, err1 := (&Point{}).ScalarBaseMultBlinded(nil, &x)
+		p2, err2 := (&Point{}).ScalarBaseMultBlinded(nil, &x)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		checkOnCurve(t, p1, p2)
+		return p1.Equal((&Point{}).ScalarBaseMult(&x)) == 1 &&
+			p1.Equal(p2) == 1 && p1.z.Equal(&p2.z) == 0
+	}
+	if err := quick.Check(g, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+
+	// The same randomness gives the same coordinates.
+	x := dalekScalar
+	seed := bytes.Repeat([]byte{0x42}, 32)
+	p1, _ := (&Point{}).ScalarMultBlinded(bytes.NewReader(seed), &x, B)
+	p2, _ := (&Point{}).ScalarMultBlinded(bytes.NewReader(seed), &x, B)
+	if p1.x != p2.x || p1.y != p2.y || p1.z != p2.z || p1.t != p2.t {
+		t.Error("same randomness gave different coordinates")
+	}
+	if p1.Equal(&dalekScalarBasepoint) != 1 {
+		t.Error("ScalarMultBlinded does not match the dalek vector")
+	}
+
+	// A failing reader fails closed.
+	p := (&Point{}).Set(B)
+	if _, err := p.ScalarMultBlinded(errorReader{}, &x, B); err == nil {
+		t.Error("ScalarMultBlinded succeeded with a failing reader")
+	}
+	if _, err := p.ScalarBaseMultBlinded(errorReader{}, &x); err == nil {
+		t.Error("ScalarBaseMultBlinded succeeded with a failing reader")
+	}
+	// So does a stuck one, instead of retrying forever.
+	for _, b := range []byte{0x00, 0xff} {
+		if _, err := p.ScalarMultBlinded(constantReader(b), &x, B); err != errRandomRetries {
+			t.Errorf("ScalarMultBlinded with constant %#x reader: got %v, expected %v", b, err, errRandomRetries)
+		}
+		if _, err := p.ScalarBaseMultBlinded(constantReader(b), &x); err != errRandomRetries {
+			t.Errorf("ScalarBaseMultBlinded with constant %#x reader: got %v, expected %v", b, err, errRandomRetries)
+		}
+	}
+	if p.Equal(B) != 1 {
+		t.Error("receiver was modified on error")
+	}
+}
+
+func TestScalarMultSplit(t *testing.T) {
+	f := func(x, y Scalar) bool {
+		q := (&Point{}).ScalarBaseMult(&y)
+		p, err := (&Point{}).ScalarMultSplit(nil, &x, q)
+		if err != nil {
+			return false
+		}
+		checkOnCurve(t, p)
+		return p.Equal((&Point{}).ScalarMult(&x, q)) == 1
+	}
+	if err := quick.Check(f, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+
+	x := dalekScalar
+	a1, a2, err := splitScalar(nil, &x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b1, _, err := splitScalar(nil, &x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a1.Equal(b1) == 1 {
+		t.Error("x1 is the same across calls")
+	}
+	if NewScalar().Add(a1, a2).Equal(&x) != 1 {
+		t.Error("x1 + x2 != x")
+	}
+
+	// A failing reader fails closed.
+	p := (&Point{}).Set(B)
+	if _, err := p.ScalarMultSplit(errorReader{}, &x, B); err == nil {
+		t.Error("ScalarMultSplit succeeded with a failing reader")
+	}
+	if p.Equal(B) != 1 {
+		t.Error("receiver was modified on error")
+	}
+}
+
+func TestWipeDigits(t *testing.T) {
+	x := dalekScalar
+	digits := x.signedRadix16()
+	wipeDigits(digits[:])
+	if digits != [64]int8{} {
+		t.Errorf("digits were not wiped: %v", digits)
+	}
+}
+
+// TestConstantTimeFunctionsWipeDigits checks that every constant-time function
+// that computes the digits of a scalar also wipes them.
+func TestConstantTimeFunctionsWipeDigits(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := func(fn *ast.FuncDecl, names ...string) bool {
+		found := false
+		ast.Inspect(fn, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			var name string
+			switch f := call.Fun.(type) {
+			case *ast.Ident:
+				name = f.Name
+			case *ast.SelectorExpr:
+				name = f.Sel.Name
+			}
+			for _, n := range names {
+				found = found || name == n
+			}
+			return true
+		})
+		return found
+	}
+	// Functions that return the digits, like SignedRadix2w, leave wiping them
+	// to their caller.
+	returnsDigits := func(fn *ast.FuncDecl) bool {
+		if fn.Type.Results == nil {
+			return false
+		}
+		for _, r := range fn.Type.Results.List {
+			if a, ok := r.Type.(*ast.ArrayType); ok {
+				if elt, ok := a.Elt.(*ast.Ident); ok && elt.Name == "int8" {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	checked := 0
+	for _, f := range pkgs["edwards25519"].Files {
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || strings.Contains(strings.ToLower(fn.Name.Name), "vartime") {
+				continue
+			}
+			if !calls(fn, "signedRadix16", "signedRadix2w") || returnsDigits(fn) {
+				continue
+			}
+			checked++
+			if !calls(fn, "wipeDigits") {
+				t.Errorf("%s computes scalar digits but does not wipe them", fn.Name.Name)
+			}
+		}
+	}
+	if checked == 0 {
+		t.Error("no functions were checked")
+	}
+}
+
 // Benchmarks.
 
 func BenchmarkBasepointMul(t *testing.B) {
@@ -236,6 +1058,23 @@ func BenchmarkBasepointMul(t *testing.B) {
 	}
 }
 
+func BenchmarkScalarBaseMultAdd(b *testing.B) {
+	P := (&Point{}).ScalarBaseMult(&dalekScalar)
+	b.Run("fused", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.ScalarBaseMultAdd(&dalekScalar, P)
+		}
+	})
+	b.Run("two-step", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.ScalarBaseMult(&dalekScalar)
+			p.Add(&p, P)
+		}
+	})
+}
+
 func BenchmarkScalarMul(t *testing.B) {
 	var p Point
 
@@ -244,6 +1083,38 @@ func BenchmarkScalarMul(t *testing.B) {
 	}
 }
 
+func BenchmarkScalarMultBlinded(b *testing.B) {
+	var p Point
+	for i := 0; i < b.N; i++ {
+		p.ScalarMultBlinded(nil, &dalekScalar, B)
+	}
+}
+
+func BenchmarkScalarMultSplit(b *testing.B) {
+	var p Point
+	for i := 0; i < b.N; i++ {
+		p.ScalarMultSplit(nil, &dalekScalar, B)
+	}
+}
+
+func BenchmarkBatchScalarMult(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, points := randomMultiScalarMultInputs(rand, 64)
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchScalarMult(&dalekScalar, points)
+		}
+	})
+	b.Run("ScalarMult", func(b *testing.B) {
+		results := make([]Point, len(points))
+		for i := 0; i < b.N; i++ {
+			for j := range points {
+				results[j].ScalarMult(&dalekScalar, points[j])
+			}
+		}
+	})
+}
+
 func BenchmarkVartimeDoubleBaseMul(t *testing.B) {
 	var p Point
 
@@ -252,6 +1123,40 @@ func BenchmarkVartimeDoubleBaseMul(t *testing.B) {
 	}
 }
 
+func BenchmarkVarTimeDoubleScalarBaseMultWidths(b *testing.B) {
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+	b.Run("w=5", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeDoubleScalarBaseMult(&dalekScalar, A, &dalekScalar)
+		}
+	})
+	b.Run("w=8", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.varTimeDoubleScalarBaseMultWide(&dalekScalar, A, &dalekScalar)
+		}
+	})
+}
+
+func BenchmarkVarTimeDoubleScalarMult(t *testing.B) {
+	var p Point
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+
+	for i := 0; i < t.N; i++ {
+		p.VarTimeDoubleScalarMult(&dalekScalar, A, &dalekScalar, B)
+	}
+}
+
+func BenchmarkVarTimeMultiScalarMulSize2(t *testing.B) {
+	var p Point
+	A := (&Point{}).ScalarBaseMult(&dalekScalar)
+
+	for i := 0; i < t.N; i++ {
+		p.VarTimeMultiScalarMult([]*Scalar{&dalekScalar, &dalekScalar}, []*Point{A, B})
+	}
+}
+
 func BenchmarkMultiscalarMulSize8(t *testing.B) {
 	var p Point
 	x := dalekScalar
@@ -261,5 +1166,128 @@ func BenchmarkMultiscalarMulSize8(t *testing.B) {
 	}
 }
 
+func BenchmarkVarTimeMultiScalarMultSparse(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	// 1000 terms exceed pippengerThreshold, but 90% zero leaves only 100.
+	for _, n := range []int{100, 1000} {
+		scalars, points := randomMultiScalarMultInputs(rand, n)
+		b.Run(fmt.Sprintf("%d/dense", n), func(b *testing.B) {
+			var p Point
+			for i := 0; i < b.N; i++ {
+				p.VarTimeMultiScalarMult(scalars, points)
+			}
+		})
+		sparse := make([]*Scalar, len(scalars))
+		for i := range sparse {
+			sparse[i] = NewScalar()
+			if i%10 == 0 {
+				sparse[i] = scalars[i]
+			}
+		}
+		b.Run(fmt.Sprintf("%d/90%%-zero", n), func(b *testing.B) {
+			var p Point
+			for i := 0; i < b.N; i++ {
+				p.VarTimeMultiScalarMult(sparse, points)
+			}
+		})
+	}
+}
+
+func BenchmarkVarTimeMultiScalarMultDedup(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, distinct := randomMultiScalarMultInputs(rand, 10)
+	scalars, _ := randomMultiScalarMultInputs(rand, 1000)
+	points := make([]*Point, len(scalars))
+	for i := range points {
+		points[i] = distinct[i%len(distinct)]
+	}
+	b.Run("dedup", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeMultiScalarMultDedup(scalars, points)
+		}
+	})
+	b.Run("no-dedup", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeMultiScalarMult(scalars, points)
+		}
+	})
+}
+
+func BenchmarkMultiScalarMultBounded(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, points := randomMultiScalarMultInputs(rand, 16)
+	scalars := make([]*Scalar, len(points))
+	for i := range scalars {
+		scalars[i] = scalarFromInt16(int16(rand.Intn(1 << 15)))
+	}
+	b.Run("64-bit", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.MultiScalarMultBounded(scalars, points, 64)
+		}
+	})
+	b.Run("unbounded", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.MultiScalarMult(scalars, points)
+		}
+	})
+}
+
+func BenchmarkVarTimeMultiLinearCombination(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, points := randomMultiScalarMultInputs(rand, 32)
+	coeffs := make([][]*Scalar, 32)
+	for j := range coeffs {
+		coeffs[j], _ = randomMultiScalarMultInputs(rand, len(points))
+	}
+	b.Run("shared-tables", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			VarTimeMultiLinearCombination(coeffs, points)
+		}
+	})
+	b.Run("VarTimeMultiScalarMult", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			for j := range coeffs {
+				p.VarTimeMultiScalarMult(coeffs[j], points)
+			}
+		}
+	})
+}
+
+func BenchmarkVarTimeStrausWidths(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{2, 16, 64, 190} {
+		scalars, points := randomMultiScalarMultInputs(rand, n)
+		for w := 4; w <= 8; w++ {
+			b.Run(fmt.Sprintf("n=%d/w=%d", n, w), func(b *testing.B) {
+				var p Point
+				c := (&MSMContext{}).SetNAFWidth(w)
+				for i := 0; i < b.N; i++ {
+					p.VarTimeMultiScalarMultWithContext(c, scalars, points)
+				}
+			})
+		}
+	}
+}
+
 // TODO: add BenchmarkVartimeMultiscalarMulSize8 (need to have
 // different scalars & points to measure cache effects).
+
+func BenchmarkScalarMultRadix(b *testing.B) {
+	b.Run("radix-16", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.ScalarMult(&dalekScalar, B)
+		}
+	})
+	b.Run("radix-32", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.scalarMultRadix32(&dalekScalar, B)
+		}
+	})
+}
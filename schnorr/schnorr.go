@@ -0,0 +1,88 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package schnorr implements Schnorr signatures over edwards25519, with a
+// caller-supplied challenge function.
+//
+// A signature by the secret key x, with public key A = x * B, of a message is
+// a pair (R, s) such that
+//
+//     s * B = R + challenge(R, A, msg) * A
+//
+// With Ed25519Challenge as the challenge function, and the nonce derived as in
+// RFC 8032, signatures are Ed25519 signatures.
+package schnorr
+
+import (
+	"crypto/sha512"
+
+	"filippo.io/edwards25519"
+)
+
+// A ChallengeFunc computes the challenge scalar of a signature from the
+// commitment R, the public key A, and the message. It should hash all three
+// with a domain separation string unique to the protocol.
+type ChallengeFunc func(R, A *edwards25519.Point, msg []byte) *edwards25519.Scalar
+
+// Ed25519Challenge is the challenge function of Ed25519, SHA-512(R || A || msg)
+// reduced modulo l, as specified in RFC 8032, Section 5.1.6.
+func Ed25519Challenge(R, A *edwards25519.Point, msg []byte) *edwards25519.Scalar {
+	h := sha512.New()
+	h.Write(R.Bytes())
+	h.Write(A.Bytes())
+	h.Write(msg)
+	return edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+}
+
+// Sign returns the signature (R, s) of msg by the secret key priv, with a
+// deterministic nonce.
+//
+// The nonce is SHA-512 of a domain separation string, priv, the challenge
+// computed for the basepoint as R, and msg, reduced modulo l. Including that
+// challenge ensures that signing the same message with the same key but
+// different challenge functions doesn't reuse a nonce, which would reveal the
+// key.
+//
+// Sign executes in constant time, if challenge does.
+func Sign(priv *edwards25519.Scalar, msg []byte, challenge ChallengeFunc) (R *edwards25519.Point, s *edwards25519.Scalar) {
+	A := (&edwards25519.Point{}).ScalarBaseMult(priv)
+	h := sha512.New()
+	h.Write([]byte("edwards25519 Schnorr nonce"))
+	h.Write(priv.Bytes())
+	h.Write(challenge(edwards25519.NewGeneratorPoint(), A, msg).Bytes())
+	h.Write(msg)
+	nonce := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	return SignWithNonce(priv, nonce, msg, challenge)
+}
+
+// SignWithNonce is like Sign, but uses the caller-supplied nonce, for example
+// to implement a protocol that specifies how nonces are derived.
+//
+// The nonce must be secret, uniformly random, and never used for two different
+// messages or challenge functions, or the secret key can be computed from the
+// two signatures. Most applications should use Sign instead.
+//
+// SignWithNonce executes in constant time, if challenge does.
+func SignWithNonce(priv, nonce *edwards25519.Scalar, msg []byte, challenge ChallengeFunc) (R *edwards25519.Point, s *edwards25519.Scalar) {
+	A := (&edwards25519.Point{}).ScalarBaseMult(priv)
+	R = (&edwards25519.Point{}).ScalarBaseMult(nonce)
+	c := challenge(R, A, msg)
+	s = edwards25519.NewScalar().MultiplyAdd(c, priv, nonce)
+	return R, s
+}
+
+// Verify reports whether (R, s) is a valid signature of msg by the public key
+// A, with the cofactorless equation s * B = R + challenge(R, A, msg) * A. It
+// returns false if A has small order.
+//
+// Verify executes in variable time.
+func Verify(A *edwards25519.Point, msg []byte, R *edwards25519.Point, s *edwards25519.Scalar, challenge ChallengeFunc) bool {
+	identity := edwards25519.NewIdentityPoint()
+	if (&edwards25519.Point{}).MultByCofactor(A).Equal(identity) == 1 {
+		return false
+	}
+	minusC := edwards25519.NewScalar().Negate(challenge(R, A, msg))
+	check := (&edwards25519.Point{}).VarTimeDoubleScalarBaseMult(minusC, A, s)
+	return check.Equal(R) == 1
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"testing"
+
+	"filippo.io/edwards25519"
+)
+
+func testChallenge(R, A *edwards25519.Point, msg []byte) *edwards25519.Scalar {
+	h := sha512.New()
+	h.Write([]byte("schnorr test challenge"))
+	h.Write(R.Bytes())
+	h.Write(A.Bytes())
+	h.Write(msg)
+	return edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+}
+
+func testKey(t *testing.T, seed byte) (*edwards25519.Scalar, *edwards25519.Point) {
+	b := make([]byte, ed25519.SeedSize)
+	b[0] = seed
+	x, _, err := edwards25519.ScalarAndPrefixFromSeed(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x, (&edwards25519.Point{}).ScalarBaseMult(x)
+}
+
+func TestSignVerify(t *testing.T) {
+	x, A := testKey(t, 1)
+	_, otherA := testKey(t, 2)
+	msg := []byte("message")
+
+	for name, challenge := range map[string]ChallengeFunc{
+		"test": testChallenge, "Ed25519": Ed25519Challenge,
+	} {
+		R, s := Sign(x, msg, challenge)
+		if !Verify(A, msg, R, s, challenge) {
+			t.Errorf("%s: valid signature does not verify", name)
+		}
+		if Verify(A, []byte("other message"), R, s, challenge) {
+			t.Errorf("%s: signature verifies for a different message", name)
+		}
+		if Verify(otherA, msg, R, s, challenge) {
+			t.Errorf("%s: signature verifies for a different key", name)
+		}
+		otherS := edwards25519.NewScalar().Add(s, s)
+		if Verify(A, msg, R, otherS, challenge) {
+			t.Errorf("%s: signature verifies with a different s", name)
+		}
+		otherR := (&edwards25519.Point{}).Add(R, edwards25519.NewGeneratorPoint())
+		if Verify(A, msg, otherR, s, challenge) {
+			t.Errorf("%s: signature verifies with a different R", name)
+		}
+
+		// Signatures are deterministic.
+		R2, s2 := Sign(x, msg, challenge)
+		if R2.Equal(R) != 1 || s2.Equal(s) != 1 {
+			t.Errorf("%s: signature is not deterministic", name)
+		}
+	}
+}
+
+func TestSignNonces(t *testing.T) {
+	x, _ := testKey(t, 1)
+	R1, _ := Sign(x, []byte("message 1"), testChallenge)
+	R2, _ := Sign(x, []byte("message 2"), testChallenge)
+	if R1.Equal(R2) == 1 {
+		t.Errorf("different messages reused a nonce")
+	}
+	R3, _ := Sign(x, []byte("message 1"), Ed25519Challenge)
+	if R1.Equal(R3) == 1 {
+		t.Errorf("different challenge functions reused a nonce")
+	}
+}
+
+func TestNonceReuse(t *testing.T) {
+	// Reusing a nonce for two messages is detectable from the shared R, and
+	// reveals the key as x = (s1 - s2) / (c1 - c2).
+	x, A := testKey(t, 1)
+	nonce := edwards25519.NewScalar().SetUniformBytes(bytes.Repeat([]byte{42}, 64))
+	msg1, msg2 := []byte("message 1"), []byte("message 2")
+	R1, s1 := SignWithNonce(x, nonce, msg1, testChallenge)
+	R2, s2 := SignWithNonce(x, nonce, msg2, testChallenge)
+	if R1.Equal(R2) != 1 {
+		t.Fatalf("same nonce produced different R")
+	}
+	c1, c2 := testChallenge(R1, A, msg1), testChallenge(R2, A, msg2)
+	num := edwards25519.NewScalar().Subtract(s1, s2)
+	den := edwards25519.NewScalar().Subtract(c1, c2)
+	recovered := edwards25519.NewScalar().Multiply(num, edwards25519.NewScalar().Invert(den))
+	if recovered.Equal(x) != 1 {
+		t.Errorf("failed to recover the key from a reused nonce")
+	}
+}
+
+func TestEd25519Compatibility(t *testing.T) {
+	// RFC 8032, Section 7.1, Test 1.
+	seed := decodeHex("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	expected := decodeHex("e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b")
+	if sig := ed25519Sign(t, seed, nil); !bytes.Equal(sig, expected) {
+		t.Errorf("RFC 8032 test 1: got %x, expected %x", sig, expected)
+	}
+
+	for i := 0; i < 16; i++ {
+		seed := make([]byte, ed25519.SeedSize)
+		seed[0] = byte(i)
+		msg := bytes.Repeat([]byte{byte(i)}, i*7)
+		sig := ed25519Sign(t, seed, msg)
+		expected := ed25519.Sign(ed25519.NewKeyFromSeed(seed), msg)
+		if !bytes.Equal(sig, expected) {
+			t.Errorf("seed %d: got %x, expected %x", i, sig, expected)
+		}
+	}
+}
+
+// ed25519Sign produces an Ed25519 signature with SignWithNonce, deriving the
+// nonce as specified in RFC 8032, Section 5.1.6.
+func ed25519Sign(t *testing.T, seed, msg []byte) []byte {
+	x, prefix, err := edwards25519.ScalarAndPrefixFromSeed(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sha512.New()
+	h.Write(prefix)
+	h.Write(msg)
+	nonce := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	R, s := SignWithNonce(x, nonce, msg, Ed25519Challenge)
+	A := (&edwards25519.Point{}).ScalarBaseMult(x)
+	if !Verify(A, msg, R, s, Ed25519Challenge) {
+		t.Errorf("Ed25519 signature does not verify")
+	}
+	return append(R.Bytes(), s.Bytes()...)
+}
+
+func TestVerifySmallOrderKey(t *testing.T) {
+	lowOrder, err := (&edwards25519.Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// With the identity as public key, R = s * B is valid for any message
+	// under the bare equation, so it must be rejected.
+	s := edwards25519.NewScalar().SetUniformBytes(bytes.Repeat([]byte{42}, 64))
+	R := (&edwards25519.Point{}).ScalarBaseMult(s)
+	for _, A := range []*edwards25519.Point{edwards25519.NewIdentityPoint(), lowOrder} {
+		if Verify(A, []byte("message"), R, s, testChallenge) {
+			t.Errorf("signature verifies for small order key %x", A.Bytes())
+		}
+	}
+}
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
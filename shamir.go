@@ -0,0 +1,68 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"errors"
+	"io"
+	"math"
+)
+
+// ShamirShare is a share of a secret scalar split with ShamirSplit, the value
+// of the secret polynomial at Index.
+type ShamirShare struct {
+	Index uint32
+	Value Scalar
+}
+
+// ShamirSplit splits secret into n shares, any t of which can recover it with
+// ShamirRecover, while fewer than t reveal nothing about it. The shares have
+// indices 1 to n. The random coefficients of the polynomial are read from
+// rand, or from crypto/rand.Reader if rand is nil.
+//
+// t must be between 1 and n, or ShamirSplit returns an error.
+func ShamirSplit(secret *Scalar, t, n int, rand io.Reader) ([]ShamirShare, error) {
+	if t < 1 || t > n {
+		return nil, errors.New("edwards25519: invalid Shamir threshold")
+	}
+	if uint64(n) > math.MaxUint32 {
+		return nil, errors.New("edwards25519: too many Shamir shares")
+	}
+	p, err := RandomPolynomial(t-1, rand)
+	if err != nil {
+		return nil, err
+	}
+	p.SetCoefficient(0, secret)
+
+	shares := make([]ShamirShare, n)
+	x := NewScalar()
+	for i := range shares {
+		shares[i].Index = uint32(i + 1)
+		shares[i].Value.Set(p.Evaluate(x.SetUint64(uint64(i + 1))))
+	}
+	for i := range p.coefficients {
+		p.coefficients[i] = scZero
+	}
+	return shares, nil
+}
+
+// ShamirRecover returns the secret that shares were split from. The indices of
+// the shares must be nonzero and distinct, or ShamirRecover returns an error.
+//
+// If fewer shares are passed than the threshold they were split with, or if
+// any of them is corrupted, the result is an unrelated scalar, with no error.
+func ShamirRecover(shares []ShamirShare) (*Scalar, error) {
+	indices := make([]uint32, len(shares))
+	values := make([]*Scalar, len(shares))
+	for i := range shares {
+		indices[i] = shares[i].Index
+		values[i] = &shares[i].Value
+	}
+	coefficients, err := LagrangeCoefficients(indices)
+	if err != nil {
+		return nil, err
+	}
+	return InnerProduct(coefficients, values), nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	mathrand "math/rand"
+	"testing"
+)
+
+func TestShamir(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	secret := randomScalars(rand, 1)[0]
+	for _, tn := range [][2]int{{1, 1}, {1, 3}, {2, 3}, {3, 3}, {3, 5}, {7, 10}} {
+		threshold, n := tn[0], tn[1]
+		shares, err := ShamirSplit(secret, threshold, n, rand)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(shares) != n {
+			t.Fatalf("%v: got %d shares", tn, len(shares))
+		}
+
+		// Any threshold shares recover the secret, in any order.
+		for trial := 0; trial < 5; trial++ {
+			perm := rand.Perm(n)
+			for k := threshold; k <= n; k++ {
+				subset := make([]ShamirShare, k)
+				for i := range subset {
+					subset[i] = shares[perm[i]]
+				}
+				got, err := ShamirRecover(subset)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Equal(secret) != 1 {
+					t.Errorf("%v: %d shares did not recover the secret", tn, k)
+				}
+			}
+			if threshold > 1 {
+				got, err := ShamirRecover([]ShamirShare{shares[perm[0]]})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Equal(secret) == 1 {
+					t.Errorf("%v: a single share recovered the secret", tn)
+				}
+			}
+		}
+	}
+}
+
+func TestShamirErrors(t *testing.T) {
+	for _, tn := range [][2]int{{0, 3}, {4, 3}, {-1, 3}, {1, 0}} {
+		if _, err := ShamirSplit(&scOne, tn[0], tn[1], nil); err == nil {
+			t.Errorf("expected an error for t = %d, n = %d", tn[0], tn[1])
+		}
+	}
+	if _, err := ShamirSplit(&scOne, 2, 3, errorReader{}); err == nil {
+		t.Error("expected an error from a failing reader")
+	}
+
+	shares, err := ShamirSplit(&scOne, 2, 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ShamirRecover([]ShamirShare{shares[0], shares[0]}); err == nil {
+		t.Error("expected an error for duplicate shares")
+	}
+	if _, err := ShamirRecover(nil); err == nil {
+		t.Error("expected an error for no shares")
+	}
+}
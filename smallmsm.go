@@ -0,0 +1,166 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// VarTimeMultiScalarMultSmall sets v = sum(coeffs[i] * points[i]), and returns
+// v. It is much faster than VarTimeMultiScalarMult for small coefficients, as
+// its cost grows with the bit length of the largest one.
+//
+// Execution time depends on the inputs.
+func (v *Point) VarTimeMultiScalarMultSmall(coeffs []int16, points []*Point) *Point {
+	if len(coeffs) != len(points) {
+		panic("edwards25519: called VarTimeMultiScalarMultSmall with different size inputs")
+	}
+	checkInitialized(points...)
+
+	// Negate the points with negative coefficients, so that only the
+	// magnitudes are left to deal with.
+	cached := make([]projCached, len(points))
+	mags := make([]uint16, len(coeffs))
+	var max uint16
+	for i, c := range coeffs {
+		cached[i].FromP3(points[i])
+		if c < 0 {
+			cached[i].CondNeg(1)
+			mags[i] = uint16(-int32(c))
+		} else {
+			mags[i] = uint16(c)
+		}
+		if mags[i] > max {
+			max = mags[i]
+		}
+	}
+
+	v.Set(NewIdentityPoint())
+	b := uint(bits.Len16(max))
+	if b == 0 {
+		return v
+	}
+
+	// Split the magnitudes into windows of w bits. Each window costs one
+	// addition per point plus two per bucket, so pick the w that minimizes
+	// that over all windows. For bit vectors that's a single window with a
+	// single bucket, which is a plain sum.
+	w, bestCost := uint(1), -1
+	for ww := uint(1); ww <= b && ww <= 8; ww++ {
+		windows := int((b + ww - 1) / ww)
+		cost := windows * (len(points) + 2*(1<<ww-1))
+		if bestCost < 0 || cost < bestCost {
+			w, bestCost = ww, cost
+		}
+	}
+	numWindows := (b + w - 1) / w
+	mask := uint16(1<<w - 1)
+
+	buckets := make([]Point, 1<<w-1)
+	tmp1 := &projP1xP1{}
+	tmp2 := &projP2{}
+	bucketSum := &Point{}
+	windowSum := &Point{}
+	bucketCached := &projCached{}
+
+	for d := int(numWindows) - 1; d >= 0; d-- {
+		if d != int(numWindows)-1 {
+			// Multiply the accumulator by 2^w.
+			tmp2.FromP3(v)
+			for i := uint(0); i < w; i++ {
+				tmp1.Double(tmp2)
+				tmp2.FromP1xP1(tmp1)
+			}
+			v.fromP1xP1(tmp1)
+		}
+
+		for i := range buckets {
+			buckets[i].Set(NewIdentityPoint())
+		}
+		for i := range cached {
+			if digit := (mags[i] >> (uint(d) * w)) & mask; digit != 0 {
+				b := &buckets[digit-1]
+				b.fromP1xP1(tmp1.Add(b, &cached[i]))
+			}
+		}
+
+		// Compute sum(k * buckets[k-1]) with the running-sum trick, as in
+		// varTimePippenger.
+		bucketSum.Set(NewIdentityPoint())
+		windowSum.Set(NewIdentityPoint())
+		for k := len(buckets) - 1; k >= 0; k-- {
+			bucketCached.FromP3(&buckets[k])
+			bucketSum.fromP1xP1(tmp1.Add(bucketSum, bucketCached))
+			bucketCached.FromP3(bucketSum)
+			windowSum.fromP1xP1(tmp1.Add(windowSum, bucketCached))
+		}
+
+		bucketCached.FromP3(windowSum)
+		v.fromP1xP1(tmp1.Add(v, bucketCached))
+	}
+
+	return v
+}
+
+// VarTimeMultiScalarMultBool sets v to the sum of the points[i] for which
+// coeffs[i] is true, and returns v.
+//
+// Execution time depends on the inputs.
+func (v *Point) VarTimeMultiScalarMultBool(coeffs []bool, points []*Point) *Point {
+	if len(coeffs) != len(points) {
+		panic("edwards25519: called VarTimeMultiScalarMultBool with different size inputs")
+	}
+	checkInitialized(points...)
+
+	pCached := &projCached{}
+	tmp1 := &projP1xP1{}
+	acc := NewIdentityPoint()
+	for i := range coeffs {
+		if coeffs[i] {
+			pCached.FromP3(points[i])
+			acc.fromP1xP1(tmp1.Add(acc, pCached))
+		}
+	}
+	return v.Set(acc)
+}
+
+var (
+	errSignedSumLength = errors.New("edwards25519: called SignedSum with different size inputs")
+	errSignedSumSign   = errors.New("edwards25519: SignedSum sign is not -1, 0, or 1")
+)
+
+// SignedSum sets v = sum(signs[i] * points[i]), where each sign is -1, 0, or
+// 1, and returns v. It is a chain of additions and subtractions, much faster
+// than a multiscalar multiplication with scalars 1 and -1.
+//
+// If the slices have different lengths, or a sign is out of range, SignedSum
+// returns nil and an error, and the receiver is unchanged.
+func (v *Point) SignedSum(signs []int8, points []*Point) (*Point, error) {
+	if len(signs) != len(points) {
+		return nil, errSignedSumLength
+	}
+	for _, s := range signs {
+		if s < -1 || s > 1 {
+			return nil, errSignedSumSign
+		}
+	}
+	checkInitialized(points...)
+
+	pCached := &projCached{}
+	tmp1 := &projP1xP1{}
+	acc := NewIdentityPoint()
+	for i := range signs {
+		switch signs[i] {
+		case 1:
+			pCached.FromP3(points[i])
+			acc.fromP1xP1(tmp1.Add(acc, pCached))
+		case -1:
+			pCached.FromP3(points[i])
+			acc.fromP1xP1(tmp1.Sub(acc, pCached))
+		}
+	}
+	return v.Set(acc), nil
+}
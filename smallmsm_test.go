@@ -0,0 +1,177 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	mathrand "math/rand"
+	"testing"
+)
+
+func scalarFromInt16(c int16) *Scalar {
+	m := int32(c)
+	if m < 0 {
+		m = -m
+	}
+	var b [32]byte
+	b[0], b[1] = byte(m), byte(m>>8)
+	s, err := NewScalar().SetCanonicalBytes(b[:])
+	if err != nil {
+		panic(err)
+	}
+	if c < 0 {
+		s.Negate(s)
+	}
+	return s
+}
+
+func TestVarTimeMultiScalarMultSmall(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{0, 1, 5, 64} {
+		_, points := randomMultiScalarMultInputs(rand, n)
+		for _, max := range []int{1, 2, 255, 4096, 1 << 16} {
+			coeffs := make([]int16, n)
+			scalars := make([]*Scalar, n)
+			for i := range coeffs {
+				coeffs[i] = int16(rand.Intn(max) - max/2)
+				switch rand.Intn(20) {
+				case 0:
+					coeffs[i] = -32768
+				case 1:
+					coeffs[i] = 32767
+				}
+				scalars[i] = scalarFromInt16(coeffs[i])
+			}
+			var p, check Point
+			p.Set(B)
+			p.VarTimeMultiScalarMultSmall(coeffs, points)
+			check.MultiScalarMult(scalars, points)
+			checkOnCurve(t, &p)
+			if p.Equal(&check) != 1 {
+				t.Errorf("n = %d, max = %d: result does not match MultiScalarMult", n, max)
+			}
+		}
+	}
+}
+
+func TestVarTimeMultiScalarMultBool(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{0, 1, 64} {
+		_, points := randomMultiScalarMultInputs(rand, n)
+		coeffs := make([]bool, n)
+		small := make([]int16, n)
+		for i := range coeffs {
+			coeffs[i] = rand.Intn(2) == 0
+			if coeffs[i] {
+				small[i] = 1
+			}
+		}
+		var p, check Point
+		p.Set(B)
+		p.VarTimeMultiScalarMultBool(coeffs, points)
+		check.VarTimeMultiScalarMultSmall(small, points)
+		if p.Equal(&check) != 1 {
+			t.Errorf("n = %d: result does not match VarTimeMultiScalarMultSmall", n)
+		}
+	}
+}
+
+func TestSignedSum(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	for _, n := range []int{0, 1, 2, 64} {
+		_, points := randomMultiScalarMultInputs(rand, n)
+		signs := make([]int8, n)
+		scalars := make([]*Scalar, n)
+		for i := range signs {
+			signs[i] = int8(rand.Intn(3) - 1)
+			scalars[i] = scalarFromInt16(int16(signs[i]))
+		}
+		var check Point
+		check.MultiScalarMult(scalars, points)
+		p := (&Point{}).Set(B)
+		if out, err := p.SignedSum(signs, points); err != nil {
+			t.Fatal(err)
+		} else if out != p {
+			t.Error("SignedSum did not return its receiver")
+		}
+		if p.Equal(&check) != 1 {
+			t.Errorf("n = %d: result does not match MultiScalarMult", n)
+		}
+	}
+
+	p := (&Point{}).Set(B)
+	if out, err := p.SignedSum([]int8{1, 2}, []*Point{B, B}); err != errSignedSumSign || out != nil {
+		t.Errorf("got %v, %v for an invalid sign", out, err)
+	}
+	if out, err := p.SignedSum([]int8{1}, []*Point{B, B}); err != errSignedSumLength || out != nil {
+		t.Errorf("got %v, %v for different size inputs", out, err)
+	}
+	if p.Equal(B) != 1 {
+		t.Error("SignedSum modified its receiver on error")
+	}
+}
+
+func BenchmarkSignedSum(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, points := randomMultiScalarMultInputs(rand, 64)
+	signs := make([]int8, len(points))
+	scalars := make([]*Scalar, len(points))
+	for i := range signs {
+		signs[i] = int8(1 - 2*rand.Intn(2))
+		scalars[i] = scalarFromInt16(int16(signs[i]))
+	}
+	b.Run("SignedSum", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.SignedSum(signs, points)
+		}
+	})
+	b.Run("VarTimeMultiScalarMult", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeMultiScalarMult(scalars, points)
+		}
+	})
+}
+
+func BenchmarkVarTimeMultiScalarMultSmall(b *testing.B) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	_, points := randomMultiScalarMultInputs(rand, 256)
+	bools := make([]bool, len(points))
+	bits := make([]int16, len(points))
+	bytes := make([]int16, len(points))
+	scalars := make([]*Scalar, len(points))
+	for i := range points {
+		bools[i] = rand.Intn(2) == 0
+		if bools[i] {
+			bits[i] = 1
+		}
+		bytes[i] = int16(rand.Intn(256))
+		scalars[i] = scalarFromInt16(bytes[i])
+	}
+	b.Run("bool", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeMultiScalarMultBool(bools, points)
+		}
+	})
+	b.Run("1-bit", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeMultiScalarMultSmall(bits, points)
+		}
+	})
+	b.Run("8-bit", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeMultiScalarMultSmall(bytes, points)
+		}
+	})
+	b.Run("8-bit/general", func(b *testing.B) {
+		var p Point
+		for i := 0; i < b.N; i++ {
+			p.VarTimeMultiScalarMult(scalars, points)
+		}
+	})
+}
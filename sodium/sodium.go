@@ -0,0 +1,291 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sodium implements the crypto_core_ed25519 functions of libsodium,
+// with byte-for-byte identical results, including the inputs that are
+// rejected.
+//
+// Points and scalars are 32 bytes little-endian encodings. Like libsodium,
+// the point functions accept non-canonical encodings and points of small
+// order, except IsValidPoint, and the scalar functions accept any 32 bytes,
+// reducing them modulo l.
+package sodium
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"filippo.io/edwards25519"
+)
+
+const (
+	// Bytes is the size of an encoded point, crypto_core_ed25519_BYTES.
+	Bytes = 32
+
+	// UniformBytes is the size of the input of FromUniform,
+	// crypto_core_ed25519_UNIFORMBYTES.
+	UniformBytes = 32
+
+	// ScalarBytes is the size of a scalar, crypto_core_ed25519_SCALARBYTES.
+	ScalarBytes = 32
+
+	// NonReducedScalarBytes is the size of the input of ScalarReduce,
+	// crypto_core_ed25519_NONREDUCEDSCALARBYTES.
+	NonReducedScalarBytes = 64
+)
+
+// smallOrderBlocklist are the encodings, with the sign bit cleared, that
+// libsodium's ge25519_has_small_order rejects: the canonical encodings of the
+// y-coordinates of the points of small order, and the non-canonical
+// encodings p and p + 1 of 0 and 1.
+var smallOrderBlocklist = [][32]byte{
+	// 0 (order 4)
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+	// 1 (order 1)
+	{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+	// 2707385501144840649318225287225658788936804267575313519463743609750303402022 (order 8)
+	{0x26, 0xe8, 0x95, 0x8f, 0xc2, 0xb2, 0x27, 0xb0, 0x45, 0xc3, 0xf4, 0x89, 0xf2, 0xef, 0x98, 0xf0,
+		0xd5, 0xdf, 0xac, 0x05, 0xd3, 0xc6, 0x33, 0x39, 0xb1, 0x38, 0x02, 0x88, 0x6d, 0x53, 0xfc, 0x05},
+	// 55188659117513257062467267217118295137698188065244968500265048394206261417927 (order 8)
+	{0xc7, 0x17, 0x6a, 0x70, 0x3d, 0x4d, 0xd8, 0x4f, 0xba, 0x3c, 0x0b, 0x76, 0x0d, 0x10, 0x67, 0x0f,
+		0x2a, 0x20, 0x53, 0xfa, 0x2c, 0x39, 0xcc, 0xc6, 0x4e, 0xc7, 0xfd, 0x77, 0x92, 0xac, 0x03, 0x7a},
+	// p - 1 (order 2)
+	{0xec, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+	// p (=0, order 4)
+	{0xed, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+	// p + 1 (=1, order 1)
+	{0xee, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+}
+
+// isCanonical implements ge25519_is_canonical, which reports whether the
+// y-coordinate of p, ignoring the sign bit, is lower than 2^255 - 19.
+func isCanonical(p []byte) bool {
+	c := (p[31] & 0x7f) ^ 0x7f
+	for i := 30; i > 0; i-- {
+		c |= p[i] ^ 0xff
+	}
+	// The encoding is non-canonical if bytes 1 to 31 are all ones, and byte
+	// 0 is at least 0xed.
+	return c != 0 || p[0] < 0xed
+}
+
+// hasSmallOrder implements ge25519_has_small_order.
+func hasSmallOrder(p []byte) bool {
+	var masked [32]byte
+	copy(masked[:], p)
+	masked[31] &= 0x7f
+	for i := range smallOrderBlocklist {
+		if subtle.ConstantTimeCompare(masked[:], smallOrderBlocklist[i][:]) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidPoint implements crypto_core_ed25519_is_valid_point. It reports
+// whether p is the canonical encoding of a point on the curve, in the prime
+// order subgroup, and not the identity.
+func IsValidPoint(p []byte) bool {
+	if len(p) != Bytes || !isCanonical(p) || hasSmallOrder(p) {
+		return false
+	}
+	P, err := new(edwards25519.Point).SetBytes(p)
+	if err != nil {
+		return false
+	}
+	// P is in the prime order subgroup if (l - 1) * P + P is the identity.
+	minusOne := edwards25519.NewScalar().Negate(scalarOne)
+	lP := new(edwards25519.Point).ScalarMult(minusOne, P)
+	lP.Add(lP, P)
+	return lP.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+// Add implements crypto_core_ed25519_add. It returns the encoding of p + q,
+// or an error if either is not a valid point encoding.
+func Add(p, q []byte) ([]byte, error) {
+	P, Q, err := decodePoints(p, q)
+	if err != nil {
+		return nil, err
+	}
+	return P.Add(P, Q).Bytes(), nil
+}
+
+// Sub implements crypto_core_ed25519_sub. It returns the encoding of p - q,
+// or an error if either is not a valid point encoding.
+func Sub(p, q []byte) ([]byte, error) {
+	P, Q, err := decodePoints(p, q)
+	if err != nil {
+		return nil, err
+	}
+	return P.Subtract(P, Q).Bytes(), nil
+}
+
+func decodePoints(p, q []byte) (P, Q *edwards25519.Point, err error) {
+	if len(p) != Bytes || len(q) != Bytes {
+		return nil, nil, errors.New("sodium: invalid point length")
+	}
+	if P, err = new(edwards25519.Point).SetBytes(p); err != nil {
+		return nil, nil, err
+	}
+	if Q, err = new(edwards25519.Point).SetBytes(q); err != nil {
+		return nil, nil, err
+	}
+	return P, Q, nil
+}
+
+// FromUniform implements crypto_core_ed25519_from_uniform. It maps r to a
+// point with Elligator 2, taking the sign of its x-coordinate from the high
+// bit of r, and multiplies it by the cofactor.
+//
+// The map is computed in constant time.
+func FromUniform(r []byte) ([]byte, error) {
+	if len(r) != UniformBytes {
+		return nil, errors.New("sodium: invalid uniform input length")
+	}
+	// The RFC 9380 map ignores the high bit, and picks the same
+	// y-coordinate as libsodium, but a different sign for x.
+	P, err := edwards25519.MapToCurveElligator2(r)
+	if err != nil {
+		return nil, err
+	}
+	enc := P.Bytes()
+	enc[31] = enc[31]&0x7f | r[31]&0x80
+	P, err = P.SetBytes(enc)
+	if err != nil {
+		panic("sodium: internal error: invalid Elligator 2 output")
+	}
+	return P.MultByCofactor(P).Bytes(), nil
+}
+
+var scalarOne, _ = edwards25519.NewScalar().SetCanonicalBytes([]byte{1,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})
+
+// reduce returns s modulo l. s can be any 32 bytes, like in libsodium.
+func reduce(s []byte) (*edwards25519.Scalar, error) {
+	if len(s) != ScalarBytes {
+		return nil, errors.New("sodium: invalid scalar length")
+	}
+	var wide [64]byte
+	copy(wide[:], s)
+	return edwards25519.NewScalar().SetUniformBytes(wide[:]), nil
+}
+
+// ScalarReduce implements crypto_core_ed25519_scalar_reduce. It returns s
+// modulo l, where s is 64 bytes long.
+func ScalarReduce(s []byte) ([]byte, error) {
+	if len(s) != NonReducedScalarBytes {
+		return nil, errors.New("sodium: invalid non-reduced scalar length")
+	}
+	return edwards25519.NewScalar().SetUniformBytes(s).Bytes(), nil
+}
+
+// ScalarInvert implements crypto_core_ed25519_scalar_invert. It returns the
+// inverse of s modulo l, or an error if s is all zeroes. Like libsodium, it
+// returns zero for the other encodings of multiples of l.
+//
+// The inversion is done in constant time.
+func ScalarInvert(s []byte) ([]byte, error) {
+	x, err := reduce(s)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(s, make([]byte, ScalarBytes)) == 1 {
+		return nil, errors.New("sodium: inverse of zero")
+	}
+	// Scalar.Invert panics on zero, while libsodium computes s^(l-2), which
+	// is zero. Invert 1 instead, and select zero afterwards.
+	isZero := x.Equal(edwards25519.NewScalar())
+	one := edwards25519.NewScalar().Set(scalarOne)
+	inv := edwards25519.NewScalar().Invert(selectScalar(one, x, isZero)).Bytes()
+	subtle.ConstantTimeCopy(isZero, inv, make([]byte, ScalarBytes))
+	return inv, nil
+}
+
+// selectScalar returns a if cond is 1, and b if cond is 0.
+func selectScalar(a, b *edwards25519.Scalar, cond int) *edwards25519.Scalar {
+	out := b.Bytes()
+	subtle.ConstantTimeCopy(cond, out, a.Bytes())
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(out)
+	if err != nil {
+		panic("sodium: internal error: scalar is not canonical")
+	}
+	return s
+}
+
+// ScalarNegate implements crypto_core_ed25519_scalar_negate. It returns -s
+// modulo l.
+func ScalarNegate(s []byte) ([]byte, error) {
+	x, err := reduce(s)
+	if err != nil {
+		return nil, err
+	}
+	return x.Negate(x).Bytes(), nil
+}
+
+// ScalarComplement implements crypto_core_ed25519_scalar_complement. It
+// returns 1 - s modulo l.
+func ScalarComplement(s []byte) ([]byte, error) {
+	x, err := reduce(s)
+	if err != nil {
+		return nil, err
+	}
+	return x.Subtract(scalarOne, x).Bytes(), nil
+}
+
+// ScalarAdd implements crypto_core_ed25519_scalar_add. It returns x + y
+// modulo 2^256, reduced modulo l. Like in libsodium, the carry out of the
+// 256-bit sum is discarded, so the result is not x + y modulo l if the sum
+// overflows, which can only happen if x or y are not reduced.
+func ScalarAdd(x, y []byte) ([]byte, error) {
+	if len(x) != ScalarBytes || len(y) != ScalarBytes {
+		return nil, errors.New("sodium: invalid scalar length")
+	}
+	var sum [ScalarBytes]byte
+	var carry uint16
+	for i := range sum {
+		carry += uint16(x[i]) + uint16(y[i])
+		sum[i] = byte(carry)
+		carry >>= 8
+	}
+	z, err := reduce(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return z.Bytes(), nil
+}
+
+// ScalarSub implements crypto_core_ed25519_scalar_sub. It returns
+// ScalarAdd(x, ScalarNegate(y)), which is x - y modulo l if x is reduced.
+func ScalarSub(x, y []byte) ([]byte, error) {
+	negY, err := ScalarNegate(y)
+	if err != nil {
+		return nil, err
+	}
+	return ScalarAdd(x, negY)
+}
+
+// ScalarMul implements crypto_core_ed25519_scalar_mul. It returns x * y
+// modulo l.
+func ScalarMul(x, y []byte) ([]byte, error) {
+	a, b, err := reduce2(x, y)
+	if err != nil {
+		return nil, err
+	}
+	return a.Multiply(a, b).Bytes(), nil
+}
+
+func reduce2(x, y []byte) (a, b *edwards25519.Scalar, err error) {
+	if a, err = reduce(x); err != nil {
+		return nil, nil, err
+	}
+	if b, err = reduce(y); err != nil {
+		return nil, nil, err
+	}
+	return a, b, nil
+}
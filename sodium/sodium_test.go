@@ -0,0 +1,137 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sodium
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+)
+
+// libsodiumVectors are outputs of libsodium 1.0.18 for random and adversarial
+// inputs, including small order points, non-canonical encodings, points with
+// a small order component, off-curve encodings, and non-reduced scalars. A
+// nil result means libsodium returned -1.
+type libsodiumVectors struct {
+	Points []struct {
+		P, Q     string
+		Valid    bool
+		Add, Sub *string
+	}
+	FromUniform []struct {
+		R, Out string
+	}
+	Scalars []struct {
+		S, T                              string
+		Invert                            *string
+		Negate, Complement, Add, Sub, Mul string
+	}
+	Reduce []struct {
+		S, Out string
+	}
+}
+
+func loadVectors(t *testing.T) *libsodiumVectors {
+	data, err := ioutil.ReadFile("testdata/libsodium.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &libsodiumVectors{}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// checkResult compares the output of a function that can fail with the
+// libsodium output, where nil means failure.
+func checkResult(t *testing.T, name string, got []byte, err error, want *string) {
+	t.Helper()
+	switch {
+	case want == nil && err == nil:
+		t.Errorf("%s: got %x, expected error", name, got)
+	case want != nil && err != nil:
+		t.Errorf("%s: got error %v, expected %s", name, err, *want)
+	case want != nil && !bytes.Equal(got, decodeHex(*want)):
+		t.Errorf("%s: got %x, expected %s", name, got, *want)
+	}
+}
+
+func TestPointsLibsodium(t *testing.T) {
+	for _, v := range loadVectors(t).Points {
+		p, q := decodeHex(v.P), decodeHex(v.Q)
+		if got := IsValidPoint(p); got != v.Valid {
+			t.Errorf("IsValidPoint(%s) = %v, expected %v", v.P, got, v.Valid)
+		}
+		sum, err := Add(p, q)
+		checkResult(t, "Add("+v.P+", "+v.Q+")", sum, err, v.Add)
+		diff, err := Sub(p, q)
+		checkResult(t, "Sub("+v.P+", "+v.Q+")", diff, err, v.Sub)
+	}
+}
+
+func TestFromUniformLibsodium(t *testing.T) {
+	for _, v := range loadVectors(t).FromUniform {
+		got, err := FromUniform(decodeHex(v.R))
+		checkResult(t, "FromUniform("+v.R+")", got, err, &v.Out)
+	}
+}
+
+func TestScalarsLibsodium(t *testing.T) {
+	v := loadVectors(t)
+	for _, v := range v.Scalars {
+		s, x := decodeHex(v.S), decodeHex(v.T)
+		got, err := ScalarInvert(s)
+		checkResult(t, "ScalarInvert("+v.S+")", got, err, v.Invert)
+		got, err = ScalarNegate(s)
+		checkResult(t, "ScalarNegate("+v.S+")", got, err, &v.Negate)
+		got, err = ScalarComplement(s)
+		checkResult(t, "ScalarComplement("+v.S+")", got, err, &v.Complement)
+		got, err = ScalarAdd(s, x)
+		checkResult(t, "ScalarAdd("+v.S+", "+v.T+")", got, err, &v.Add)
+		got, err = ScalarSub(s, x)
+		checkResult(t, "ScalarSub("+v.S+", "+v.T+")", got, err, &v.Sub)
+		got, err = ScalarMul(s, x)
+		checkResult(t, "ScalarMul("+v.S+", "+v.T+")", got, err, &v.Mul)
+	}
+	for _, v := range v.Reduce {
+		got, err := ScalarReduce(decodeHex(v.S))
+		checkResult(t, "ScalarReduce("+v.S+")", got, err, &v.Out)
+	}
+}
+
+func TestLengths(t *testing.T) {
+	short, point := make([]byte, 31), make([]byte, 32)
+	point[0] = 1
+	if IsValidPoint(short) {
+		t.Error("IsValidPoint accepted a short input")
+	}
+	for name, f := range map[string]func() ([]byte, error){
+		"Add":              func() ([]byte, error) { return Add(short, point) },
+		"Sub":              func() ([]byte, error) { return Sub(point, short) },
+		"FromUniform":      func() ([]byte, error) { return FromUniform(short) },
+		"ScalarReduce":     func() ([]byte, error) { return ScalarReduce(point) },
+		"ScalarInvert":     func() ([]byte, error) { return ScalarInvert(short) },
+		"ScalarNegate":     func() ([]byte, error) { return ScalarNegate(short) },
+		"ScalarComplement": func() ([]byte, error) { return ScalarComplement(short) },
+		"ScalarAdd":        func() ([]byte, error) { return ScalarAdd(point, short) },
+		"ScalarSub":        func() ([]byte, error) { return ScalarSub(short, point) },
+		"ScalarMul":        func() ([]byte, error) { return ScalarMul(point, short) },
+	} {
+		if _, err := f(); err == nil {
+			t.Errorf("%s accepted a wrong length input", name)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package spake2 implements the edwards25519 group operations of the SPAKE2
+// password-authenticated key exchange of RFC 9382.
+//
+// Each party computes its message with Mask, from a random ephemeral scalar x
+// and the password scalar w, and recovers the shared point K from the peer's
+// message with Unmask. Hashing the transcript into the shared keys is left to
+// the caller, as in RFC 9382, Section 3.3.
+package spake2
+
+import "filippo.io/edwards25519"
+
+// encodedM and encodedN are the encodings of the M and N points for
+// edwards25519 of RFC 9382, Section 6. They are derived as specified in RFC
+// 9382, Appendix A, from the seeds "edwards25519 point generation seed (M)"
+// and "edwards25519 point generation seed (N)", by iterating SHA-256 on the
+// seed until the output is the encoding of a point of prime order.
+var (
+	encodedM = [32]byte{
+		0xd0, 0x48, 0x03, 0x2c, 0x6e, 0xa0, 0xb6, 0xd6,
+		0x97, 0xdd, 0xc2, 0xe8, 0x6b, 0xda, 0x85, 0xa3,
+		0x3a, 0xda, 0xc9, 0x20, 0xf1, 0xbf, 0x18, 0xe1,
+		0xb0, 0xc6, 0xd1, 0x66, 0xa5, 0xce, 0xcd, 0xaf,
+	}
+	encodedN = [32]byte{
+		0xd3, 0xbf, 0xb5, 0x18, 0xf4, 0x4f, 0x34, 0x30,
+		0xf2, 0x9d, 0x0c, 0x92, 0xaf, 0x50, 0x38, 0x65,
+		0xa1, 0xed, 0x32, 0x81, 0xdc, 0x69, 0xb3, 0x5d,
+		0xd8, 0x68, 0xba, 0x85, 0xf8, 0x86, 0xc4, 0xab,
+	}
+)
+
+func mustDecodePoint(b *[32]byte) *edwards25519.Point {
+	p, err := (&edwards25519.Point{}).SetBytes(b[:])
+	if err != nil {
+		panic("spake2: internal error: invalid point constant")
+	}
+	return p
+}
+
+// M returns the M point of SPAKE2 for edwards25519, as specified in RFC 9382,
+// Section 6. It's used to mask the value sent by party A.
+func M() *edwards25519.Point {
+	return mustDecodePoint(&encodedM)
+}
+
+// N returns the N point of SPAKE2 for edwards25519, as specified in RFC 9382,
+// Section 6. It's used to mask the value sent by party B.
+func N() *edwards25519.Point {
+	return mustDecodePoint(&encodedN)
+}
+
+// Mask returns x * B + w * MorN, the value pA or pB sent by a SPAKE2 party
+// with the ephemeral secret x and the password scalar w, where MorN is M() for
+// party A, and N() for party B.
+//
+// The multiplication is done in constant time.
+func Mask(x, w *edwards25519.Scalar, MorN *edwards25519.Point) *edwards25519.Point {
+	return (&edwards25519.Point{}).MultiScalarMult([]*edwards25519.Scalar{x, w},
+		[]*edwards25519.Point{edwards25519.NewGeneratorPoint(), MorN})
+}
+
+// Unmask returns peer - w * MorN, removing the mask from the value received
+// from the other SPAKE2 party, where MorN is N() for party A, and M() for party
+// B. The shared point K is then 8 * x * Unmask(...), where the multiplication
+// by the cofactor 8 is necessary to discard any small order component added by
+// the peer.
+//
+// The multiplication is done in constant time.
+func Unmask(peer *edwards25519.Point, w *edwards25519.Scalar, MorN *edwards25519.Point) *edwards25519.Point {
+	v := (&edwards25519.Point{}).ScalarMult(w, MorN)
+	return v.Subtract(peer, v)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package spake2
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	mathrand "math/rand"
+	"testing"
+
+	"filippo.io/edwards25519"
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestSPAKE2Constants(t *testing.T) {
+	I := edwards25519.NewIdentityPoint()
+	minusOne := edwards25519.NewScalar().Negate(edwards25519.NewScalar().SetUint64(1))
+	for _, tt := range []struct {
+		name  string
+		point *edwards25519.Point
+		seed  string
+		want  string
+	}{
+		{"M", M(), "edwards25519 point generation seed (M)",
+			"d048032c6ea0b6d697ddc2e86bda85a33adac920f1bf18e1b0c6d166a5cecdaf"},
+		{"N", N(), "edwards25519 point generation seed (N)",
+			"d3bfb518f44f3430f29d0c92af503865a1ed3281dc69b35dd868ba85f886c4ab"},
+	} {
+		if got := tt.point.Bytes(); !bytes.Equal(got, decodeHex(tt.want)) {
+			t.Errorf("%s = %x, expected %s", tt.name, got, tt.want)
+		}
+
+		// Reproduce the derivation of RFC 9382, Appendix A: the first
+		// iterated SHA-256 of the seed that decodes to a point of prime
+		// order, other than the identity.
+		h := sha256.Sum256([]byte(tt.seed))
+		for {
+			p, err := (&edwards25519.Point{}).SetBytes(h[:])
+			if err == nil && p.Equal(I) != 1 {
+				q := (&edwards25519.Point{}).ScalarMult(minusOne, p)
+				if q.Add(q, p).Equal(I) == 1 {
+					break
+				}
+			}
+			h = sha256.Sum256(h[:])
+		}
+		if !bytes.Equal(h[:], decodeHex(tt.want)) {
+			t.Errorf("%s derivation produced %x, expected %s", tt.name, h, tt.want)
+		}
+	}
+}
+
+// spake2Party runs one side of SPAKE2, as in RFC 9382, Section 3.3, returning
+// its message and a function that computes the shared point K and the hash of
+// the transcript from the peer's message.
+func spake2Party(rand *mathrand.Rand, w *edwards25519.Scalar, isA bool) (*edwards25519.Point, func(peer, pA *edwards25519.Point) (*edwards25519.Point, []byte)) {
+	var buf [64]byte
+	rand.Read(buf[:])
+	x := edwards25519.NewScalar().SetUniformBytes(buf[:])
+	own, other := M(), N()
+	if !isA {
+		own, other = other, own
+	}
+	msg := Mask(x, w, own)
+	return msg, func(peer, pA *edwards25519.Point) (*edwards25519.Point, []byte) {
+		K := Unmask(peer, w, other)
+		K.MultByCofactor(K)
+		K.ScalarMult(x, K)
+
+		pB := peer
+		if !isA {
+			pB = msg
+		}
+		// TT = len(A) || A || len(B) || B || len(pA) || pA || len(pB) || pB ||
+		// len(K) || K || len(w) || w, with 8-byte little-endian lengths.
+		var tt []byte
+		for _, field := range [][]byte{[]byte("A"), []byte("B"),
+			pA.Bytes(), pB.Bytes(), K.Bytes(), w.Bytes()} {
+			var l [8]byte
+			binary.LittleEndian.PutUint64(l[:], uint64(len(field)))
+			tt = append(tt, l[:]...)
+			tt = append(tt, field...)
+		}
+		sum := sha256.Sum256(tt)
+		return K, sum[:]
+	}
+}
+
+func TestSPAKE2RoundTrip(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	w := edwards25519.NewScalar().SetUniformBytes(bytes.Repeat([]byte("password"), 8))
+	wrong := edwards25519.NewScalar().SetUniformBytes(bytes.Repeat([]byte("passw0rd"), 8))
+
+	pA, finishA := spake2Party(rand, w, true)
+	pB, finishB := spake2Party(rand, w, false)
+	KA, keyA := finishA(pB, pA)
+	KB, keyB := finishB(pA, pA)
+	if KA.Equal(KB) != 1 || !bytes.Equal(keyA, keyB) {
+		t.Errorf("parties with the same password derived different keys")
+	}
+
+	pA, finishA = spake2Party(rand, w, true)
+	pB, finishB = spake2Party(rand, wrong, false)
+	KA, keyA = finishA(pB, pA)
+	KB, keyB = finishB(pA, pA)
+	if KA.Equal(KB) == 1 || bytes.Equal(keyA, keyB) {
+		t.Errorf("parties with different passwords derived the same key")
+	}
+
+	// A small order component added to a message doesn't change K.
+	lowOrder, err := (&edwards25519.Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pA, finishA = spake2Party(rand, w, true)
+	pB, finishB = spake2Party(rand, w, false)
+	pBTorsion := (&edwards25519.Point{}).Add(pB, lowOrder)
+	K1, _ := finishA(pB, pA)
+	K2, _ := finishA(pBTorsion, pA)
+	KB, _ = finishB(pA, pA)
+	if K1.Equal(K2) != 1 || K1.Equal(KB) != 1 {
+		t.Errorf("small order component changed the shared point")
+	}
+}
+
+func TestSPAKE2MaskUnmask(t *testing.T) {
+	x := edwards25519.NewScalar().SetUniformBytes(bytes.Repeat([]byte("x"), 64))
+	w := edwards25519.NewScalar().Add(x, edwards25519.NewScalar().SetUint64(1))
+	for _, MorN := range []*edwards25519.Point{M(), N()} {
+		masked := Mask(x, w, MorN)
+		expected := (&edwards25519.Point{}).ScalarBaseMult(x)
+		expected.Add(expected, (&edwards25519.Point{}).ScalarMult(w, MorN))
+		if masked.Equal(expected) != 1 {
+			t.Errorf("Mask does not match x * B + w * MorN")
+		}
+		if Unmask(masked, w, MorN).Equal((&edwards25519.Point{}).ScalarBaseMult(x)) != 1 {
+			t.Errorf("Unmask does not invert Mask")
+		}
+	}
+}
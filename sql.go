@@ -0,0 +1,85 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Value implements the database/sql/driver.Valuer interface, and returns the
+// canonical 32 bytes encoding of v, as returned by Bytes.
+func (v *Point) Value() (driver.Value, error) {
+	return v.Bytes(), nil
+}
+
+// Scan implements the database/sql.Scanner interface. src must be a 32 bytes
+// []byte, or a string of 64 hexadecimal digits, holding the canonical
+// encoding of a point. NULL is rejected with an error.
+//
+// If Scan returns an error, v is unchanged.
+func (v *Point) Scan(src interface{}) error {
+	b, err := scanBytes(src)
+	if err != nil {
+		return err
+	}
+	p, err := (&Point{}).SetBytes(b)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(p.Bytes(), b) {
+		return errors.New("edwards25519: non-canonical point encoding")
+	}
+	v.Set(p)
+	return nil
+}
+
+// Value implements the database/sql/driver.Valuer interface, and returns the
+// canonical 32 bytes encoding of s, as returned by Bytes.
+func (s *Scalar) Value() (driver.Value, error) {
+	return s.Bytes(), nil
+}
+
+// Scan implements the database/sql.Scanner interface. src must be a 32 bytes
+// []byte, or a string of 64 hexadecimal digits, holding the canonical
+// encoding of a scalar, as accepted by SetCanonicalBytes. NULL is rejected
+// with an error.
+//
+// If Scan returns an error, s is unchanged.
+func (s *Scalar) Scan(src interface{}) error {
+	b, err := scanBytes(src)
+	if err != nil {
+		return err
+	}
+	_, err = s.SetCanonicalBytes(b)
+	return err
+}
+
+// scanBytes returns the 32 bytes encoding held by src, a []byte or a
+// hex-encoded string.
+func scanBytes(src interface{}) ([]byte, error) {
+	var b []byte
+	switch src := src.(type) {
+	case nil:
+		return nil, errors.New("edwards25519: cannot scan NULL")
+	case []byte:
+		b = src
+	case string:
+		var err error
+		b, err = hex.DecodeString(src)
+		if err != nil {
+			return nil, fmt.Errorf("edwards25519: invalid hex encoding: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("edwards25519: cannot scan %T", src)
+	}
+	if len(b) != 32 {
+		return nil, errors.New("edwards25519: invalid encoding length")
+	}
+	return b, nil
+}
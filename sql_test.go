@@ -0,0 +1,131 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+)
+
+// echoDriver is a database/sql driver whose only statement returns a single
+// row with its arguments as columns.
+type echoDriver struct{}
+
+type echoConn struct{}
+type echoStmt struct{}
+type echoRows struct {
+	values []driver.Value
+	done   bool
+}
+
+func (echoDriver) Open(name string) (driver.Conn, error) { return echoConn{}, nil }
+
+func (echoConn) Prepare(query string) (driver.Stmt, error) { return echoStmt{}, nil }
+func (echoConn) Close() error                              { return nil }
+func (echoConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (echoStmt) Close() error  { return nil }
+func (echoStmt) NumInput() int { return -1 }
+func (echoStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (echoStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &echoRows{values: args}, nil
+}
+
+func (r *echoRows) Columns() []string {
+	return make([]string, len(r.values))
+}
+func (r *echoRows) Close() error { return nil }
+func (r *echoRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.values)
+	return nil
+}
+
+func init() {
+	sql.Register("edwards25519echo", echoDriver{})
+}
+
+func openEchoDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("edwards25519echo", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	db := openEchoDB(t)
+	p := (&Point{}).ScalarBaseMult(&dalekScalar)
+
+	var gotP Point
+	var gotS Scalar
+	if err := db.QueryRow("SELECT ?, ?", p, &dalekScalar).Scan(&gotP, &gotS); err != nil {
+		t.Fatal(err)
+	}
+	checkOnCurve(t, &gotP)
+	if gotP.Equal(p) != 1 {
+		t.Errorf("point did not round trip")
+	}
+	if gotS.Equal(&dalekScalar) != 1 {
+		t.Errorf("scalar did not round trip")
+	}
+
+	// Hex strings are accepted too.
+	if err := db.QueryRow("SELECT ?, ?", hex.EncodeToString(B.Bytes()),
+		hex.EncodeToString(scOne.Bytes())).Scan(&gotP, &gotS); err != nil {
+		t.Fatal(err)
+	}
+	if gotP.Equal(B) != 1 {
+		t.Errorf("hex point did not round trip")
+	}
+	if gotS.Equal(&scOne) != 1 {
+		t.Errorf("hex scalar did not round trip")
+	}
+}
+
+func TestSQLScanErrors(t *testing.T) {
+	db := openEchoDB(t)
+	nonCanonicalPoint := decodeHex("edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	nonCanonicalScalar := decodeHex("edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010")
+	notOnCurve := decodeHex("0200000000000000000000000000000000000000000000000000000000000000")
+	for _, tt := range []struct {
+		name string
+		p, s interface{}
+	}{
+		{"NULL", nil, nil},
+		{"truncated", B.Bytes()[:31], scOne.Bytes()[:31]},
+		{"too long", append(B.Bytes(), 0), append(scOne.Bytes(), 0)},
+		{"invalid hex", "zz" + hex.EncodeToString(B.Bytes())[2:], "zz" + hex.EncodeToString(scOne.Bytes())[2:]},
+		{"truncated hex", hex.EncodeToString(B.Bytes()[:31]), hex.EncodeToString(scOne.Bytes()[:31])},
+		{"non-canonical", nonCanonicalPoint, nonCanonicalScalar},
+		{"invalid encoding", notOnCurve, nonCanonicalScalar},
+		{"wrong type", int64(1), int64(1)},
+	} {
+		p := (&Point{}).Set(B)
+		if err := db.QueryRow("SELECT ?", tt.p).Scan(p); err == nil {
+			t.Errorf("%s: expected error scanning a point", tt.name)
+		}
+		if p.Equal(B) != 1 {
+			t.Errorf("%s: point was modified by a failed Scan", tt.name)
+		}
+		s := NewScalar().Set(&scOne)
+		if err := db.QueryRow("SELECT ?", tt.s).Scan(s); err == nil {
+			t.Errorf("%s: expected error scanning a scalar", tt.name)
+		}
+		if s.Equal(&scOne) != 1 {
+			t.Errorf("%s: scalar was modified by a failed Scan", tt.name)
+		}
+	}
+}
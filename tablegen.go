@@ -0,0 +1,118 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// A TableSourceConfig describes the Go source written by GenerateTableSource.
+type TableSourceConfig struct {
+	// Package is the name of the package of the generated file.
+	Package string
+
+	// Point is the point the tables are computed for.
+	Point *Point
+
+	// PrecomputedPoint is the name of the *PrecomputedPoint variable, or
+	// empty to skip it.
+	PrecomputedPoint string
+
+	// NafTablePoint is the name of the *NafTablePoint variable, or empty to
+	// skip it.
+	NafTablePoint string
+}
+
+// GenerateTableSource writes to w a Go source file declaring the tables for
+// a fixed point, as described by c. Each table is a variable initialized with
+// NewPrecomputedPointFromSource or NewNafTablePointFromSource from a string
+// constant holding its MarshalBinary encoding, with the name of the variable
+// followed by "Encoding".
+//
+// The cmd/gentable tool wraps this function for use with go:generate.
+func GenerateTableSource(w io.Writer, c *TableSourceConfig) error {
+	if c.Package == "" {
+		return errors.New("edwards25519: missing package name")
+	}
+	if c.PrecomputedPoint == "" && c.NafTablePoint == "" {
+		return errors.New("edwards25519: no tables to generate")
+	}
+	if c.Point == nil || c.Point.x == (fieldElement{}) && c.Point.y == (fieldElement{}) {
+		return errors.New("edwards25519: missing or uninitialized point")
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "// Code generated by gentable. DO NOT EDIT.\n\n")
+	fmt.Fprintf(bw, "package %s\n\n", c.Package)
+	fmt.Fprintf(bw, "import \"filippo.io/edwards25519\"\n")
+	encoding := hex.EncodeToString(c.Point.Bytes())
+	if c.PrecomputedPoint != "" {
+		table, _ := NewPrecomputedPoint(c.Point).MarshalBinary()
+		writeTableSource(bw, c.PrecomputedPoint, "PrecomputedPoint", encoding, table)
+	}
+	if c.NafTablePoint != "" {
+		table, _ := NewNafTablePoint(c.Point).MarshalBinary()
+		writeTableSource(bw, c.NafTablePoint, "NafTablePoint", encoding, table)
+	}
+	return bw.Flush()
+}
+
+// writeTableSource writes the declarations of the variable name of type
+// typ for the point with the given encoding, and of its encoding constant.
+func writeTableSource(w *bufio.Writer, name, typ, point string, table []byte) {
+	fmt.Fprintf(w, "\n// %s is the edwards25519.%s for the point\n", name, typ)
+	fmt.Fprintf(w, "// %s.\n", point)
+	fmt.Fprintf(w, "var %s = edwards25519.New%sFromSource(%sEncoding)\n", name, typ, name)
+	fmt.Fprintf(w, "\n// %sEncoding is the binary encoding of %s.\n", name, name)
+	writeStringConstantSource(w, name+"Encoding", table)
+}
+
+// writeStringConstantSource writes the declaration of a string constant with
+// the given name and value, split into lines of 32 bytes.
+func writeStringConstantSource(w *bufio.Writer, name string, value []byte) {
+	fmt.Fprintf(w, "const %s = \"\" +", name)
+	for i := 0; i < len(value); i += 32 {
+		if i > 0 {
+			w.WriteString(" +")
+		}
+		w.WriteString("\n\t\"")
+		for j := i; j < i+32 && j < len(value); j++ {
+			fmt.Fprintf(w, "\\x%02x", value[j])
+		}
+		w.WriteString("\"")
+	}
+	w.WriteString("\n")
+}
+
+// NewPrecomputedPointFromSource returns the PrecomputedPoint encoded in data,
+// a string constant written by GenerateTableSource.
+//
+// Unlike UnmarshalBinary, it only checks the checksum and that the field
+// elements are canonical, and not that the entries are consistent with each
+// other, because generated source is part of the program rather than an
+// input. That makes it about five times faster. It panics if data is not a
+// valid encoding, so that it can initialize package-level variables.
+func NewPrecomputedPointFromSource(data string) *PrecomputedPoint {
+	v := &PrecomputedPoint{}
+	if err := decodePrecomputedTable([]byte(data), &v.table); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// NewNafTablePointFromSource returns the NafTablePoint encoded in data, a
+// string constant written by GenerateTableSource, like
+// NewPrecomputedPointFromSource.
+func NewNafTablePointFromSource(data string) *NafTablePoint {
+	v := &NafTablePoint{}
+	if err := decodeNafTable([]byte(data), &v.table); err != nil {
+		panic(err)
+	}
+	return v
+}
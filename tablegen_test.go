@@ -0,0 +1,219 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBasepointTableSource(t *testing.T) {
+	want, err := ioutil.ReadFile("table_constants.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &bytes.Buffer{}
+	if err := writeBasepointTableSource(got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		t.Error("generated source does not match table_constants.go")
+	}
+}
+
+// generateTables runs GenerateTableSource for q, checks that the output is
+// gofmt'd, and returns the source and the values of its string constants.
+func generateTables(t *testing.T, q *Point) (string, map[string]string) {
+	buf := &bytes.Buffer{}
+	if err := GenerateTableSource(buf, &TableSourceConfig{
+		Package:          "foo",
+		Point:            q,
+		PrecomputedPoint: "qTable",
+		NafTablePoint:    "qNafTable",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	src := buf.String()
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		t.Fatalf("generated source does not parse: %v", err)
+	}
+	if string(formatted) != src {
+		t.Error("generated source is not gofmt'd")
+	}
+
+	// Evaluate the constants, which are concatenations of string literals.
+	f, err := parser.ParseFile(token.NewFileSet(), "tables.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	constants := make(map[string]string)
+	for _, decl := range f.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range d.Specs {
+			vs := spec.(*ast.ValueSpec)
+			var value strings.Builder
+			ast.Inspect(vs.Values[0], func(n ast.Node) bool {
+				if lit, ok := n.(*ast.BasicLit); ok {
+					s, err := strconv.Unquote(lit.Value)
+					if err != nil {
+						t.Fatal(err)
+					}
+					value.WriteString(s)
+				}
+				return true
+			})
+			constants[vs.Names[0].Name] = value.String()
+		}
+	}
+	return src, constants
+}
+
+func TestGenerateTableSource(t *testing.T) {
+	q := (&Point{}).ScalarBaseMult(&dalekScalar)
+	src, constants := generateTables(t, q)
+	for _, decl := range []string{
+		"var qTable = edwards25519.NewPrecomputedPointFromSource(qTableEncoding)\n",
+		"var qNafTable = edwards25519.NewNafTablePointFromSource(qNafTableEncoding)\n",
+	} {
+		if !strings.Contains(src, decl) {
+			t.Errorf("generated source does not contain %q", decl)
+		}
+	}
+
+	// The constants decode to the tables for q, also with the full checks.
+	if NewPrecomputedPointFromSource(constants["qTableEncoding"]).table != NewPrecomputedPoint(q).table {
+		t.Error("qTable does not match NewPrecomputedPoint")
+	}
+	if NewNafTablePointFromSource(constants["qNafTableEncoding"]).table != NewNafTablePoint(q).table {
+		t.Error("qNafTable does not match NewNafTablePoint")
+	}
+	if err := new(PrecomputedPoint).UnmarshalBinary([]byte(constants["qTableEncoding"])); err != nil {
+		t.Error(err)
+	}
+	if err := new(NafTablePoint).UnmarshalBinary([]byte(constants["qNafTableEncoding"])); err != nil {
+		t.Error(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := GenerateTableSource(buf, &TableSourceConfig{Package: "foo", Point: q}); err == nil {
+		t.Error("GenerateTableSource without tables did not fail")
+	}
+	for i, p := range []*Point{nil, {}} {
+		if err := GenerateTableSource(buf, &TableSourceConfig{
+			Package: "foo", Point: p, PrecomputedPoint: "qTable",
+		}); err == nil {
+			t.Errorf("GenerateTableSource with missing point #%d did not fail", i)
+		}
+	}
+}
+
+// TestGenerateTableSourceBasepoint checks that the tables GenerateTableSource
+// writes for B hold the same entries as the basepoint tables. The generated
+// source itself is different from table_constants.go, which declares the
+// tables with the internal types, and is checked by TestBasepointTableSource.
+func TestGenerateTableSourceBasepoint(t *testing.T) {
+	_, constants := generateTables(t, B)
+	if NewPrecomputedPointFromSource(constants["qTableEncoding"]).table != basepointTable {
+		t.Error("the PrecomputedPoint for B does not match basepointTable")
+	}
+	if NewNafTablePointFromSource(constants["qNafTableEncoding"]).table != basepointNafTable {
+		t.Error("the NafTablePoint for B does not match basepointNafTable")
+	}
+}
+
+func TestNewPrecomputedPointFromSourceCorrupted(t *testing.T) {
+	data, _ := NewPrecomputedPoint(B).MarshalBinary()
+	data[tableHeaderSize] ^= 1
+	defer func() {
+		if recover() == nil {
+			t.Error("corrupted source did not panic")
+		}
+	}()
+	NewPrecomputedPointFromSource(string(data))
+}
+
+func BenchmarkPrecomputedPointFromSource(b *testing.B) {
+	data, _ := NewPrecomputedPoint(B).MarshalBinary()
+	src := string(data)
+	b.Run("UnmarshalBinary", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			new(PrecomputedPoint).UnmarshalBinary(data)
+		}
+	})
+	b.Run("FromSource", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			NewPrecomputedPointFromSource(src)
+		}
+	})
+}
+
+// writeBasepointTableSource writes the source of table_constants.go to w.
+func writeBasepointTableSource(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`// Copyright (c) 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !ed25519_smalltables
+
+package edwards25519
+
+var (
+	basepointTable = [32]affineLookupTable{
+`)
+	table := NewPrecomputedPoint(NewGeneratorPoint())
+	for i := range table.table {
+		bw.WriteString("\t\t{points: [8]affineCached{")
+		for j := range table.table[i].points {
+			if j > 0 {
+				bw.WriteString(", ")
+			}
+			writeAffineCachedSource(bw, &table.table[i].points[j])
+		}
+		bw.WriteString("}},\n")
+	}
+	bw.WriteString("\t}\n")
+
+	nafTable := NewNafTablePoint(NewGeneratorPoint())
+	bw.WriteString("\tbasepointNafTable = nafLookupTable8{points: [64]affineCached{")
+	for i := range nafTable.table.points {
+		if i > 0 {
+			bw.WriteString(", ")
+		}
+		writeAffineCachedSource(bw, &nafTable.table.points[i])
+	}
+	bw.WriteString("}}\n)\n")
+	return bw.Flush()
+}
+
+// writeAffineCachedSource writes p as a Go composite literal of type
+// affineCached.
+func writeAffineCachedSource(w *bufio.Writer, p *affineCached) {
+	names := []string{"YplusX: fieldElement", "YminusX: fieldElement", "T2d: fieldElement"}
+	w.WriteString("{")
+	for i, fe := range []*fieldElement{&p.YplusX, &p.YminusX, &p.T2d} {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteString(names[i])
+		fmt.Fprintf(w, "{%#x, %#x, %#x, %#x, %#x}", fe.l0, fe.l1, fe.l2, fe.l3, fe.l4)
+	}
+	w.WriteString("}")
+}
@@ -28,6 +28,11 @@ type nafLookupTable8 struct {
 	points [64]affineCached
 }
 
+// A larger precomputed lookup table for fixed-base, variable-time scalar muls.
+type nafLookupTable10 struct {
+	points [256]affineCached
+}
+
 // Constructors.
 
 // Builds a lookup table at runtime. Fast.
@@ -84,10 +89,42 @@ func (v *nafLookupTable8) FromP3(q *Point) {
 	}
 }
 
+// This is not optimised for speed; affine tables should be precomputed.
+func (v *nafLookupTable10) FromP3(q *Point) {
+	v.points[0].FromP3(q)
+	q2 := Point{}
+	q2.Add(q, q)
+	tmpP3 := Point{}
+	tmpP1xP1 := projP1xP1{}
+	for i := 0; i < 255; i++ {
+		v.points[i+1].FromP3(tmpP3.fromP1xP1(tmpP1xP1.AddAffine(&q2, &v.points[i])))
+	}
+}
+
+// nafTableSize returns the number of entries in a width-w NAF lookup table.
+func nafTableSize(w uint) int {
+	return 1 << (w - 2)
+}
+
+// fillNafTable sets points[i] = (2*i+1)*Q, i.e., Q, 3Q, 5Q, ..., like
+// nafLookupTable5.FromP3 but for a table of any size. A table of size
+// nafTableSize(w) allows lookup of all width-w NAF digits.
+func fillNafTable(points []projCached, q *Point) {
+	points[0].FromP3(q)
+	q2 := Point{}
+	q2.Add(q, q)
+	tmpP3 := Point{}
+	tmpP1xP1 := projP1xP1{}
+	for i := 0; i < len(points)-1; i++ {
+		points[i+1].FromP3(tmpP3.fromP1xP1(tmpP1xP1.Add(&q2, &points[i])))
+	}
+}
+
 // Selectors.
 
 // Set dest to x*Q, where -8 <= x <= 8, in constant time.
 func (v *projLookupTable) SelectInto(dest *projCached, x int8) {
+	ctPoisonIndex(&x)
 	// Compute xabs = |x|
 	xmask := x >> 7
 	xabs := uint8((x + xmask) ^ xmask)
@@ -104,6 +141,7 @@ func (v *projLookupTable) SelectInto(dest *projCached, x int8) {
 
 // Set dest to x*Q, where -8 <= x <= 8, in constant time.
 func (v *affineLookupTable) SelectInto(dest *affineCached, x int8) {
+	ctPoisonIndex(&x)
 	// Compute xabs = |x|
 	xmask := x >> 7
 	xabs := uint8((x + xmask) ^ xmask)
@@ -5,6 +5,7 @@
 package edwards25519
 
 import (
+	"crypto/subtle"
 	"testing"
 )
 
@@ -117,3 +118,79 @@ func TestNafLookupTable8(t *testing.T) {
 		t.Errorf("Sanity check on nafLookupTable8 failed")
 	}
 }
+
+// A larger dynamic lookup table for variable-base, constant-time scalar muls
+// with radix-32 digits.
+type projLookupTable16 struct {
+	points [16]projCached
+}
+
+// Builds a lookup table at runtime. Fast.
+func (v *projLookupTable16) FromP3(q *Point) {
+	// Goal: v.points[i] = (i+1)*Q, i.e., Q, 2Q, ..., 16Q
+	// This allows lookup of -16Q, ..., -Q, 0, Q, ..., 16Q
+	v.points[0].FromP3(q)
+	tmpP3 := Point{}
+	tmpP1xP1 := projP1xP1{}
+	for i := 0; i < 15; i++ {
+		v.points[i+1].FromP3(tmpP3.fromP1xP1(tmpP1xP1.Add(q, &v.points[i])))
+	}
+}
+
+// Set dest to x*Q, where -16 <= x <= 16, in constant time.
+func (v *projLookupTable16) SelectInto(dest *projCached, x int8) {
+	ctPoisonIndex(&x)
+	// Compute xabs = |x|
+	xmask := x >> 7
+	xabs := uint8((x + xmask) ^ xmask)
+
+	dest.Zero()
+	for j := 1; j <= 16; j++ {
+		// Set dest = j*Q if |x| = j
+		cond := subtle.ConstantTimeByteEq(xabs, uint8(j))
+		dest.Select(&v.points[j-1], dest, cond)
+	}
+	// Now dest = |x|*Q, conditionally negate to get x*Q
+	dest.CondNeg(int(xmask & 1))
+}
+
+// A wide dynamic lookup table for variable-base, variable-time scalar muls,
+// used by varTimeDoubleScalarBaseMultWide to measure width 8 against width 5.
+type projNafLookupTable8 struct {
+	points [64]projCached
+}
+
+// Builds a lookup table at runtime. Faster than nafLookupTable8, but the
+// resulting additions are slower.
+func (v *projNafLookupTable8) FromP3(q *Point) {
+	// Goal: v.points[i] = (2*i+1)*Q, i.e., Q, 3Q, 5Q, ..., 127Q
+	// This allows lookup of -127Q, ..., -3Q, -Q, 0, Q, 3Q, ..., 127Q
+	fillNafTable(v.points[:], q)
+}
+
+// Given odd x with 0 < x < 2^7, return x*Q (in variable time).
+func (v *projNafLookupTable8) SelectInto(dest *projCached, x int8) {
+	*dest = v.points[x/2]
+}
+
+func TestProjNafLookupTable8(t *testing.T) {
+	var table projNafLookupTable8
+	table.FromP3(B)
+
+	// Check every odd digit against a naive x*B computation.
+	var multiple projCached
+	var accP1xP1 projP1xP1
+	for x := int8(1); x > 0; x += 2 {
+		table.SelectInto(&multiple, x)
+		got := NewIdentityPoint()
+		got.fromP1xP1(accP1xP1.Add(got, &multiple))
+
+		want := NewIdentityPoint()
+		for i := int8(0); i < x; i++ {
+			want.Add(want, B)
+		}
+		if got.Equal(want) != 1 {
+			t.Errorf("projNafLookupTable8.SelectInto(%d) != %d*B", x, x)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package vrf implements the ECVRF-EDWARDS25519-SHA512-TAI and
+// ECVRF-EDWARDS25519-SHA512-ELL2 verifiable random functions of RFC 9381.
+//
+// Keys are Ed25519 keys, as in RFC 8032. A proof pi for an input alpha can be
+// verified by anyone holding the public key, and it determines the VRF output
+// beta, returned by ProofToHash and Verify.
+package vrf
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"errors"
+
+	"filippo.io/edwards25519"
+)
+
+const (
+	// ProofSize is the size, in bytes, of proofs.
+	ProofSize = 32 + challengeSize + 32
+
+	// HashSize is the size, in bytes, of VRF outputs.
+	HashSize = sha512.Size
+
+	// challengeSize is cLen, the size of the challenge in a proof.
+	challengeSize = 16
+
+	suiteTAI  = 0x03
+	suiteELL2 = 0x04
+)
+
+// A Suite is an ECVRF ciphersuite.
+type Suite struct {
+	id            byte
+	encodeToCurve func(salt, alpha []byte) (*edwards25519.Point, error)
+}
+
+var (
+	// TAI is ECVRF-EDWARDS25519-SHA512-TAI, which hashes to the curve with
+	// the try-and-increment method. Its running time depends on alpha.
+	TAI = &Suite{id: suiteTAI, encodeToCurve: EncodeToCurveTAI}
+
+	// ELL2 is ECVRF-EDWARDS25519-SHA512-ELL2, which hashes to the curve with
+	// the Elligator 2 method of RFC 9380.
+	ELL2 = &Suite{id: suiteELL2, encodeToCurve: EncodeToCurveELL2}
+)
+
+// EncodeToCurveTAI hashes the salt and alpha to a point with the
+// try-and-increment method of RFC 9381, Section 5.4.1.1. The salt is the
+// encoded public key for ECVRF-EDWARDS25519-SHA512-TAI.
+//
+// It returns an error only if no valid point is found in 256 attempts, which
+// happens with probability about 2^-256.
+func EncodeToCurveTAI(salt, alpha []byte) (*edwards25519.Point, error) {
+	h := sha512.New()
+	for ctr := 0; ctr < 256; ctr++ {
+		h.Reset()
+		h.Write([]byte{suiteTAI, 0x01})
+		h.Write(salt)
+		h.Write(alpha)
+		h.Write([]byte{byte(ctr), 0x00})
+		p, err := stringToPoint(h.Sum(nil)[:32])
+		if err != nil {
+			continue
+		}
+		p.MultByCofactor(p)
+		if p.Equal(edwards25519.NewIdentityPoint()) == 1 {
+			continue
+		}
+		return p, nil
+	}
+	return nil, errors.New("vrf: try-and-increment failed to find a point")
+}
+
+// ell2DST is the hash to curve domain separation tag of the ELL2 suite,
+// "ECVRF_" || h2c_suite_ID_string || suite_string.
+var ell2DST = []byte("ECVRF_edwards25519_XMD:SHA-512_ELL2_NU_\x04")
+
+// EncodeToCurveELL2 hashes the salt and alpha to a point with the
+// edwards25519_XMD:SHA-512_ELL2_NU_ suite of RFC 9380, as specified in RFC
+// 9381, Section 5.4.1.2. The salt is the encoded public key for
+// ECVRF-EDWARDS25519-SHA512-ELL2. It never returns an error.
+func EncodeToCurveELL2(salt, alpha []byte) (*edwards25519.Point, error) {
+	msg := make([]byte, 0, len(salt)+len(alpha))
+	msg = append(msg, salt...)
+	msg = append(msg, alpha...)
+	return edwards25519.EncodeToCurve(msg, ell2DST), nil
+}
+
+// Prove returns the proof pi for alpha by the private key priv, as specified
+// in RFC 9381, Section 5.1.
+func (s *Suite) Prove(priv ed25519.PrivateKey, alpha []byte) ([]byte, error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		return nil, errors.New("vrf: invalid private key length")
+	}
+	hashedSK := sha512.Sum512(priv.Seed())
+	x := edwards25519.NewScalar().SetBytesWithClamping(hashedSK[:32])
+	Y := (&edwards25519.Point{}).ScalarBaseMult(x)
+	pk := Y.Bytes()
+
+	H, err := s.encodeToCurve(pk, alpha)
+	if err != nil {
+		return nil, err
+	}
+	hString := H.Bytes()
+	Gamma := (&edwards25519.Point{}).ScalarMult(x, H)
+
+	// The nonce is derived as in RFC 8032, Section 5.1.6, from the second
+	// half of the hashed secret key and the encoded H.
+	nonce := sha512.New()
+	nonce.Write(hashedSK[32:])
+	nonce.Write(hString)
+	k := edwards25519.NewScalar().SetUniformBytes(nonce.Sum(nil))
+
+	U := (&edwards25519.Point{}).ScalarBaseMult(k)
+	V := (&edwards25519.Point{}).ScalarMult(k, H)
+	c := s.challenge(Y, H, Gamma, U, V)
+	sc := edwards25519.NewScalar().MultiplyAdd(c, x, k)
+
+	pi := make([]byte, 0, ProofSize)
+	pi = append(pi, Gamma.Bytes()...)
+	pi = append(pi, c.Bytes()[:challengeSize]...)
+	pi = append(pi, sc.Bytes()...)
+	return pi, nil
+}
+
+// Verify checks the proof pi for alpha by the public key pub, as specified in
+// RFC 9381, Section 5.3, and returns the VRF output beta if it is valid.
+//
+// Public keys of small order are rejected, as in ECVRF_validate_key, so the
+// VRF is fully unique and collision resistant. Non-canonical encodings of the
+// public key and of Gamma are rejected.
+func (s *Suite) Verify(pub ed25519.PublicKey, pi, alpha []byte) ([]byte, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, errors.New("vrf: invalid public key length")
+	}
+	Y, err := stringToPoint(pub)
+	if err != nil {
+		return nil, err
+	}
+	if (&edwards25519.Point{}).MultByCofactor(Y).Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return nil, errors.New("vrf: small order public key")
+	}
+	Gamma, c, sc, err := decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	H, err := s.encodeToCurve(pub, alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	// U = s*B - c*Y and V = s*H - c*Gamma
+	minusC := edwards25519.NewScalar().Negate(c)
+	U := (&edwards25519.Point{}).VarTimeDoubleScalarBaseMult(minusC, Y, sc)
+	V := (&edwards25519.Point{}).VarTimeMultiScalarMult(
+		[]*edwards25519.Scalar{sc, minusC}, []*edwards25519.Point{H, Gamma})
+
+	if s.challenge(Y, H, Gamma, U, V).Equal(c) != 1 {
+		return nil, errors.New("vrf: invalid proof")
+	}
+	return s.proofToHash(Gamma), nil
+}
+
+// ProofToHash returns the VRF output beta for the proof pi, as specified in
+// RFC 9381, Section 5.2. It doesn't verify pi, and must only be used on
+// proofs that were verified with Verify, or produced with Prove.
+func (s *Suite) ProofToHash(pi []byte) ([]byte, error) {
+	Gamma, _, _, err := decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	return s.proofToHash(Gamma), nil
+}
+
+func (s *Suite) proofToHash(Gamma *edwards25519.Point) []byte {
+	h := sha512.New()
+	h.Write([]byte{s.id, 0x03})
+	h.Write((&edwards25519.Point{}).MultByCofactor(Gamma).Bytes())
+	h.Write([]byte{0x00})
+	return h.Sum(nil)
+}
+
+// challenge implements ECVRF_challenge_generation from RFC 9381, Section
+// 5.4.3.
+func (s *Suite) challenge(points ...*edwards25519.Point) *edwards25519.Scalar {
+	h := sha512.New()
+	h.Write([]byte{s.id, 0x02})
+	for _, p := range points {
+		h.Write(p.Bytes())
+	}
+	h.Write([]byte{0x00})
+	var c [32]byte
+	copy(c[:], h.Sum(nil)[:challengeSize])
+	sc, err := edwards25519.NewScalar().SetCanonicalBytes(c[:])
+	if err != nil {
+		panic("vrf: internal error: 128-bit challenge is not canonical")
+	}
+	return sc
+}
+
+// decodeProof implements ECVRF_decode_proof from RFC 9381, Section 5.4.4.
+func decodeProof(pi []byte) (Gamma *edwards25519.Point, c, s *edwards25519.Scalar, err error) {
+	if len(pi) != ProofSize {
+		return nil, nil, nil, errors.New("vrf: invalid proof length")
+	}
+	Gamma, err = stringToPoint(pi[:32])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var cBytes [32]byte
+	copy(cBytes[:], pi[32:32+challengeSize])
+	c, err = edwards25519.NewScalar().SetCanonicalBytes(cBytes[:])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	s, err = edwards25519.NewScalar().SetCanonicalBytes(pi[32+challengeSize:])
+	if err != nil {
+		return nil, nil, nil, errors.New("vrf: non-canonical proof scalar")
+	}
+	return Gamma, c, s, nil
+}
+
+// stringToPoint decodes a point as specified in RFC 8032, Section 5.1.3,
+// which rejects non-canonical encodings, unlike Point.SetBytes.
+func stringToPoint(b []byte) (*edwards25519.Point, error) {
+	p, err := (&edwards25519.Point{}).SetBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(p.Bytes(), b) {
+		return nil, errors.New("vrf: non-canonical point encoding")
+	}
+	return p, nil
+}
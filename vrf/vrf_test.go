@@ -0,0 +1,200 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vrf
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+)
+
+// vectors are the test vectors from RFC 9381, Appendix B.
+var vectors = []struct {
+	suite         *Suite
+	sk, pk, alpha string
+	h, pi, beta   string
+}{
+	// ECVRF-EDWARDS25519-SHA512-TAI, Appendix B.1.
+	{
+		TAI,
+		"9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		"",
+		"91bbed02a99461df1ad4c6564a5f5d829d0b90cfc7903e7a5797bd658abf3318",
+		"8657106690b5526245a92b003bb079ccd1a92130477671f6fc01ad16f26f723f26f8a57ccaed74ee1b190bed1f479d9727d2d0f9b005a6e456a35d4fb0daab1268a1b0db10836d9826a528ca76567805",
+		"90cf1df3b703cce59e2a35b925d411164068269d7b2d29f3301c03dd757876ff66b71dda49d2de59d03450451af026798e8f81cd2e333de5cdf4f3e140fdd8ae",
+	},
+	{
+		TAI,
+		"4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+		"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		"72",
+		"5b659fc3d4e9263fd9a4ed1d022d75eaacc20df5e09f9ea937502396598dc551",
+		"f3141cd382dc42909d19ec5110469e4feae18300e94f304590abdced48aed5933bf0864a62558b3ed7f2fea45c92a465301b3bbf5e3e54ddf2d935be3b67926da3ef39226bbc355bdc9850112c8f4b02",
+		"eb4440665d3891d668e7e0fcaf587f1b4bd7fbfe99d0eb2211ccec90496310eb5e33821bc613efb94db5e5b54c70a848a0bef4553a41befc57663b56373a5031",
+	},
+	{
+		TAI,
+		"c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+		"fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+		"af82",
+		"bf4339376f5542811de615e3313d2b36f6f53c0acfebb482159711201192576a",
+		"9bc0f79119cc5604bf02d23b4caede71393cedfbb191434dd016d30177ccbf8096bb474e53895c362d8628ee9f9ea3c0e52c7a5c691b6c18c9979866568add7a2d41b00b05081ed0f58ee5e31b3a970e",
+		"645427e5d00c62a23fb703732fa5d892940935942101e456ecca7bb217c61c452118fec1219202a0edcf038bb6373241578be7217ba85a2687f7a0310b2df19f",
+	},
+	// ECVRF-EDWARDS25519-SHA512-ELL2, Appendix B.2.
+	{
+		ELL2,
+		"9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+		"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+		"",
+		"b8066ebbb706c72b64390324e4a3276f129569eab100c26b9f05011200c1bad9",
+		"7d9c633ffeee27349264cf5c667579fc583b4bda63ab71d001f89c10003ab46f14adf9a3cd8b8412d9038531e865c341cafa73589b023d14311c331a9ad15ff2fb37831e00f0acaa6d73bc9997b06501",
+		"9d574bf9b8302ec0fc1e21c3ec5368269527b87b462ce36dab2d14ccf80c53cccf6758f058c5b1c856b116388152bbe509ee3b9ecfe63d93c3b4346c1fbc6c54",
+	},
+	{
+		ELL2,
+		"4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb",
+		"3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c",
+		"72",
+		"76ac3ccb86158a9104dff819b1ca293426d305fd76b39b13c9356d9b58c08e57",
+		"47b327393ff2dd81336f8a2ef10339112401253b3c714eeda879f12c509072ef055b48372bb82efbdce8e10c8cb9a2f9d60e93908f93df1623ad78a86a028d6bc064dbfc75a6a57379ef855dc6733801",
+		"38561d6b77b71d30eb97a062168ae12b667ce5c28caccdf76bc88e093e4635987cd96814ce55b4689b3dd2947f80e59aac7b7675f8083865b46c89b2ce9cc735",
+	},
+	{
+		ELL2,
+		"c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+		"fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+		"af82",
+		"13d2a8b5ca32db7e98094a61f656a08c6c964344e058879a386a947a4e189ed1",
+		"926e895d308f5e328e7aa159c06eddbe56d06846abf5d98c2512235eaa57fdce35b46edfc655bc828d44ad09d1150f31374e7ef73027e14760d42e77341fe05467bb286cc2c9d7fde29120a0b2320d04",
+		"121b7f9b9aaaa29099fc04a94ba52784d44eac976dd1a3cca458733be5cd090a7b5fbd148444f17f8daf1fb55cb04b1ae85a626e30a54b4b0f8abf4a43314a58",
+	},
+}
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestVectors(t *testing.T) {
+	for i, v := range vectors {
+		priv := ed25519.NewKeyFromSeed(decodeHex(v.sk))
+		pk, alpha := decodeHex(v.pk), decodeHex(v.alpha)
+		if !bytes.Equal(priv.Public().(ed25519.PublicKey), pk) {
+			t.Fatalf("#%d: wrong public key", i)
+		}
+
+		H, err := v.suite.encodeToCurve(pk, alpha)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(H.Bytes()); got != v.h {
+			t.Errorf("#%d: H = %s, expected %s", i, got, v.h)
+		}
+
+		pi, err := v.suite.Prove(priv, alpha)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(pi); got != v.pi {
+			t.Errorf("#%d: pi = %s, expected %s", i, got, v.pi)
+		}
+
+		beta, err := v.suite.ProofToHash(pi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(beta); got != v.beta {
+			t.Errorf("#%d: ProofToHash = %s, expected %s", i, got, v.beta)
+		}
+
+		beta, err = v.suite.Verify(pk, decodeHex(v.pi), alpha)
+		if err != nil {
+			t.Errorf("#%d: valid proof was rejected: %v", i, err)
+		}
+		if got := hex.EncodeToString(beta); got != v.beta {
+			t.Errorf("#%d: Verify = %s, expected %s", i, got, v.beta)
+		}
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	for i, v := range vectors {
+		pk, pi, alpha := decodeHex(v.pk), decodeHex(v.pi), decodeHex(v.alpha)
+
+		// Flipping any bit of the proof invalidates it.
+		for j := 0; j < len(pi)*8; j++ {
+			mutated := append([]byte{}, pi...)
+			mutated[j/8] ^= 1 << (j % 8)
+			if _, err := v.suite.Verify(pk, mutated, alpha); err == nil {
+				t.Errorf("#%d: proof with bit %d flipped was accepted", i, j)
+			}
+		}
+
+		if _, err := v.suite.Verify(pk, pi, append(alpha, 0)); err == nil {
+			t.Errorf("#%d: proof for a different alpha was accepted", i)
+		}
+		if _, err := v.suite.Verify(decodeHex(vectors[(i+1)%3].pk), pi, alpha); err == nil {
+			t.Errorf("#%d: proof for a different public key was accepted", i)
+		}
+		other := ELL2
+		if v.suite == ELL2 {
+			other = TAI
+		}
+		if _, err := other.Verify(pk, pi, alpha); err == nil {
+			t.Errorf("#%d: proof was accepted by the other suite", i)
+		}
+		if _, err := v.suite.Verify(pk, pi[:ProofSize-1], alpha); err == nil {
+			t.Errorf("#%d: truncated proof was accepted", i)
+		}
+
+		// p itself is a non-canonical encoding of y = 0, which is a valid
+		// point of order 4, as Gamma.
+		nonCanonical := append([]byte{}, pi...)
+		copy(nonCanonical, decodeHex("edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f"))
+		if _, err := v.suite.Verify(pk, nonCanonical, alpha); err == nil {
+			t.Errorf("#%d: proof with non-canonical Gamma was accepted", i)
+		}
+		if _, err := v.suite.ProofToHash(nonCanonical); err == nil {
+			t.Errorf("#%d: ProofToHash accepted non-canonical Gamma", i)
+		}
+
+		// A small order public key is rejected.
+		smallOrder := decodeHex("0000000000000000000000000000000000000000000000000000000000000000")
+		if _, err := v.suite.Verify(smallOrder, pi, alpha); err == nil {
+			t.Errorf("#%d: small order public key was accepted", i)
+		}
+	}
+}
+
+func TestProveVerify(t *testing.T) {
+	for _, suite := range []*Suite{TAI, ELL2} {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, alpha := range []string{"", "a", "sample input"} {
+			pi, err := suite.Prove(priv, []byte(alpha))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pi) != ProofSize {
+				t.Errorf("proof is %d bytes, expected %d", len(pi), ProofSize)
+			}
+			beta, err := suite.Verify(pub, pi, []byte(alpha))
+			if err != nil {
+				t.Errorf("%q: proof was rejected: %v", alpha, err)
+			}
+			if len(beta) != HashSize {
+				t.Errorf("output is %d bytes, expected %d", len(beta), HashSize)
+			}
+		}
+	}
+}
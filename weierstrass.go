@@ -0,0 +1,113 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import "errors"
+
+// Wei25519 is the short Weierstrass model y² = x³ + ax + b of Curve25519 and
+// edwards25519, specified in draft-ietf-lwig-curve-representations, with
+//
+//     a = 0x2aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa984914a144
+//     b = 0x7b425ed097b425ed097b425ed097b425ed097b425ed097b4260b5e9c7710c864
+//
+// A point (u, v) on Curve25519 maps to (u + A/3, v) on Wei25519, and a point
+// (x, y) on edwards25519 maps to the Curve25519 point
+//
+//     u = (1 + y) / (1 - y)
+//     v = sqrt(-486664) u / x
+//
+// as in RFC 7748, Section 4.1. The square root is the one that maps the
+// edwards25519 basepoint to the Curve25519 basepoint of RFC 7748, which is the
+// opposite of the one used for hashing to the curve in RFC 9380.
+
+// weierstrassShift is A/3, the difference between Wei25519 and Curve25519
+// x-coordinates.
+var weierstrassShift = func() *fieldElement {
+	var inv3 fieldElement
+	inv3.Invert(&fieldElement{3, 0, 0, 0, 0})
+	return new(fieldElement).Multiply(montgomeryA, &inv3)
+}()
+
+// sqrtMinusAPlus2RFC7748 is the square root of -486664 used in RFC 7748, the
+// negative one.
+var sqrtMinusAPlus2RFC7748 = new(fieldElement).Negate(sqrtMinusAPlus2)
+
+// WeierstrassCoordinates returns the affine coordinates of v on Wei25519, as
+// canonical 32 bytes little-endian encodings, like BytesMontgomery.
+//
+// The identity maps to the point at infinity, which has no affine coordinates,
+// so WeierstrassCoordinates returns an error for it. The point of order two,
+// (0, -1), would map to the Curve25519 point (0, 0) if not for the division by
+// x = 0, and is mapped there explicitly, resulting in (A/3, 0).
+func (v *Point) WeierstrassCoordinates() (x, y []byte, err error) {
+	checkInitialized(v)
+
+	// u = (Z + Y) / (Z - Y)
+	// v = sqrt(-486664) u Z / X
+	var num, den, u, w fieldElement
+	num.Add(&v.z, &v.y)
+	den.Subtract(&v.z, &v.y)
+	if den.Equal(feZero) == 1 {
+		return nil, nil, errors.New("edwards25519: the identity has no Weierstrass coordinates")
+	}
+	u.Multiply(&num, den.Invert(&den))
+	// When X = 0 and Y = -Z, Invert returns zero, and v is zero as required.
+	w.Multiply(&u, &v.z)
+	w.Multiply(&w, new(fieldElement).Invert(&v.x))
+	w.Multiply(&w, sqrtMinusAPlus2RFC7748)
+
+	u.Add(&u, weierstrassShift)
+	return u.Bytes(), w.Bytes(), nil
+}
+
+// SetWeierstrassCoordinates sets v to the point with Wei25519 affine
+// coordinates x and y, as returned by WeierstrassCoordinates, and returns v.
+// If x or y are not canonical 32 bytes encodings, or if (x, y) is not on
+// Wei25519, SetWeierstrassCoordinates returns nil and an error, and the
+// receiver is unchanged.
+func (v *Point) SetWeierstrassCoordinates(x, y []byte) (*Point, error) {
+	var u, w fieldElement
+	if _, err := u.setCanonicalBytes(x); err != nil {
+		return nil, err
+	}
+	if _, err := w.setCanonicalBytes(y); err != nil {
+		return nil, err
+	}
+	u.Subtract(&u, weierstrassShift)
+
+	// Check that v² = u³ + A u² + u on Curve25519, which is equivalent to the
+	// Wei25519 equation.
+	var lhs, rhs fieldElement
+	lhs.Square(&w)
+	rhs.Add(&u, montgomeryA)
+	rhs.Multiply(&rhs, &u)
+	rhs.Add(&rhs, feOne)
+	rhs.Multiply(&rhs, &u)
+	if lhs.Equal(&rhs) != 1 {
+		return nil, errors.New("edwards25519: point is not on the Weierstrass curve")
+	}
+
+	// (0, 0) is the only point of order two on Curve25519, and maps to (0, -1).
+	if u.Equal(feZero) == 1 {
+		v.x.Zero()
+		v.y.Negate(feOne)
+		v.z.One()
+		v.t.Zero()
+		return v, nil
+	}
+
+	// The rational map (sqrt(-486664) u / v, (u - 1) / (u + 1)) in projective
+	// coordinates, as in mapToCurveElligator2. Here v is not zero, and u is not
+	// -1, because A - 2 is not a square, so Z is not zero.
+	var sum, diff, t fieldElement
+	sum.Add(&u, feOne)
+	diff.Subtract(&u, feOne)
+	t.Multiply(sqrtMinusAPlus2RFC7748, &u)
+	v.x.Multiply(&t, &sum)
+	v.t.Multiply(&t, &diff)
+	v.y.Multiply(&w, &diff)
+	v.z.Multiply(&w, &sum)
+	return v, nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"testing"
+	"testing/quick"
+)
+
+// decodeHexBigEndian decodes a big-endian hex number into a little-endian
+// encoding.
+func decodeHexBigEndian(s string) []byte {
+	b := decodeHex(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+func TestWeierstrassBasepoint(t *testing.T) {
+	// The Wei25519 generator from draft-ietf-lwig-curve-representations,
+	// Appendix E.3.
+	wantX := decodeHexBigEndian("2aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaad245a")
+	wantY := decodeHexBigEndian("20ae19a1b8a086b4e01edd2c7748d14c923d4d7e6d7c61b229e9c5a27eced3d9")
+	x, y, err := B.WeierstrassCoordinates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(x, wantX) || !bytes.Equal(y, wantY) {
+		t.Errorf("got (%x, %x), expected (%x, %x)", x, y, wantX, wantY)
+	}
+	p, err := (&Point{}).SetWeierstrassCoordinates(wantX, wantY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOnCurve(t, p)
+	if p.Equal(B) != 1 {
+		t.Errorf("generator does not map back to the basepoint")
+	}
+}
+
+func TestWeierstrassRoundTrip(t *testing.T) {
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTrip := func(s Scalar, torsion uint8) bool {
+		p := (&Point{}).ScalarBaseMult(&s)
+		if p.Equal(NewIdentityPoint()) == 1 {
+			return true
+		}
+		for i := uint8(0); i < torsion%8; i++ {
+			p.Add(p, lowOrder)
+		}
+		x, y, err := p.WeierstrassCoordinates()
+		if err != nil {
+			return false
+		}
+		q, err := (&Point{}).SetWeierstrassCoordinates(x, y)
+		if err != nil {
+			return false
+		}
+		checkOnCurve(t, q)
+		return q.Equal(p) == 1
+	}
+	if err := quick.Check(roundTrip, quickCheckConfig32); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWeierstrassExceptional(t *testing.T) {
+	if _, _, err := NewIdentityPoint().WeierstrassCoordinates(); err == nil {
+		t.Errorf("expected error for the identity")
+	}
+
+	// The point of order two maps to (A/3, 0).
+	minusOne := decodeHex("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	orderTwo, err := (&Point{}).SetBytes(minusOne)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y, err := orderTwo.WeierstrassCoordinates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(x, weierstrassShift.Bytes()) || !bytes.Equal(y, make([]byte, 32)) {
+		t.Errorf("got (%x, %x), expected (A/3, 0)", x, y)
+	}
+	p, err := (&Point{}).SetWeierstrassCoordinates(x, y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkOnCurve(t, p)
+	if p.Equal(orderTwo) != 1 {
+		t.Errorf("(A/3, 0) does not map back to (0, -1)")
+	}
+}
+
+func TestSetWeierstrassCoordinatesInvalid(t *testing.T) {
+	x, y, err := B.WeierstrassCoordinates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	offCurve := append([]byte{}, y...)
+	offCurve[0] ^= 1
+	nonCanonical := decodeHex("edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	for _, tt := range []struct {
+		name string
+		x, y []byte
+	}{
+		{"off curve", x, offCurve},
+		{"zero", make([]byte, 32), make([]byte, 32)},
+		{"short x", x[:31], y},
+		{"short y", x, y[:31]},
+		{"non-canonical", nonCanonical, y},
+	} {
+		p := (&Point{}).Set(B)
+		if _, err := p.SetWeierstrassCoordinates(tt.x, tt.y); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+		if p.Equal(B) != 1 {
+			t.Errorf("%s: receiver was modified", tt.name)
+		}
+	}
+}
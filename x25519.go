@@ -0,0 +1,215 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+// X25519PublicKeyFromPoint returns the X25519 public key corresponding to the
+// Ed25519 public key p, that is, the u-coordinate of the birationally
+// equivalent point on Curve25519, as a 32 bytes encoding according to RFC 7748.
+//
+// X25519PublicKeyFromPoint returns an error if p is the identity or has small
+// order, as Diffie-Hellman with such a key has a predictable result. To
+// convert any point, including those, use BytesMontgomery.
+func X25519PublicKeyFromPoint(p *Point) ([]byte, error) {
+	checkInitialized(p)
+	if p.varTimeIsIdentity() {
+		return nil, errors.New("edwards25519: identity point has no X25519 public key")
+	}
+	if (&Point{}).MultByCofactor(p).varTimeIsIdentity() {
+		return nil, errors.New("edwards25519: small order point has no X25519 public key")
+	}
+	return p.BytesMontgomery(), nil
+}
+
+// A BatchError is returned by the batch conversion functions when some of the
+// inputs are rejected. The other inputs are still converted.
+type BatchError struct {
+	// Errors has an entry for each input, which is nil if the input was
+	// converted successfully.
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	n := 0
+	for _, err := range e.Errors {
+		if err != nil {
+			n++
+		}
+	}
+	return fmt.Sprintf("edwards25519: %d of %d inputs rejected", n, len(e.Errors))
+}
+
+// BatchX25519FromPoints is like X25519PublicKeyFromPoint for each point, but
+// shares a single field inversion across all of them.
+//
+// If any point is rejected, the corresponding entry of the result is nil and
+// BatchX25519FromPoints returns a *BatchError along with the other results.
+func BatchX25519FromPoints(points []*Point) ([][]byte, error) {
+	checkInitialized(points...)
+	errs := make([]error, len(points))
+	return batchX25519(points, errs)
+}
+
+// BatchX25519FromPublicKeys is like BatchX25519FromPoints, but takes Ed25519
+// public keys, which are decoded like in PointFromPublicKey. Invalid encodings
+// are rejected like small order points.
+func BatchX25519FromPublicKeys(pubs []ed25519.PublicKey) ([][]byte, error) {
+	points := make([]*Point, len(pubs))
+	errs := make([]error, len(pubs))
+	for i, pub := range pubs {
+		points[i], errs[i] = PointFromPublicKey(pub)
+	}
+	return batchX25519(points, errs)
+}
+
+// batchX25519 converts the points with a nil entry in errs, and fills in errs
+// for the small order ones.
+func batchX25519(points []*Point, errs []error) ([][]byte, error) {
+	// u = (1 + y) / (1 - y) = (Z + Y) / (Z - Y)
+	dens := make([]fieldElement, len(points))
+	for i, p := range points {
+		if errs[i] != nil {
+			continue
+		}
+		if p.varTimeIsIdentity() {
+			errs[i] = errors.New("edwards25519: identity point has no X25519 public key")
+			continue
+		}
+		if (&Point{}).MultByCofactor(p).varTimeIsIdentity() {
+			errs[i] = errors.New("edwards25519: small order point has no X25519 public key")
+			continue
+		}
+		dens[i].Subtract(&p.z, &p.y)
+	}
+	invertBatch(dens)
+
+	out := make([][]byte, len(points))
+	failed := false
+	var u fieldElement
+	for i, p := range points {
+		if errs[i] != nil {
+			failed = true
+			continue
+		}
+		u.Add(&p.z, &p.y)
+		u.Multiply(&u, &dens[i])
+		out[i] = u.Bytes()
+	}
+	if failed {
+		return out, &BatchError{Errors: errs}
+	}
+	return out, nil
+}
+
+// X25519PrivateKeyFromSeed returns the X25519 private key corresponding to
+// the Ed25519 private key seed, such that its public key is the one returned
+// by X25519PublicKeyFromPoint for the Ed25519 public key.
+//
+// The returned key is the clamped first half of SHA-512(seed), which is also
+// the input of SetBytesWithClamping that produces the Ed25519 secret scalar
+// returned by ScalarAndPrefixFromSeed. Since the Scalar is reduced modulo l,
+// the key can't be recovered from it, and multiplying a point by the Scalar
+// matches X25519 with the key only for points in the prime order subgroup.
+// X25519 also multiplies away any small order component of the peer point,
+// because the clamped key is a multiple of the cofactor.
+func X25519PrivateKeyFromSeed(seed []byte) ([]byte, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, errors.New("edwards25519: invalid seed length")
+	}
+	h := sha512.Sum512(seed)
+	key := make([]byte, 32)
+	copy(key, h[:32])
+	ClampBytes(key)
+	return key, nil
+}
+
+// X25519 returns the result of the X25519 function of RFC 7748 with the given
+// scalar and u-coordinate, both 32 bytes long. The scalar is clamped, and the
+// high bit of u is ignored, as specified.
+//
+// X25519 returns an error if the inputs have the wrong length, or if the
+// result is the all-zero value, which happens when u is a low order point.
+// For the canonical base point, X25519ScalarBaseMult is faster.
+func X25519(scalar, u []byte) ([]byte, error) {
+	if len(scalar) != 32 {
+		return nil, errors.New("edwards25519: invalid X25519 scalar length")
+	}
+	if len(u) != 32 {
+		return nil, errors.New("edwards25519: invalid X25519 point length")
+	}
+
+	var k [32]byte
+	copy(k[:], scalar)
+	ClampBytes(k[:])
+
+	var x1, x2, z2, x3, z3, tmp0, tmp1 fieldElement
+	x1.SetBytes(u)
+	x2.One()
+	x3.Set(&x1)
+	z3.One()
+
+	// The Montgomery ladder from RFC 7748, Section 5, with conditional swaps
+	// that only depend on the bits of the scalar.
+	swap := 0
+	for pos := 254; pos >= 0; pos-- {
+		b := int(k[pos/8]>>uint(pos&7)) & 1
+		swap ^= b
+		x2.Swap(&x3, swap)
+		z2.Swap(&z3, swap)
+		swap = b
+
+		tmp0.Subtract(&x3, &z3)     // D = x3 - z3
+		tmp1.Subtract(&x2, &z2)     // B = x2 - z2
+		x2.Add(&x2, &z2)            // A = x2 + z2
+		z2.Add(&x3, &z3)            // C = x3 + z3
+		z3.Multiply(&tmp0, &x2)     // DA = D * A
+		z2.Multiply(&z2, &tmp1)     // CB = C * B
+		tmp0.Square(&tmp1)          // BB = B^2
+		tmp1.Square(&x2)            // AA = A^2
+		x3.Add(&z3, &z2)            // DA + CB
+		z2.Subtract(&z3, &z2)       // DA - CB
+		x2.Multiply(&tmp1, &tmp0)   // x2 = AA * BB
+		tmp1.Subtract(&tmp1, &tmp0) // E = AA - BB
+		z2.Square(&z2)              // (DA - CB)^2
+		z3.Mult32(&tmp1, 121666)    // a24 * E, with a24 = (A + 2) / 4
+		x3.Square(&x3)              // x3 = (DA + CB)^2
+		tmp0.Add(&tmp0, &z3)        // BB + a24 * E
+		z3.Multiply(&x1, &z2)       // z3 = x1 * (DA - CB)^2
+		z2.Multiply(&tmp1, &tmp0)   // z2 = E * (BB + a24 * E)
+	}
+	x2.Swap(&x3, swap)
+	z2.Swap(&z3, swap)
+
+	z2.Invert(&z2)
+	x2.Multiply(&x2, &z2)
+	out := x2.Bytes()
+
+	var zero [32]byte
+	if subtle.ConstantTimeCompare(out, zero[:]) == 1 {
+		return nil, errors.New("edwards25519: X25519 produced the all-zero value")
+	}
+	return out, nil
+}
+
+// X25519ScalarBaseMult returns the result of X25519 with the given scalar and
+// the canonical Curve25519 base point, u = 9. It is computed on the Edwards
+// curve with the fixed-base tables, and converted with BytesMontgomery.
+func X25519ScalarBaseMult(scalar []byte) ([]byte, error) {
+	if len(scalar) != 32 {
+		return nil, errors.New("edwards25519: invalid X25519 scalar length")
+	}
+
+	// Reducing the clamped scalar modulo l doesn't change the result, because
+	// the base point has order l.
+	s := NewScalar().SetBytesWithClamping(scalar)
+	return (&Point{}).ScalarBaseMult(s).BytesMontgomery(), nil
+}
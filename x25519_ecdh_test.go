@@ -0,0 +1,145 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build go1.20
+
+package edwards25519
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/sha512"
+	mathrand "math/rand"
+	"testing"
+	"testing/quick"
+)
+
+// The tests in this file compare against crypto/ecdh, added in Go 1.20.
+
+func TestX25519PublicKeyFromPointECDH(t *testing.T) {
+	publicKeyMatches := func(seed [32]byte) bool {
+		h := sha512.Sum512(seed[:])
+		p := (&Point{}).ScalarBaseMult(NewScalar().SetBytesWithClamping(h[:32]))
+		u, err := X25519PublicKeyFromPoint(p)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+
+		// crypto/ecdh clamps the private key as part of X25519.
+		priv, err := ecdh.X25519().NewPrivateKey(h[:32])
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		return bytes.Equal(u, priv.PublicKey().Bytes())
+	}
+	if err := quick.Check(publicKeyMatches, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestX25519PrivateKeyFromSeed(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	var privs []*ecdh.PrivateKey
+	var pubs []*ecdh.PublicKey
+	for i := 0; i < 2; i++ {
+		edPub, edPriv, err := ed25519.GenerateKey(rand)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key, err := X25519PrivateKeyFromSeed(edPriv.Seed())
+		if err != nil {
+			t.Fatal(err)
+		}
+		priv, err := ecdh.X25519().NewPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		A, err := PointFromPublicKey(edPub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := X25519PublicKeyFromPoint(A)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := ecdh.X25519().NewPublicKey(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pub.Equal(priv.PublicKey()) {
+			t.Error("converted public key does not match the converted private key")
+		}
+
+		// The clamped key is the input of the Ed25519 secret scalar.
+		s, _, err := ScalarAndPrefixFromSeed(edPriv.Seed())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if NewScalar().SetBytesWithClamping(key).Equal(s) != 1 {
+			t.Error("clamped key does not match the Ed25519 secret scalar")
+		}
+
+		// Multiplying by the Scalar matches X25519 in the prime order subgroup.
+		var seed [64]byte
+		rand.Read(seed[:])
+		P := (&Point{}).ScalarBaseMult(NewScalar().SetUniformBytes(seed[:]))
+		peer, err := ecdh.X25519().NewPublicKey(P.BytesMontgomery())
+		if err != nil {
+			t.Fatal(err)
+		}
+		shared, err := priv.ECDH(peer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal((&Point{}).ScalarMult(s, P).BytesMontgomery(), shared) {
+			t.Error("Scalar multiplication does not match X25519")
+		}
+
+		// The Ed25519 key still signs as usual.
+		msg := []byte("message")
+		if !ed25519.Verify(edPub, msg, ed25519.Sign(edPriv, msg)) {
+			t.Error("Ed25519 signature is invalid")
+		}
+
+		privs = append(privs, priv)
+		pubs = append(pubs, pub)
+	}
+
+	// Both sides of the converted key pairs agree.
+	shared0, err := privs[0].ECDH(pubs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	shared1, err := privs[1].ECDH(pubs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(shared0, shared1) {
+		t.Error("X25519 between converted key pairs does not agree")
+	}
+
+	if _, err := X25519PrivateKeyFromSeed(make([]byte, 31)); err == nil {
+		t.Error("expected an error for a short seed")
+	}
+}
+
+func TestX25519ECDH(t *testing.T) {
+	x25519Matches := func(scalar, u [32]byte) bool {
+		out, err := X25519(scalar[:], u[:])
+		priv, _ := ecdh.X25519().NewPrivateKey(scalar[:])
+		pub, _ := ecdh.X25519().NewPublicKey(u[:])
+		expected, expectedErr := priv.ECDH(pub)
+		if err != nil || expectedErr != nil {
+			return (err != nil) == (expectedErr != nil)
+		}
+		return bytes.Equal(out, expected)
+	}
+	if err := quick.Check(x25519Matches, nil); err != nil {
+		t.Error(err)
+	}
+}
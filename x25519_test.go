@@ -0,0 +1,254 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"flag"
+	mathrand "math/rand"
+	"testing"
+	"testing/quick"
+)
+
+var x25519MillionIterations = flag.Bool("x25519-million", false, "run the 1,000,000 iterations X25519 test vector")
+
+func TestX25519PublicKeyFromPoint(t *testing.T) {
+	publicKeyMatches := func(seed [32]byte) bool {
+		h := sha512.Sum512(seed[:])
+		p := (&Point{}).ScalarBaseMult(NewScalar().SetBytesWithClamping(h[:32]))
+		u, err := X25519PublicKeyFromPoint(p)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+
+		// X25519 clamps the scalar, and uses the Montgomery ladder rather
+		// than the birational map.
+		basepoint := make([]byte, 32)
+		basepoint[0] = 9
+		expected, err := X25519(h[:32], basepoint)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		return bytes.Equal(u, expected)
+	}
+	if err := quick.Check(publicKeyMatches, nil); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := X25519PublicKeyFromPoint(NewIdentityPoint()); err == nil {
+		t.Error("expected an error for the identity")
+	}
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 8; i++ {
+		p := (&Point{}).Set(lowOrder)
+		for j := 0; j < i; j++ {
+			p.Add(p, lowOrder)
+		}
+		if _, err := X25519PublicKeyFromPoint(p); err == nil {
+			t.Errorf("expected an error for %d times a point of order 8", i+1)
+		}
+	}
+}
+
+func TestBatchX25519FromPoints(t *testing.T) {
+	rand := mathrand.New(mathrand.NewSource(0))
+	var points []*Point
+	var pubs []ed25519.PublicKey
+	for i := 0; i < 50; i++ {
+		var seed [64]byte
+		rand.Read(seed[:])
+		p := (&Point{}).ScalarBaseMult(NewScalar().SetUniformBytes(seed[:]))
+		points = append(points, p)
+		pubs = append(pubs, p.Bytes())
+	}
+
+	lowOrder, err := (&Point{}).SetBytes(decodeHex("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc85"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	points[3] = NewIdentityPoint()
+	points[17] = lowOrder
+	pubs[3] = NewIdentityPoint().Bytes()
+	pubs[17] = lowOrder.Bytes()
+	pubs[40] = decodeHex("0200000000000000000000000000000000000000000000000000000000000000")
+	pubs[41] = pubs[41][:31]
+
+	check := func(name string, out [][]byte, err error, rejected ...int) {
+		batchErr, ok := err.(*BatchError)
+		if !ok {
+			t.Fatalf("%s: expected a *BatchError, got %v", name, err)
+		}
+		for i := range out {
+			isRejected := false
+			for _, r := range rejected {
+				isRejected = isRejected || i == r
+			}
+			if isRejected {
+				if out[i] != nil || batchErr.Errors[i] == nil {
+					t.Errorf("%s: input %d was not rejected", name, i)
+				}
+				continue
+			}
+			if batchErr.Errors[i] != nil {
+				t.Errorf("%s: input %d was rejected: %v", name, i, batchErr.Errors[i])
+			}
+			expected, err := X25519PublicKeyFromPoint(points[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(out[i], expected) {
+				t.Errorf("%s: input %d does not match X25519PublicKeyFromPoint", name, i)
+			}
+		}
+	}
+	out, err := BatchX25519FromPoints(points)
+	check("BatchX25519FromPoints", out, err, 3, 17)
+	out, err = BatchX25519FromPublicKeys(pubs)
+	check("BatchX25519FromPublicKeys", out, err, 3, 17, 40, 41)
+
+	out, err = BatchX25519FromPoints(points[:3])
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for i := range out {
+		expected, _ := X25519PublicKeyFromPoint(points[i])
+		if !bytes.Equal(out[i], expected) {
+			t.Errorf("input %d does not match X25519PublicKeyFromPoint", i)
+		}
+	}
+}
+
+func BenchmarkBatchX25519FromPoints(b *testing.B) {
+	points := make([]*Point, 100000)
+	p := NewGeneratorPoint()
+	for i := range points {
+		points[i] = (&Point{}).Set(p)
+		p.Add(p, B)
+	}
+	b.Run("Batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := BatchX25519FromPoints(points); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, p := range points {
+				if _, err := X25519PublicKeyFromPoint(p); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
+
+func TestX25519(t *testing.T) {
+	// Test vectors from RFC 7748, Section 5.2.
+	vectors := []struct{ scalar, u, out string }{
+		{
+			"a546e36bf0527c9d3b16154b82465edd62144c0ac1fc5a18506a2244ba449ac4",
+			"e6db6867583030db3594c1a424b15f7c726624ec26b3353b10a903a6d0ab1c4c",
+			"c3da55379de9c6908e94ea4df28d084f32eccf03491c71f754b4075577a28552",
+		},
+		{
+			"4b66e9d4d1b4673c5ad22691957d6af5c11b6421e0ea01d42ca4169e7918ba0d",
+			"e5210f12786811d3f4b7959d0538ae2c31dbe7106fc03c3efc4cd549c715a493",
+			"95cbde9476e8907d7aade45cb4b873f88b595a68799fa152e6f8f7647aac7957",
+		},
+	}
+	for _, v := range vectors {
+		out, err := X25519(decodeHex(v.scalar), decodeHex(v.u))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(out); got != v.out {
+			t.Errorf("got %s, expected %s", got, v.out)
+		}
+	}
+
+	baseMultMatches := func(scalar [32]byte) bool {
+		out, err := X25519ScalarBaseMult(scalar[:])
+		if err != nil {
+			return false
+		}
+		basepoint := make([]byte, 32)
+		basepoint[0] = 9
+		expected, err := X25519(scalar[:], basepoint)
+		return err == nil && bytes.Equal(out, expected)
+	}
+	if err := quick.Check(baseMultMatches, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestX25519Iterated(t *testing.T) {
+	// Iterated test vectors from RFC 7748, Section 5.2.
+	iterations := 1000
+	expected := "684cf59ba83309552800ef566f2f4d3c1c3887c49360e3875f2eb94d99532c51"
+	if testing.Short() {
+		iterations = 1
+		expected = "422c8e7a6227d7bca1350b3e2bb7279f7897b87bb6854b783c60e80311ae3079"
+	} else if *x25519MillionIterations {
+		iterations = 1000000
+		expected = "7c3911e0ab2586fd864497297e575e6f3bc601c0883c30df5f4dd2d24f665424"
+	}
+
+	k := make([]byte, 32)
+	k[0] = 9
+	u := append([]byte{}, k...)
+	for i := 0; i < iterations; i++ {
+		out, err := X25519(k, u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, u = out, k
+	}
+	if got := hex.EncodeToString(k); got != expected {
+		t.Errorf("after %d iterations got %s, expected %s", iterations, got, expected)
+	}
+}
+
+func TestX25519LowOrder(t *testing.T) {
+	// u = 0 and u = 1 are low order points, and so is u = p + 1, a
+	// non-canonical encoding of 1 that also has the high bit set.
+	lowOrder := []string{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"0100000000000000000000000000000000000000000000000000000000000000",
+		"eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	}
+	scalar := decodeHex("a546e36bf0527c9d3b16154b82465edd62144c0ac1fc5a18506a2244ba449ac4")
+	for _, u := range lowOrder {
+		if _, err := X25519(scalar, decodeHex(u)); err == nil {
+			t.Errorf("u = %s: expected an error", u)
+		}
+	}
+	if _, err := X25519(scalar[:31], decodeHex(lowOrder[1])); err == nil {
+		t.Error("expected an error for a short scalar")
+	}
+}
+
+func BenchmarkX25519(b *testing.B) {
+	scalar := decodeHex("a546e36bf0527c9d3b16154b82465edd62144c0ac1fc5a18506a2244ba449ac4")
+	u := decodeHex("e6db6867583030db3594c1a424b15f7c726624ec26b3353b10a903a6d0ab1c4c")
+	b.Run("X25519", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			X25519(scalar, u)
+		}
+	})
+	b.Run("X25519ScalarBaseMult", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			X25519ScalarBaseMult(scalar)
+		}
+	})
+}
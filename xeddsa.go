@@ -0,0 +1,84 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+)
+
+// The XEd25519 functions convert X25519 keys to Ed25519 keys as specified in
+// Signal's XEdDSA specification, Section 2.3, so that X25519 keys can produce
+// and verify Ed25519 signatures.
+//
+// An X25519 public key, a u-coordinate, corresponds to two Edwards points,
+// which differ only in the sign bit of their encoding. XEdDSA always uses the
+// one with sign bit 0, and negates the private scalar when its public point
+// has sign bit 1, so that the two sides agree. This matches calculate_key_pair
+// and convert_mont in libsignal.
+
+// EdwardsFromXPublicKey returns the Edwards point with sign bit 0 that
+// corresponds to the X25519 public key u, as in the convert_mont function of
+// XEdDSA. The high bit of u is ignored, as in X25519.
+//
+// It returns an error if u is not 32 bytes, if u is not canonical (as
+// xeddsa_verify does), or if u doesn't correspond to a point on the curve
+// (for example because it's on the twist).
+func EdwardsFromXPublicKey(u []byte) (*Point, error) {
+	if len(u) != 32 {
+		return nil, errors.New("edwards25519: invalid X25519 public key length")
+	}
+	uf := new(fieldElement).SetBytes(u)
+	masked := append([]byte{}, u...)
+	masked[31] &= 127
+	if !bytes.Equal(uf.Bytes(), masked) {
+		return nil, errors.New("edwards25519: non-canonical X25519 public key")
+	}
+
+	// y = (u - 1) / (u + 1)
+	var num, den fieldElement
+	num.Subtract(uf, feOne)
+	den.Add(uf, feOne)
+	if den.Equal(feZero) == 1 {
+		return nil, errors.New("edwards25519: X25519 public key has no Edwards equivalent")
+	}
+	y := new(fieldElement).Multiply(&num, den.Invert(&den))
+	return (&Point{}).SetBytes(y.Bytes())
+}
+
+// CalculateKeyPairFromX returns the Ed25519 secret scalar and public point of
+// the X25519 private key priv, as in the calculate_key_pair function of
+// XEdDSA. priv is clamped as in X25519. The public point is the one with sign
+// bit 0, which is EdwardsFromXPublicKey of the X25519 public key of priv, and
+// the scalar is negated if needed to match it.
+//
+// Ed25519 signatures by the returned scalar verify under the returned point.
+//
+// The computation is done in constant time.
+func CalculateKeyPairFromX(priv []byte) (*Scalar, *Point, error) {
+	if len(priv) != 32 {
+		return nil, nil, errors.New("edwards25519: invalid X25519 private key length")
+	}
+	k := NewScalar().SetBytesWithClamping(priv)
+	E := (&Point{}).ScalarBaseMult(k)
+	enc := E.Bytes()
+	sign := int(enc[31] >> 7)
+
+	a := k.Bytes()
+	subtle.ConstantTimeCopy(sign, a, NewScalar().Negate(k).Bytes())
+	scalar, err := NewScalar().SetCanonicalBytes(a)
+	if err != nil {
+		panic("edwards25519: internal error: scalar is not canonical")
+	}
+
+	// -E has the same y-coordinate and the opposite sign bit.
+	enc[31] &= 127
+	A, err := (&Point{}).SetBytes(enc)
+	if err != nil {
+		panic("edwards25519: internal error: invalid point encoding")
+	}
+	return scalar, A, nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edwards25519
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"testing"
+)
+
+// signWithScalar produces an Ed25519 signature of msg by the secret scalar a
+// with public point A, with a nonce derived from a and msg.
+func signWithScalar(a *Scalar, A *Point, msg []byte) []byte {
+	h := sha512.New()
+	h.Write(a.Bytes())
+	h.Write(msg)
+	r := NewScalar().SetUniformBytes(h.Sum(nil))
+	R := (&Point{}).ScalarBaseMult(r)
+	h.Reset()
+	h.Write(R.Bytes())
+	h.Write(A.Bytes())
+	h.Write(msg)
+	k := NewScalar().SetUniformBytes(h.Sum(nil))
+	S := NewScalar().MultiplyAdd(k, a, r)
+	return append(R.Bytes(), S.Bytes()...)
+}
+
+func TestCalculateKeyPairFromX(t *testing.T) {
+	// Cover keys whose natural Edwards point has sign bit 0, and 1.
+	seen := map[int]int{}
+	for i := 0; seen[0] < 4 || seen[1] < 4; i++ {
+		priv := make([]byte, 32)
+		priv[0], priv[1] = byte(i), byte(i>>8)
+		natural := (&Point{}).ScalarBaseMult(NewScalar().SetBytesWithClamping(priv))
+		sign := int(natural.Bytes()[31] >> 7)
+		seen[sign]++
+
+		a, A, err := CalculateKeyPairFromX(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if A.Bytes()[31]>>7 != 0 {
+			t.Errorf("key %d: public point has sign bit 1", i)
+		}
+		if (&Point{}).ScalarBaseMult(a).Equal(A) != 1 {
+			t.Errorf("key %d: scalar does not match public point", i)
+		}
+		if sign == 0 && A.Equal(natural) != 1 || sign == 1 && A.Equal(natural) == 1 {
+			t.Errorf("key %d: unexpected public point for sign bit %d", i, sign)
+		}
+
+		// The X25519 public key converts to the same point.
+		u, err := X25519ScalarBaseMult(priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		P, err := EdwardsFromXPublicKey(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if P.Equal(A) != 1 {
+			t.Errorf("key %d: converted X25519 public key does not match", i)
+		}
+
+		// Signatures by the scalar verify with standard Ed25519 under the
+		// converted X25519 public key.
+		msg := []byte("XEd25519 message")
+		sig := signWithScalar(a, A, msg)
+		if !ed25519.Verify(P.Bytes(), msg, sig) {
+			t.Errorf("key %d (sign bit %d): signature does not verify", i, sign)
+		}
+	}
+}
+
+func TestEdwardsFromXPublicKeyErrors(t *testing.T) {
+	// p - 1 is -1, whose Edwards y-coordinate would be a division by zero.
+	minusOne := decodeHex("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	// p + 1 is a non-canonical encoding of 1.
+	nonCanonical := decodeHex("eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	// u = 2 is on the twist.
+	twist := make([]byte, 32)
+	twist[0] = 2
+	for _, tt := range []struct {
+		name string
+		u    []byte
+	}{
+		{"short", make([]byte, 31)},
+		{"minus one", minusOne},
+		{"non-canonical", nonCanonical},
+		{"twist", twist},
+	} {
+		if _, err := EdwardsFromXPublicKey(tt.u); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+
+	// The high bit is ignored.
+	u := B.BytesMontgomery()
+	u[31] |= 128
+	P, err := EdwardsFromXPublicKey(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if P.Equal(B) != 1 {
+		t.Errorf("basepoint u-coordinate did not convert to the basepoint")
+	}
+
+	if _, _, err := CalculateKeyPairFromX(make([]byte, 33)); err == nil {
+		t.Errorf("expected error for long private key")
+	}
+}